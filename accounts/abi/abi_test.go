@@ -696,3 +696,107 @@ func TestABI_MethodById(t *testing.T) {
 	}
 
 }
+
+// Tests that nested, dynamic tuples and arrays of them are packed according to
+// the ABI spec and unpacked into Go structs, matching the fields by name or order.
+func TestTuplePackUnpack(t *testing.T) {
+	const definition = `[{"type":"function","name":"f",
+		"inputs":[{"name":"items","type":"tuple[]","components":[{"name":"id","type":"uint256"},{"name":"inner","type":"tuple","components":[{"name":"data","type":"bytes"},{"name":"flags","type":"bool[2]"}]}]}],
+		"outputs":[{"name":"items","type":"tuple[]","components":[{"name":"id","type":"uint256"},{"name":"inner","type":"tuple","components":[{"name":"data","type":"bytes"},{"name":"flags","type":"bool[2]"}]}]}]}]`
+
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig := abi.Methods["f"].Sig(); sig != "f((uint256,(bytes,bool[2]))[])" {
+		t.Fatalf("signature mismatch: have %s, want f((uint256,(bytes,bool[2]))[])", sig)
+	}
+	type inner struct {
+		Data  []byte
+		Flags [2]bool
+	}
+	type item struct {
+		Id    *big.Int
+		Inner inner
+	}
+	items := []item{{Id: big.NewInt(1), Inner: inner{Data: []byte{0x01, 0x02}, Flags: [2]bool{true, false}}}}
+
+	packed, err := abi.Methods["f"].Inputs.Pack(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := common.Hex2Bytes("" +
+		"0000000000000000000000000000000000000000000000000000000000000020" + // offset of items
+		"0000000000000000000000000000000000000000000000000000000000000001" + // length of items
+		"0000000000000000000000000000000000000000000000000000000000000020" + // offset of items[0]
+		"0000000000000000000000000000000000000000000000000000000000000001" + // items[0].id
+		"0000000000000000000000000000000000000000000000000000000000000040" + // offset of items[0].inner
+		"0000000000000000000000000000000000000000000000000000000000000060" + // offset of items[0].inner.data
+		"0000000000000000000000000000000000000000000000000000000000000001" + // items[0].inner.flags[0]
+		"0000000000000000000000000000000000000000000000000000000000000000" + // items[0].inner.flags[1]
+		"0000000000000000000000000000000000000000000000000000000000000002" + // length of items[0].inner.data
+		"0102000000000000000000000000000000000000000000000000000000000000") // items[0].inner.data
+	if !bytes.Equal(packed, want) {
+		t.Fatalf("packed tuple mismatch:\nhave %x\nwant %x", packed, want)
+	}
+	// Unpack into the same structs, matched by field name
+	var named []item
+	if err := abi.Unpack(&named, "f", packed); err != nil {
+		t.Fatalf("failed to unpack by field name: %v", err)
+	}
+	if !reflect.DeepEqual(named, items) {
+		t.Errorf("unpacked tuple mismatch: have %+v, want %+v", named, items)
+	}
+	// Unpack into structs with different field names, matched by order
+	var ordered []struct {
+		Number *big.Int
+		Nested struct {
+			Blob []byte
+			Bits [2]bool
+		}
+	}
+	if err := abi.Unpack(&ordered, "f", packed); err != nil {
+		t.Fatalf("failed to unpack by field order: %v", err)
+	}
+	if len(ordered) != 1 || ordered[0].Number.Cmp(big.NewInt(1)) != 0 || !bytes.Equal(ordered[0].Nested.Blob, []byte{0x01, 0x02}) || ordered[0].Nested.Bits != [2]bool{true, false} {
+		t.Errorf("unpacked tuple mismatch: have %+v", ordered)
+	}
+	// Structs matching the components neither by name nor by order are rejected
+	var invalid []struct{ Id *big.Int }
+	if err := abi.Unpack(&invalid, "f", packed); err == nil {
+		t.Errorf("unpacked tuple into mismatching struct")
+	}
+}
+
+// Tests that static tuples are encoded in place, both as arguments and as
+// elements of static arrays.
+func TestStaticTuplePackUnpack(t *testing.T) {
+	const definition = `[{"type":"function","name":"f",
+		"inputs":[{"name":"points","type":"tuple[2]","components":[{"name":"x","type":"uint64"},{"name":"y","type":"uint64"}]},{"name":"z","type":"uint8"}],
+		"outputs":[{"name":"points","type":"tuple[2]","components":[{"name":"x","type":"uint64"},{"name":"y","type":"uint64"}]},{"name":"z","type":"uint8"}]}]`
+
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type point struct{ X, Y uint64 }
+	points := [2]point{{1, 2}, {3, 4}}
+
+	packed, err := abi.Pack("f", points, uint8(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packed) != 4+5*32 || packed[4+31] != 1 || packed[4+4*32+31] != 5 {
+		t.Fatalf("packed tuple mismatch: have %x", packed)
+	}
+	var out struct {
+		Points [2]point
+		Z      uint8
+	}
+	if err := abi.Unpack(&out, "f", packed[4:]); err != nil {
+		t.Fatalf("failed to unpack: %v", err)
+	}
+	if out.Points != points || out.Z != 5 {
+		t.Errorf("unpacked tuple mismatch: have %+v", out)
+	}
+}
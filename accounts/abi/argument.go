@@ -33,19 +33,24 @@ type Argument struct {
 
 type Arguments []Argument
 
+// ArgumentMarshaling is the JSON representation of an argument, with tuple
+// types listing their components recursively.
+type ArgumentMarshaling struct {
+	Name       string
+	Type       string
+	Components []ArgumentMarshaling
+	Indexed    bool
+}
+
 // UnmarshalJSON implements json.Unmarshaler interface
 func (argument *Argument) UnmarshalJSON(data []byte) error {
-	var extarg struct {
-		Name    string
-		Type    string
-		Indexed bool
-	}
+	var extarg ArgumentMarshaling
 	err := json.Unmarshal(data, &extarg)
 	if err != nil {
 		return fmt.Errorf("argument json err: %v", err)
 	}
 
-	argument.Type, err = NewType(extarg.Type)
+	argument.Type, err = newType(extarg.Type, extarg.Components)
 	if err != nil {
 		return err
 	}
@@ -111,7 +116,7 @@ func (arguments Arguments) unpackTuple(v interface{}, output []byte) error {
 		}
 	}
 	// `i` counts the nonindexed arguments.
-	// `j` counts the number of complex types.
+	// `j` counts the number of additional words of static arrays and tuples.
 	// both `i` and `j` are used to to correctly compute `data` offset.
 
 	i, j := -1, 0
@@ -127,10 +132,10 @@ func (arguments Arguments) unpackTuple(v interface{}, output []byte) error {
 			return err
 		}
 
-		if arg.Type.T == ArrayTy {
-			// combined index ('i' + 'j') need to be adjusted only by size of array, thus
-			// we need to decrement 'j' because 'i' was incremented
-			j += arg.Type.Size - 1
+		if !isDynamicType(arg.Type) && (arg.Type.T == ArrayTy || arg.Type.T == TupleTy) {
+			// combined index ('i' + 'j') need to be adjusted only by the size of the
+			// encoding, thus we need to decrement 'j' because 'i' was incremented
+			j += getTypeSize(arg.Type)/32 - 1
 		}
 
 		reflectValue := reflect.ValueOf(marshalledValue)
@@ -183,6 +188,12 @@ func (arguments Arguments) unpackAtomic(v interface{}, output []byte) error {
 	if err != nil {
 		return err
 	}
+	// Event structs carry the lone non-indexed argument in a named field
+	if value.Kind() == reflect.Struct {
+		if field := value.FieldByName(capitalise(arg.Name)); field.IsValid() {
+			value = field
+		}
+	}
 	return set(value, reflect.ValueOf(marshalledValue), arg)
 }
 
@@ -201,11 +212,7 @@ func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
 	// input offset is the bytes offset for packed output
 	inputOffset := 0
 	for _, abiArg := range abiArgs {
-		if abiArg.Type.T == ArrayTy {
-			inputOffset += 32 * abiArg.Type.Size
-		} else {
-			inputOffset += 32
-		}
+		inputOffset += getTypeSize(abiArg.Type)
 	}
 
 	var ret []byte
@@ -217,8 +224,8 @@ func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
 			return nil, err
 		}
 
-		// check for a slice type (string, bytes, slice)
-		if input.Type.requiresLengthPrefix() {
+		// check for a dynamic type (string, bytes, slice, or arrays and tuples of them)
+		if isDynamicType(input.Type) {
 			// calculate the offset
 			offset := inputOffset + len(variableInput)
 			// set the offset
@@ -16,12 +16,18 @@
 
 // Package bind generates Ethereum contract Go bindings.
 //
+// Bindings for other languages are generated from template sets, each consisting
+// of a text/template source and a number of hooks mapping Solidity types to the
+// types of the target language. The built-in sets can be retrieved and altered
+// via LangTemplate, or entirely new ones supplied to BindTemplate.
+//
 // Detailed usage document and tutorial available on the go-ethereum Wiki page:
 // https://github.com/ethereum/go-ethereum/wiki/Native-DApps:-Go-bindings-to-Ethereum-contracts
 package bind
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -39,13 +45,112 @@ const (
 	LangGo Lang = iota
 	LangJava
 	LangObjC
+	LangTypeScript
 )
 
+// Template is a set of templates and hooks generating contract bindings for a
+// target programming language.
+//
+// The source is rendered with the contracts to bind, see tmplData for the exact
+// fields available. Besides the standard text/template functions, the source
+// may call bindtype, bindtopictype and namedtype, which invoke the hooks of the
+// same names, as well as capitalise and decapitalise and any custom functions.
+type Template struct {
+	// Source is the text/template source generating the binding.
+	Source string
+
+	// BindType is the type mapping hook converting a Solidity type to the type
+	// of the target language used for method arguments and returns, events and
+	// their fields. Since there is usually no exact mapping for all Solidity types
+	// (e.g. uint17), those that cannot be exactly mapped should use an upscaled
+	// type (e.g. a big integer).
+	BindType func(kind abi.Type) string
+
+	// BindTopicType converts a Solidity type to the type of the target language
+	// used to filter on indexed event fields. Dynamic types are usually converted
+	// to hashes, as the topics only contain their hash. Defaults to BindType.
+	BindTopicType func(kind abi.Type) string
+
+	// NamedType converts a bound type of the target language to a variant usable
+	// within method names. Defaults to the bound type as is.
+	NamedType func(bound string, kind abi.Type) string
+
+	// NormalizeName converts the Solidity names of methods and events to the
+	// naming conventions of the target language. Defaults to the names as is.
+	NormalizeName func(name string) string
+
+	// Funcs are additional functions made available to the template source,
+	// overriding the built-in ones if they share names.
+	Funcs template.FuncMap
+
+	// Format optionally post-processes the rendered binding, e.g. to format it
+	// or to double check its validity.
+	Format func(code []byte) ([]byte, error)
+}
+
+// templates is the language to template set mapping containing all the supported
+// programming languages the package can generate to.
+var templates = map[Lang]*Template{
+	LangGo: {
+		Source:        tmplSourceGo,
+		BindType:      bindTypeGo,
+		BindTopicType: bindTopicTypeGo,
+		NamedType:     func(string, abi.Type) string { panic("this shouldn't be needed") },
+		NormalizeName: capitalise,
+		Format: func(code []byte) ([]byte, error) {
+			return imports.Process(".", code, nil)
+		},
+	},
+	LangJava: {
+		Source:        tmplSourceJava,
+		BindType:      bindTypeJava,
+		BindTopicType: bindTopicTypeJava,
+		NamedType:     namedTypeJava,
+		NormalizeName: decapitalise,
+	},
+	LangTypeScript: {
+		Source:        tmplSourceTypeScript,
+		BindType:      bindTypeTypeScript,
+		BindTopicType: bindTopicTypeTypeScript,
+		NormalizeName: decapitalise,
+	},
+}
+
+// LangTemplate returns a copy of the built-in template set of a language, which
+// may be customized and passed to BindTemplate.
+func LangTemplate(lang Lang) (*Template, error) {
+	tmpl, ok := templates[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported binding language: %d", lang)
+	}
+	cpy := *tmpl
+	return &cpy, nil
+}
+
 // Bind generates a Go wrapper around a contract ABI. This wrapper isn't meant
 // to be used as is in client code, but rather as an intermediate struct which
 // enforces compile time type safety and naming convention opposed to having to
 // manually maintain hard coded strings that break on runtime.
+//
+// Bindings for other languages are generated by selecting a different lang.
 func Bind(types []string, abis []string, bytecodes []string, pkg string, lang Lang) (string, error) {
+	tmpl, err := LangTemplate(lang)
+	if err != nil {
+		return "", err
+	}
+	return BindTemplate(types, abis, bytecodes, pkg, tmpl)
+}
+
+// BindTemplate generates a wrapper around a contract ABI using the given template
+// set, allowing bindings to be generated for arbitrary languages and styles.
+func BindTemplate(types []string, abis []string, bytecodes []string, pkg string, tmpl *Template) (string, error) {
+	if tmpl.BindType == nil {
+		return "", errors.New("no type binder in template set")
+	}
+	normalize := tmpl.NormalizeName
+	if normalize == nil {
+		normalize = func(name string) string { return name }
+	}
 	// Process each individual contract requested binding
 	contracts := make(map[string]*tmplContract)
 
@@ -71,7 +176,7 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 		for _, original := range evmABI.Methods {
 			// Normalize the method for capital cases and non-anonymous inputs/outputs
 			normalized := original
-			normalized.Name = methodNormalizer[lang](original.Name)
+			normalized.Name = normalize(original.Name)
 
 			normalized.Inputs = make([]abi.Argument, len(original.Inputs))
 			copy(normalized.Inputs, original.Inputs)
@@ -94,6 +199,26 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 				transacts[original.Name] = &tmplMethod{Original: original, Normalized: normalized, Structured: structured(original)}
 			}
 		}
+		events := make(map[string]*tmplEvent)
+		for _, original := range evmABI.Events {
+			// Skip anonymous events as they don't support explicit filtering
+			if original.Anonymous {
+				continue
+			}
+			// Normalize the event for capital cases and non-anonymous outputs
+			normalized := original
+			normalized.Name = normalize(original.Name)
+
+			normalized.Inputs = make([]abi.Argument, len(original.Inputs))
+			copy(normalized.Inputs, original.Inputs)
+			for j, input := range normalized.Inputs {
+				if input.Name == "" {
+					normalized.Inputs[j].Name = fmt.Sprintf("arg%d", j)
+				}
+			}
+			// Append the event to the accumulator list
+			events[original.Name] = &tmplEvent{Original: original, Normalized: normalized}
+		}
 		contracts[types[i]] = &tmplContract{
 			Type:        capitalise(types[i]),
 			InputABI:    strings.Replace(strippedABI, "\"", "\\\"", -1),
@@ -101,6 +226,7 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 			Constructor: evmABI.Constructor,
 			Calls:       calls,
 			Transacts:   transacts,
+			Events:      events,
 		}
 	}
 	// Generate the contract template data content and render it
@@ -110,35 +236,41 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 	}
 	buffer := new(bytes.Buffer)
 
-	funcs := map[string]interface{}{
-		"bindtype":     bindType[lang],
-		"namedtype":    namedType[lang],
-		"capitalise":   capitalise,
-		"decapitalise": decapitalise,
+	funcs := template.FuncMap{
+		"bindtype":      tmpl.BindType,
+		"bindtopictype": tmpl.BindTopicType,
+		"namedtype":     tmpl.NamedType,
+		"capitalise":    capitalise,
+		"decapitalise":  decapitalise,
+	}
+	if tmpl.BindTopicType == nil {
+		funcs["bindtopictype"] = tmpl.BindType
+	}
+	if tmpl.NamedType == nil {
+		funcs["namedtype"] = func(bound string, kind abi.Type) string { return bound }
 	}
-	tmpl := template.Must(template.New("").Funcs(funcs).Parse(tmplSource[lang]))
-	if err := tmpl.Execute(buffer, data); err != nil {
+	for name, fn := range tmpl.Funcs {
+		funcs[name] = fn
+	}
+	source, err := template.New("").Funcs(funcs).Parse(tmpl.Source)
+	if err != nil {
+		return "", err
+	}
+	if err := source.Execute(buffer, data); err != nil {
 		return "", err
 	}
-	// For Go bindings pass the code through goimports to clean it up and double check
-	if lang == LangGo {
-		code, err := imports.Process(".", buffer.Bytes(), nil)
+	// Pass the code through the formatter (goimports for Go) to clean it up and double check
+	if tmpl.Format != nil {
+		code, err := tmpl.Format(buffer.Bytes())
 		if err != nil {
 			return "", fmt.Errorf("%v\n%s", err, buffer)
 		}
 		return string(code), nil
 	}
-	// For all others just return as is for now
+	// For all others just return as is
 	return buffer.String(), nil
 }
 
-// bindType is a set of type binders that convert Solidity types to some supported
-// programming language.
-var bindType = map[Lang]func(kind abi.Type) string{
-	LangGo:   bindTypeGo,
-	LangJava: bindTypeJava,
-}
-
 // bindTypeGo converts a Solidity type to a Go one. Since there is no clear mapping
 // from all Solidity types to Go ones (e.g. uint17), those that cannot be exactly
 // mapped will use an upscaled type (e.g. *big.Int).
@@ -146,6 +278,10 @@ func bindTypeGo(kind abi.Type) string {
 	stringKind := kind.String()
 
 	switch {
+	case strings.HasPrefix(stringKind, "("):
+		// Tuples (and arrays of them) are bound to anonymous structs
+		return kind.Type.String()
+
 	case strings.HasPrefix(stringKind, "address"):
 		parts := regexp.MustCompile(`address(\[[0-9]*\])?`).FindStringSubmatch(stringKind)
 		if len(parts) != 2 {
@@ -183,6 +319,38 @@ func bindTypeGo(kind abi.Type) string {
 	}
 }
 
+// bindTopicTypeGo converts a Solidity topic type to a Go one. It is almost the
+// same functionality as for simple types, but dynamic types get converted to
+// hashes.
+func bindTopicTypeGo(kind abi.Type) string {
+	bound := bindTypeGo(kind)
+	if bound == "string" || bound == "[]byte" || isReferenceType(kind) {
+		bound = "common.Hash"
+	}
+	return bound
+}
+
+// bindTopicTypeJava converts a Solidity topic type to a Java one. It is almost
+// the same functionality as for simple types, but dynamic types get converted to
+// hashes.
+func bindTopicTypeJava(kind abi.Type) string {
+	bound := bindTypeJava(kind)
+	if bound == "String" || bound == "byte[]" || isReferenceType(kind) {
+		bound = "Hash"
+	}
+	return bound
+}
+
+// isReferenceType reports whether a Solidity type is indexed by its hash rather
+// than its value when used as an event topic.
+func isReferenceType(kind abi.Type) bool {
+	switch kind.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	}
+	return false
+}
+
 // bindTypeJava converts a Solidity type to a Java one. Since there is no clear mapping
 // from all Solidity types to Java ones (e.g. uint17), those that cannot be exactly
 // mapped will use an upscaled type (e.g. BigDecimal).
@@ -254,13 +422,6 @@ func bindTypeJava(kind abi.Type) string {
 	}
 }
 
-// namedType is a set of functions that transform language specific types to
-// named versions that my be used inside method names.
-var namedType = map[Lang]func(string, abi.Type) string{
-	LangGo:   func(string, abi.Type) string { panic("this shouldn't be needed") },
-	LangJava: namedTypeJava,
-}
-
 // namedTypeJava converts some primitive data types to named variants that can
 // be used as parts of method names.
 func namedTypeJava(javaKind string, solKind abi.Type) string {
@@ -297,11 +458,65 @@ func namedTypeJava(javaKind string, solKind abi.Type) string {
 	}
 }
 
-// methodNormalizer is a name transformer that modifies Solidity method names to
-// conform to target language naming concentions.
-var methodNormalizer = map[Lang]func(string) string{
-	LangGo:   capitalise,
-	LangJava: decapitalise,
+// bindTypeTypeScript converts a Solidity type to a TypeScript one. Integers that
+// may not fit into the 53 bit precision of JavaScript numbers are mapped to the
+// BigNumber type of the binding, and byte arrays to hex strings.
+func bindTypeTypeScript(kind abi.Type) string {
+	switch kind.T {
+	case abi.SliceTy, abi.ArrayTy:
+		return bindTypeTypeScript(*kind.Elem) + "[]"
+
+	case abi.TupleTy:
+		// Tuples are bound to object types if all fields are named, arrays otherwise
+		named := len(kind.TupleRawNames) == len(kind.TupleElems)
+		for _, name := range kind.TupleRawNames {
+			if name == "" {
+				named = false
+			}
+		}
+		fields := make([]string, len(kind.TupleElems))
+		for i, elem := range kind.TupleElems {
+			fields[i] = bindTypeTypeScript(*elem)
+			if named {
+				fields[i] = kind.TupleRawNames[i] + ": " + fields[i]
+			}
+		}
+		if named {
+			return "{ " + strings.Join(fields, "; ") + " }"
+		}
+		return "[" + strings.Join(fields, ", ") + "]"
+
+	case abi.AddressTy:
+		return "Address"
+
+	case abi.FixedBytesTy, abi.BytesTy, abi.HashTy, abi.FunctionTy:
+		return "Bytes"
+
+	case abi.IntTy, abi.UintTy:
+		if kind.Size <= 32 {
+			return "number"
+		}
+		return "BigNumber"
+
+	case abi.BoolTy:
+		return "boolean"
+
+	case abi.StringTy:
+		return "string"
+
+	default:
+		return "any"
+	}
+}
+
+// bindTopicTypeTypeScript converts a Solidity topic type to a TypeScript one. It
+// is almost the same functionality as for simple types, but dynamic types get
+// converted to hashes.
+func bindTopicTypeTypeScript(kind abi.Type) string {
+	if kind.T == abi.StringTy || kind.T == abi.BytesTy || isReferenceType(kind) {
+		return "Hash"
+	}
+	return bindTypeTypeScript(kind)
 }
 
 // capitalise makes the first character of a string upper case, also removing any
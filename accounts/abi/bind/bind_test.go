@@ -26,6 +26,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/tools/imports"
 )
@@ -508,6 +509,139 @@ var bindTests = []struct {
 			fmt.Println(a, b, err)
 		`,
 	},
+	// Test that tuple arguments and return values bind to structs
+	{
+		`Tupler`,
+		`
+			pragma experimental ABIEncoderV2;
+
+			contract Tupler {
+				struct Item { uint256 id; bytes data; }
+
+				function store(Item[] items) public {}
+				function get(uint256 id) public view returns (Item item) {}
+			}
+		`,
+		``,
+		`[{"constant":false,"inputs":[{"components":[{"name":"id","type":"uint256"},{"name":"data","type":"bytes"}],"name":"items","type":"tuple[]"}],"name":"store","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"id","type":"uint256"}],"name":"get","outputs":[{"components":[{"name":"id","type":"uint256"},{"name":"data","type":"bytes"}],"name":"item","type":"tuple"}],"payable":false,"stateMutability":"view","type":"function"}]`,
+		`
+			if b, err := NewTupler(common.Address{}, nil); b == nil || err != nil {
+				t.Fatalf("binding (%v) nil or error (%v) not nil", b, nil)
+			}
+			var items []struct {
+				Id   *big.Int ` + "`" + `json:"id"` + "`" + `
+				Data []byte   ` + "`" + `json:"data"` + "`" + `
+			}
+			_ = func(tupler *Tupler) {
+				tupler.Store(nil, items)
+				item, _ := tupler.Get(nil, big.NewInt(0))
+				fmt.Println(item.Id, item.Data)
+			}
+		`,
+	},
+	// Test that events bind to typed filterers and watchers. The contract is hand
+	// assembled to emit Dynamic("hi", 7) on every call, the remainder of the
+	// events are just compiled to keep the test small.
+	{
+		`Eventer`,
+		`
+			contract Eventer {
+				event Changed(address indexed owner, uint256 indexed id, string indexed name, bytes data, bool flag);
+				event Dynamic(string indexed name, uint8 value);
+
+				function() {
+					Dynamic("hi", 7);
+				}
+			}
+		`,
+		`604d80600b6000396000f360076000527f7624778dedc75f8b322b9fa1632a610d40b85e106c7d9bf0e743a9ce291b9c6f7f3541f8761975c06fa2c80fee753d06572ceb8ae05df4e84c98895561312b4ec460206000a200`,
+		`[{"anonymous":false,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":true,"name":"id","type":"uint256"},{"indexed":true,"name":"name","type":"string"},{"indexed":false,"name":"data","type":"bytes"},{"indexed":false,"name":"flag","type":"bool"}],"name":"Changed","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"name","type":"string"},{"indexed":false,"name":"value","type":"uint8"}],"name":"Dynamic","type":"event"}]`,
+		`
+			// Generate a new random account and a funded simulator
+			key, _ := crypto.GenerateKey()
+			auth := bind.NewKeyedTransactor(key)
+			sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: big.NewInt(10000000000)}})
+
+			// Deploy an event emitter contract and trigger an event
+			_, _, eventer, err := DeployEventer(auth, sim)
+			if err != nil {
+				t.Fatalf("Failed to deploy eventer contract: %v", err)
+			}
+			sim.Commit()
+
+			raw := &EventerRaw{Contract: eventer}
+			if _, err := raw.Transfer(auth); err != nil {
+				t.Fatalf("Failed to trigger event: %v", err)
+			}
+			sim.Commit()
+
+			// Filter the past events by their indexed name
+			it, err := eventer.FilterDynamic(nil, []string{"hi"})
+			if err != nil {
+				t.Fatalf("Failed to filter events: %v", err)
+			}
+			defer it.Close()
+
+			count := 0
+			for it.Next() {
+				if it.Event.Name != crypto.Keccak256Hash([]byte("hi")) || it.Event.Value != 7 {
+					t.Errorf("Event mismatch: have %x/%d, want %x/%d", it.Event.Name, it.Event.Value, crypto.Keccak256Hash([]byte("hi")), 7)
+				}
+				if it.Event.Raw.BlockNumber != 2 {
+					t.Errorf("Event block mismatch: have %d, want %d", it.Event.Raw.BlockNumber, 2)
+				}
+				count++
+			}
+			if err := it.Error(); err != nil {
+				t.Fatalf("Failed to iterate events: %v", err)
+			}
+			if count != 1 {
+				t.Fatalf("Event count mismatch: have %d, want %d", count, 1)
+			}
+			if it, err := eventer.FilterDynamic(nil, []string{"bye"}); err != nil {
+				t.Fatalf("Failed to filter events: %v", err)
+			} else if it.Next() {
+				t.Fatalf("Unexpected event matched: %v", it.Event)
+			}
+			// Watch for live events and trigger a new one
+			sink := make(chan *EventerDynamic, 1)
+			sub, err := eventer.WatchDynamic(nil, sink, nil)
+			if err != nil {
+				t.Fatalf("Failed to watch events: %v", err)
+			}
+			defer sub.Unsubscribe()
+
+			if _, err := raw.Transfer(auth); err != nil {
+				t.Fatalf("Failed to trigger event: %v", err)
+			}
+			sim.Commit()
+
+			select {
+			case event := <-sink:
+				if event.Value != 7 || event.Raw.BlockNumber != 3 {
+					t.Errorf("Watched event mismatch: have %d in block %d, want %d in block %d", event.Value, event.Raw.BlockNumber, 7, 3)
+				}
+			case err := <-sub.Err():
+				t.Fatalf("Event subscription failed: %v", err)
+			case <-time.After(time.Second):
+				t.Fatalf("Event not delivered")
+			}
+			// Ensure the remaining events bind to the expected types
+			_ = func(eventer *Eventer) {
+				it, _ := eventer.FilterChanged(nil, []common.Address{{}}, []*big.Int{big.NewInt(1)}, []string{"name"})
+				for it.Next() {
+					var (
+						owner common.Address = it.Event.Owner
+						id    *big.Int       = it.Event.Id
+						name  common.Hash    = it.Event.Name
+						data  []byte         = it.Event.Data
+						flag  bool           = it.Event.Flag
+					)
+					fmt.Println(owner, id, name, data, flag)
+				}
+			}
+		`,
+	},
 }
 
 // Tests that packages generated by the binder can be successfully compiled and
@@ -565,3 +699,84 @@ func TestBindings(t *testing.T) {
 		t.Fatalf("failed to run binding test: %v\n%s", err, out)
 	}
 }
+
+// Tests that the TypeScript binder maps the contract interfaces and types.
+func TestBindTypeScript(t *testing.T) {
+	tests := map[string][]string{
+		"Token": {
+			`export interface Token extends TokenCaller, TokenTransactor, TokenFilterer {`,
+			`deploy(initialSupply: BigNumber, tokenName: string, decimalUnits: number, tokenSymbol: string, opts?: TransactOpts): Promise<Token>;`,
+			`balanceOf(arg0: Address, opts?: CallOpts): Promise<BigNumber>;`,
+			`approveAndCall(_spender: Address, _value: BigNumber, _extraData: Bytes, opts?: TransactOpts): Promise<Hash>;`,
+			`filterTransfer(from?: Address[], to?: Address[], opts?: FilterOpts): Promise<TokenTransfer[]>;`,
+		},
+		"Tupler": {
+			`tuple(opts?: CallOpts): Promise<{ a: string; b: BigNumber; c: Bytes }>;`,
+		},
+		"Slicer": {
+			`echoAddresses(input: Address[], opts?: CallOpts): Promise<Address[]>;`,
+		},
+		"Eventer": {
+			`watchChanged(sink: (event: EventerChanged) => void, owner?: Address[], id?: BigNumber[], name?: Hash[], opts?: WatchOpts): Subscription;`,
+			"\tname: Hash;\n\tdata: Bytes;\n\tflag: boolean;\n",
+		},
+	}
+	for _, tt := range bindTests {
+		want, ok := tests[tt.name]
+		if !ok {
+			continue
+		}
+		code, err := Bind([]string{tt.name}, []string{tt.abi}, []string{tt.bytecode}, "bindtest", LangTypeScript)
+		if err != nil {
+			t.Fatalf("%s: failed to generate binding: %v", tt.name, err)
+		}
+		for _, line := range want {
+			if !strings.Contains(code, line) {
+				t.Errorf("%s: binding missing %q:\n%s", tt.name, line, code)
+			}
+		}
+		delete(tests, tt.name)
+	}
+	if len(tests) > 0 {
+		t.Fatalf("unknown test contracts: %v", tests)
+	}
+}
+
+// Tests that custom template sets can be supplied, overriding the built-in
+// template sources, type mappings and functions.
+func TestBindTemplate(t *testing.T) {
+	contractABI := `[{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balance_of","outputs":[{"name":"","type":"uint256"}],"type":"function"}]`
+
+	// Customize the TypeScript template set with a different source and big integers
+	tmpl, err := LangTemplate(LangTypeScript)
+	if err != nil {
+		t.Fatalf("failed to retrieve template set: %v", err)
+	}
+	tmpl.Source = `{{range .Contracts}}{{.Type}}@{{pkg}}:{{range .Calls}}{{.Normalized.Name}}({{range .Normalized.Inputs}}{{bindtype .Type}}{{end}}) {{range .Normalized.Outputs}}{{bindtype .Type}}{{end}}{{end}}{{end}}`
+	tmpl.Funcs = map[string]interface{}{"pkg": func() string { return "custom" }}
+
+	bindType := tmpl.BindType
+	tmpl.BindType = func(kind abi.Type) string {
+		if kind.T == abi.UintTy {
+			return "BN"
+		}
+		return bindType(kind)
+	}
+	code, err := BindTemplate([]string{"token"}, []string{contractABI}, []string{""}, "bindtest", tmpl)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	if want := "Token@custom:balance_of(Address) BN"; code != want {
+		t.Fatalf("binding mismatch: have %q, want %q", code, want)
+	}
+	// Ensure the built-in template set was not modified and invalid sets are rejected
+	if code, err := Bind([]string{"token"}, []string{contractABI}, []string{""}, "bindtest", LangTypeScript); err != nil || !strings.Contains(code, "Promise<BigNumber>") {
+		t.Fatalf("built-in template set modified: %v\n%s", err, code)
+	}
+	if _, err := BindTemplate([]string{"token"}, []string{contractABI}, []string{""}, "bindtest", &Template{Source: tmpl.Source}); err == nil {
+		t.Fatalf("template set without type binder accepted")
+	}
+	if _, err := Bind([]string{"token"}, []string{contractABI}, []string{""}, "bindtest", LangObjC); err == nil {
+		t.Fatalf("unsupported language accepted")
+	}
+}
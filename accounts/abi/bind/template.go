@@ -18,7 +18,8 @@ package bind
 
 import "github.com/ethereum/go-ethereum/accounts/abi"
 
-// tmplData is the data structure required to fill the binding template.
+// tmplData is the data structure required to fill the binding template. It is
+// the root object custom template sources are rendered with.
 type tmplData struct {
 	Package   string                   // Name of the package to place the generated file in
 	Contracts map[string]*tmplContract // List of contracts to generate into this file
@@ -32,6 +33,7 @@ type tmplContract struct {
 	Constructor abi.Method             // Contract constructor for deploy parametrization
 	Calls       map[string]*tmplMethod // Contract calls that only read state data
 	Transacts   map[string]*tmplMethod // Contract calls that write state data
+	Events      map[string]*tmplEvent  // Contract events accessors
 }
 
 // tmplMethod is a wrapper around an abi.Method that contains a few preprocessed
@@ -42,11 +44,11 @@ type tmplMethod struct {
 	Structured bool       // Whether the returns should be accumulated into a contract
 }
 
-// tmplSource is language to template mapping containing all the supported
-// programming languages the package can generate to.
-var tmplSource = map[Lang]string{
-	LangGo:   tmplSourceGo,
-	LangJava: tmplSourceJava,
+// tmplEvent is a wrapper around an abi.Event that contains a few preprocessed
+// and cached data fields.
+type tmplEvent struct {
+	Original   abi.Event // Original event as parsed by the abi package
+	Normalized abi.Event // Normalized version of the parsed fields
 }
 
 // tmplSourceGo is the Go source template use to generate the contract binding
@@ -75,7 +77,7 @@ package {{.Package}}
 		  if err != nil {
 		    return common.Address{}, nil, nil, err
 		  }
-		  return address, tx, &{{.Type}}{ {{.Type}}Caller: {{.Type}}Caller{contract: contract}, {{.Type}}Transactor: {{.Type}}Transactor{contract: contract} }, nil
+		  return address, tx, &{{.Type}}{ {{.Type}}Caller: {{.Type}}Caller{contract: contract}, {{.Type}}Transactor: {{.Type}}Transactor{contract: contract}, {{.Type}}Filterer: {{.Type}}Filterer{contract: contract} }, nil
 		}
 	{{end}}
 
@@ -83,6 +85,7 @@ package {{.Package}}
 	type {{.Type}} struct {
 	  {{.Type}}Caller     // Read-only binding to the contract
 	  {{.Type}}Transactor // Write-only binding to the contract
+	  {{.Type}}Filterer   // Log filterer for contract events
 	}
 
 	// {{.Type}}Caller is an auto generated read-only Go binding around an Ethereum contract.
@@ -95,6 +98,11 @@ package {{.Package}}
 	  contract *bind.BoundContract // Generic contract wrapper for the low level calls
 	}
 
+	// {{.Type}}Filterer is an auto generated log filtering Go binding around an Ethereum contract events.
+	type {{.Type}}Filterer struct {
+	  contract *bind.BoundContract // Generic contract wrapper for the low level calls
+	}
+
 	// {{.Type}}Session is an auto generated Go binding around an Ethereum contract,
 	// with pre-set call and transact options.
 	type {{.Type}}Session struct {
@@ -134,16 +142,16 @@ package {{.Package}}
 
 	// New{{.Type}} creates a new instance of {{.Type}}, bound to a specific deployed contract.
 	func New{{.Type}}(address common.Address, backend bind.ContractBackend) (*{{.Type}}, error) {
-	  contract, err := bind{{.Type}}(address, backend, backend)
+	  contract, err := bind{{.Type}}(address, backend, backend, backend)
 	  if err != nil {
 	    return nil, err
 	  }
-	  return &{{.Type}}{ {{.Type}}Caller: {{.Type}}Caller{contract: contract}, {{.Type}}Transactor: {{.Type}}Transactor{contract: contract} }, nil
+	  return &{{.Type}}{ {{.Type}}Caller: {{.Type}}Caller{contract: contract}, {{.Type}}Transactor: {{.Type}}Transactor{contract: contract}, {{.Type}}Filterer: {{.Type}}Filterer{contract: contract} }, nil
 	}
 
 	// New{{.Type}}Caller creates a new read-only instance of {{.Type}}, bound to a specific deployed contract.
 	func New{{.Type}}Caller(address common.Address, caller bind.ContractCaller) (*{{.Type}}Caller, error) {
-	  contract, err := bind{{.Type}}(address, caller, nil)
+	  contract, err := bind{{.Type}}(address, caller, nil, nil)
 	  if err != nil {
 	    return nil, err
 	  }
@@ -152,20 +160,29 @@ package {{.Package}}
 
 	// New{{.Type}}Transactor creates a new write-only instance of {{.Type}}, bound to a specific deployed contract.
 	func New{{.Type}}Transactor(address common.Address, transactor bind.ContractTransactor) (*{{.Type}}Transactor, error) {
-	  contract, err := bind{{.Type}}(address, nil, transactor)
+	  contract, err := bind{{.Type}}(address, nil, transactor, nil)
 	  if err != nil {
 	    return nil, err
 	  }
 	  return &{{.Type}}Transactor{contract: contract}, nil
 	}
 
+	// New{{.Type}}Filterer creates a new log filterer instance of {{.Type}}, bound to a specific deployed contract.
+	func New{{.Type}}Filterer(address common.Address, filterer bind.ContractFilterer) (*{{.Type}}Filterer, error) {
+	  contract, err := bind{{.Type}}(address, nil, nil, filterer)
+	  if err != nil {
+	    return nil, err
+	  }
+	  return &{{.Type}}Filterer{contract: contract}, nil
+	}
+
 	// bind{{.Type}} binds a generic wrapper to an already deployed contract.
-	func bind{{.Type}}(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor) (*bind.BoundContract, error) {
+	func bind{{.Type}}(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
 	  parsed, err := abi.JSON(strings.NewReader({{.Type}}ABI))
 	  if err != nil {
 	    return nil, err
 	  }
-	  return bind.NewBoundContract(address, parsed, caller, transactor), nil
+	  return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
 	}
 
 	// Call invokes the (constant) contract method with params as input values and
@@ -263,6 +280,140 @@ package {{.Package}}
 		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Normalized.Inputs}}, {{.Name}}{{end}})
 		}
 	{{end}}
+
+	{{range .Events}}
+		// {{$contract.Type}}{{.Normalized.Name}}Iterator is returned from Filter{{.Normalized.Name}} and is used to iterate over the raw logs and unpacked data for {{.Normalized.Name}} events raised by the {{$contract.Type}} contract.
+		type {{$contract.Type}}{{.Normalized.Name}}Iterator struct {
+			Event *{{$contract.Type}}{{.Normalized.Name}} // Event containing the contract specifics and raw log
+
+			contract *bind.BoundContract // Generic contract to use for unpacking event data
+			event    string              // Event name to use for unpacking event data
+
+			logs chan types.Log     // Log channel receiving the found contract events
+			sub  event.Subscription // Subscription for errors, completion and termination
+			done bool               // Whether the subscription completed delivering logs
+			fail error              // Occurred error to stop iteration
+		}
+
+		// Next advances the iterator to the subsequent event, returning whether there
+		// are any more events found. In case of a retrieval or parsing error, false is
+		// returned and Error() can be queried for the exact failure.
+		func (it *{{$contract.Type}}{{.Normalized.Name}}Iterator) Next() bool {
+			// If the iterator failed, stop iterating
+			if (it.fail != nil) {
+				return false
+			}
+			// If the iterator completed, deliver directly whatever's available
+			if (it.done) {
+				select {
+				case log := <-it.logs:
+					it.Event = new({{$contract.Type}}{{.Normalized.Name}})
+					if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+						it.fail = err
+						return false
+					}
+					it.Event.Raw = log
+					return true
+
+				default:
+					return false
+				}
+			}
+			// Iterator still in progress, wait for either a data or an error event
+			select {
+			case log := <-it.logs:
+				it.Event = new({{$contract.Type}}{{.Normalized.Name}})
+				if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+					it.fail = err
+					return false
+				}
+				it.Event.Raw = log
+				return true
+
+			case err := <-it.sub.Err():
+				it.done = true
+				it.fail = err
+				return it.Next()
+			}
+		}
+
+		// Error returns any retrieval or parsing error occurred during filtering.
+		func (it *{{$contract.Type}}{{.Normalized.Name}}Iterator) Error() error {
+			return it.fail
+		}
+
+		// Close terminates the iteration process, releasing any pending underlying
+		// resources.
+		func (it *{{$contract.Type}}{{.Normalized.Name}}Iterator) Close() error {
+			it.sub.Unsubscribe()
+			return nil
+		}
+
+		// {{$contract.Type}}{{.Normalized.Name}} represents a {{.Normalized.Name}} event raised by the {{$contract.Type}} contract.
+		type {{$contract.Type}}{{.Normalized.Name}} struct { {{range .Normalized.Inputs}}
+			{{capitalise .Name}} {{if .Indexed}}{{bindtopictype .Type}}{{else}}{{bindtype .Type}}{{end}}; {{end}}
+			Raw types.Log // Blockchain specific contextual infos
+		}
+
+		// Filter{{.Normalized.Name}} is a free log retrieval operation binding the contract event 0x{{printf "%x" .Original.Id}}.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Filterer) Filter{{.Normalized.Name}}(opts *bind.FilterOpts{{range .Normalized.Inputs}}{{if .Indexed}}, {{.Name}} []{{bindtype .Type}}{{end}}{{end}}) (*{{$contract.Type}}{{.Normalized.Name}}Iterator, error) {
+			{{range .Normalized.Inputs}}
+			{{if .Indexed}}var {{.Name}}Rule []interface{}
+			for _, {{.Name}}Item := range {{.Name}} {
+				{{.Name}}Rule = append({{.Name}}Rule, {{.Name}}Item)
+			}{{end}}{{end}}
+
+			logs, sub, err := _{{$contract.Type}}.contract.FilterLogs(opts, "{{.Original.Name}}"{{range .Normalized.Inputs}}{{if .Indexed}}, {{.Name}}Rule{{end}}{{end}})
+			if err != nil {
+				return nil, err
+			}
+			return &{{$contract.Type}}{{.Normalized.Name}}Iterator{contract: _{{$contract.Type}}.contract, event: "{{.Original.Name}}", logs: logs, sub: sub}, nil
+		}
+
+		// Watch{{.Normalized.Name}} is a free log subscription operation binding the contract event 0x{{printf "%x" .Original.Id}}.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Filterer) Watch{{.Normalized.Name}}(opts *bind.WatchOpts, sink chan<- *{{$contract.Type}}{{.Normalized.Name}}{{range .Normalized.Inputs}}{{if .Indexed}}, {{.Name}} []{{bindtype .Type}}{{end}}{{end}}) (event.Subscription, error) {
+			{{range .Normalized.Inputs}}
+			{{if .Indexed}}var {{.Name}}Rule []interface{}
+			for _, {{.Name}}Item := range {{.Name}} {
+				{{.Name}}Rule = append({{.Name}}Rule, {{.Name}}Item)
+			}{{end}}{{end}}
+
+			logs, sub, err := _{{$contract.Type}}.contract.WatchLogs(opts, "{{.Original.Name}}"{{range .Normalized.Inputs}}{{if .Indexed}}, {{.Name}}Rule{{end}}{{end}})
+			if err != nil {
+				return nil, err
+			}
+			return event.NewSubscription(func(quit <-chan struct{}) error {
+				defer sub.Unsubscribe()
+				for {
+					select {
+					case log := <-logs:
+						// New log arrived, parse the event and forward to the user
+						event := new({{$contract.Type}}{{.Normalized.Name}})
+						if err := _{{$contract.Type}}.contract.UnpackLog(event, "{{.Original.Name}}", log); err != nil {
+							return err
+						}
+						event.Raw = log
+
+						select {
+						case sink <- event:
+						case err := <-sub.Err():
+							return err
+						case <-quit:
+							return nil
+						}
+					case err := <-sub.Err():
+						return err
+					case <-quit:
+						return nil
+					}
+				}
+			}), nil
+		}
+	{{end}}
 {{end}}
 `
 
@@ -367,3 +518,137 @@ import org.ethereum.geth.internal.*;
 	}
 {{end}}
 `
+
+// tmplSourceTypeScript is the TypeScript source template used to generate the
+// contract interface definitions based on.
+const tmplSourceTypeScript = `
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+/** Address is a 20 byte Ethereum account address, hex encoded with a 0x prefix. */
+export type Address = string;
+
+/** Hash is a 32 byte Keccak256 hash, hex encoded with a 0x prefix. */
+export type Hash = string;
+
+/** Bytes is an arbitrary byte array, hex encoded with a 0x prefix. */
+export type Bytes = string;
+
+/** BigNumber is an integer that may not fit into a JavaScript number, in decimal form. */
+export type BigNumber = string;
+
+/** CallOpts is the collection of options to fine tune a contract call request. */
+export interface CallOpts {
+	from?: Address;          // Optional the sender address, otherwise the first account is used
+	blockNumber?: BigNumber; // Optional block number to call at, otherwise the latest one is used
+}
+
+/** TransactOpts is the collection of options to fine tune a contract transaction. */
+export interface TransactOpts {
+	from?: Address;       // Ethereum account to send the transaction from
+	nonce?: BigNumber;    // Nonce to use for the transaction execution (unset = use pending state)
+	value?: BigNumber;    // Funds to transfer along the transaction (unset = 0)
+	gasPrice?: BigNumber; // Gas price to use for the transaction execution (unset = gas price oracle)
+	gasLimit?: BigNumber; // Gas limit to set for the transaction execution (unset = estimate)
+}
+
+/** FilterOpts is the collection of options to fine tune filtering for past events. */
+export interface FilterOpts {
+	fromBlock?: BigNumber; // Start of the queried range (unset = genesis)
+	toBlock?: BigNumber;   // End of the range (unset = latest)
+}
+
+/** WatchOpts is the collection of options to fine tune subscribing for new events. */
+export interface WatchOpts {
+	fromBlock?: BigNumber; // Start of the queried range (unset = latest)
+}
+
+/** Subscription represents a stream of events, which can be cancelled. */
+export interface Subscription {
+	unsubscribe(): void;
+}
+
+/** Log is a raw contract log entry an event was decoded from. */
+export interface Log {
+	address: Address;
+	topics: Hash[];
+	data: Bytes;
+	blockNumber: BigNumber;
+	transactionHash: Hash;
+	transactionIndex: number;
+	blockHash: Hash;
+	logIndex: number;
+	removed: boolean;
+}
+{{range $contract := .Contracts}}
+/** {{.Type}}ABI is the input ABI used to generate the binding from. */
+export const {{.Type}}ABI = "{{.InputABI}}";
+{{if .InputBin}}
+/** {{.Type}}Bin is the compiled bytecode used for deploying new contracts. */
+export const {{.Type}}Bin = "{{.InputBin}}";
+
+/** {{.Type}}Deployer deploys new instances of the {{.Type}} contract. */
+export interface {{.Type}}Deployer {
+	deploy({{range .Constructor.Inputs}}{{.Name}}: {{bindtype .Type}}, {{end}}opts?: TransactOpts): Promise<{{.Type}}>;
+}
+{{end}}
+/** {{.Type}}Caller is the read-only interface of the {{.Type}} contract. */
+export interface {{.Type}}Caller {
+{{- range .Calls}}
+	/**
+	 * {{.Normalized.Name}} is a free data retrieval call binding the contract method 0x{{printf "%x" .Original.Id}}.
+	 *
+	 * Solidity: {{.Original.String}}
+	 */
+	{{.Normalized.Name}}({{range .Normalized.Inputs}}{{.Name}}: {{bindtype .Type}}, {{end}}opts?: CallOpts): Promise<
+		{{- if .Structured}}{ {{range $i, $_ := .Normalized.Outputs}}{{if $i}}; {{end}}{{decapitalise .Name}}: {{bindtype .Type}}{{end}} }
+		{{- else if eq (len .Normalized.Outputs) 0}}void
+		{{- else if eq (len .Normalized.Outputs) 1}}{{range .Normalized.Outputs}}{{bindtype .Type}}{{end}}
+		{{- else}}[{{range $i, $_ := .Normalized.Outputs}}{{if $i}}, {{end}}{{bindtype .Type}}{{end}}]{{end}}>;
+{{- end}}
+}
+
+/** {{.Type}}Transactor is the write-only interface of the {{.Type}} contract. */
+export interface {{.Type}}Transactor {
+{{- range .Transacts}}
+	/**
+	 * {{.Normalized.Name}} is a paid mutator transaction binding the contract method 0x{{printf "%x" .Original.Id}},
+	 * resolving to the hash of the sent transaction.
+	 *
+	 * Solidity: {{.Original.String}}
+	 */
+	{{.Normalized.Name}}({{range .Normalized.Inputs}}{{.Name}}: {{bindtype .Type}}, {{end}}opts?: TransactOpts): Promise<Hash>;
+{{- end}}
+}
+{{range .Events}}
+/** {{$contract.Type}}{{capitalise .Normalized.Name}} represents a {{.Original.Name}} event raised by the {{$contract.Type}} contract. */
+export interface {{$contract.Type}}{{capitalise .Normalized.Name}} {
+{{- range .Normalized.Inputs}}
+	{{.Name}}: {{if .Indexed}}{{bindtopictype .Type}}{{else}}{{bindtype .Type}}{{end}};
+{{- end}}
+	raw: Log; // Blockchain specific contextual infos
+}
+{{end}}
+/** {{.Type}}Filterer is the log filtering interface of the {{.Type}} contract. */
+export interface {{.Type}}Filterer {
+{{- range .Events}}
+	/**
+	 * filter{{capitalise .Normalized.Name}} retrieves the past {{.Original.Name}} events matching the given indexed field values.
+	 *
+	 * Solidity: {{.Original.String}}
+	 */
+	filter{{capitalise .Normalized.Name}}({{range .Normalized.Inputs}}{{if .Indexed}}{{.Name}}?: {{bindtopictype .Type}}[], {{end}}{{end}}opts?: FilterOpts): Promise<{{$contract.Type}}{{capitalise .Normalized.Name}}[]>;
+	/**
+	 * watch{{capitalise .Normalized.Name}} subscribes to new {{.Original.Name}} events matching the given indexed field values.
+	 *
+	 * Solidity: {{.Original.String}}
+	 */
+	watch{{capitalise .Normalized.Name}}(sink: (event: {{$contract.Type}}{{capitalise .Normalized.Name}}) => void, {{range .Normalized.Inputs}}{{if .Indexed}}{{.Name}}?: {{bindtopictype .Type}}[], {{end}}{{end}}opts?: WatchOpts): Subscription;
+{{- end}}
+}
+
+/** {{.Type}} is the full interface of a deployed {{.Type}} contract. */
+export interface {{.Type}} extends {{.Type}}Caller, {{.Type}}Transactor, {{.Type}}Filterer {
+	readonly address: Address; // Address of the contract on the blockchain
+}
+{{end -}}`
@@ -0,0 +1,158 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// errNoEventSignature is returned when unpacking a log which lacks the topic
+// identifying the event.
+var errNoEventSignature = errors.New("no event signature")
+
+// makeTopics converts a filter query argument list into a filter topic set.
+func makeTopics(query ...[]interface{}) ([][]common.Hash, error) {
+	topics := make([][]common.Hash, len(query))
+	for i, filter := range query {
+		for _, rule := range filter {
+			var topic common.Hash
+
+			// Try to generate the topic based on simple types
+			switch rule := rule.(type) {
+			case common.Hash:
+				copy(topic[:], rule[:])
+			case common.Address:
+				copy(topic[common.HashLength-common.AddressLength:], rule[:])
+			case *big.Int:
+				blob := math.U256(new(big.Int).Set(rule)).Bytes()
+				copy(topic[common.HashLength-len(blob):], blob)
+			case bool:
+				if rule {
+					topic[common.HashLength-1] = 1
+				}
+			case int8:
+				topic = signedTopic(int64(rule))
+			case int16:
+				topic = signedTopic(int64(rule))
+			case int32:
+				topic = signedTopic(int64(rule))
+			case int64:
+				topic = signedTopic(rule)
+			case uint8:
+				topic[common.HashLength-1] = rule
+			case uint16:
+				binary.BigEndian.PutUint16(topic[common.HashLength-2:], rule)
+			case uint32:
+				binary.BigEndian.PutUint32(topic[common.HashLength-4:], rule)
+			case uint64:
+				binary.BigEndian.PutUint64(topic[common.HashLength-8:], rule)
+			case string:
+				topic = crypto.Keccak256Hash([]byte(rule))
+			case []byte:
+				topic = crypto.Keccak256Hash(rule)
+
+			default:
+				// Attempt to generate the topic from funky types
+				val := reflect.ValueOf(rule)
+				if val.Kind() != reflect.Array || val.Type().Elem().Kind() != reflect.Uint8 || val.Len() > common.HashLength {
+					return nil, fmt.Errorf("unsupported indexed type: %T", rule)
+				}
+				reflect.Copy(reflect.ValueOf(topic[:val.Len()]), val)
+			}
+			topics[i] = append(topics[i], topic)
+		}
+	}
+	return topics, nil
+}
+
+// signedTopic encodes a signed integer as a sign extended topic.
+func signedTopic(n int64) common.Hash {
+	var topic common.Hash
+	if n < 0 {
+		for i := range topic {
+			topic[i] = 0xff
+		}
+	}
+	binary.BigEndian.PutUint64(topic[common.HashLength-8:], uint64(n))
+	return topic
+}
+
+// parseTopics converts the indexed topic fields into actual log field values.
+//
+// Note, dynamic types cannot be reconstructed since they get mapped to Keccak256
+// hashes as the topic value!
+func parseTopics(out interface{}, fields abi.Arguments, topics []common.Hash) error {
+	// Sanity check that the fields and topics match up
+	if len(fields) != len(topics) {
+		return errors.New("topic/field count mismatch")
+	}
+	// Iterate over all the fields and reconstruct them from topics
+	for i, arg := range fields {
+		if !arg.Indexed {
+			return errors.New("non-indexed field in topic reconstruction")
+		}
+		field := reflect.ValueOf(out).Elem().FieldByName(capitalise(arg.Name))
+		if !field.IsValid() {
+			return fmt.Errorf("no field for indexed argument %q", arg.Name)
+		}
+		topic := topics[i]
+
+		// Try to parse the topic back into the fields based on primitive types
+		switch field.Kind() {
+		case reflect.Bool:
+			if topic[common.HashLength-1] == 1 {
+				field.Set(reflect.ValueOf(true))
+			}
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(int64(binary.BigEndian.Uint64(topic[common.HashLength-8:])))
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(binary.BigEndian.Uint64(topic[common.HashLength-8:]))
+
+		default:
+			// Ran out of plain primitive types, try custom types
+			switch field.Type() {
+			case reflect.TypeOf(common.Hash{}):
+				field.Set(reflect.ValueOf(topic))
+			case reflect.TypeOf(common.Address{}):
+				field.Set(reflect.ValueOf(common.BytesToAddress(topic[:])))
+			case reflect.TypeOf(new(big.Int)):
+				num := new(big.Int).SetBytes(topic[:])
+				if arg.Type.T == abi.IntTy {
+					num = math.S256(num)
+				}
+				field.Set(reflect.ValueOf(num))
+
+			default:
+				// Ran out of custom types, try the crazies
+				if field.Kind() != reflect.Array || field.Type().Elem().Kind() != reflect.Uint8 || field.Len() > common.HashLength {
+					return fmt.Errorf("unsupported indexed type: %v", arg.Type)
+				}
+				reflect.Copy(field, reflect.ValueOf(topic[:field.Len()]))
+			}
+		}
+	}
+	return nil
+}
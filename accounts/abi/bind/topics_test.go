@@ -0,0 +1,82 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that indexed event arguments survive a round trip through the filter
+// topics and back into a typed event struct.
+func TestTopicsRoundTrip(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"event","name":"Log","inputs":[
+		{"name":"flag","type":"bool","indexed":true},
+		{"name":"delta","type":"int32","indexed":true},
+		{"name":"amount","type":"int256","indexed":true},
+		{"name":"addr","type":"address","indexed":true},
+		{"name":"id","type":"bytes4","indexed":true},
+		{"name":"memo","type":"string","indexed":true}
+	]}]`))
+	if err != nil {
+		t.Fatalf("failed to parse abi: %v", err)
+	}
+	addr := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+	topics, err := makeTopics(
+		[]interface{}{true}, []interface{}{int32(-5)}, []interface{}{big.NewInt(-7)},
+		[]interface{}{addr}, []interface{}{[4]byte{1, 2, 3, 4}}, []interface{}{"memo"},
+	)
+	if err != nil {
+		t.Fatalf("failed to make topics: %v", err)
+	}
+	flat := make([]common.Hash, len(topics))
+	for i, topic := range topics {
+		if len(topic) != 1 {
+			t.Fatalf("topic %d: rule count mismatch: have %d, want 1", i, len(topic))
+		}
+		flat[i] = topic[0]
+	}
+	var out struct {
+		Flag   bool
+		Delta  int32
+		Amount *big.Int
+		Addr   common.Address
+		Id     [4]byte
+		Memo   common.Hash
+	}
+	if err := parseTopics(&out, parsed.Events["Log"].Inputs, flat); err != nil {
+		t.Fatalf("failed to parse topics: %v", err)
+	}
+	if !out.Flag || out.Delta != -5 || out.Amount.Cmp(big.NewInt(-7)) != 0 || out.Addr != addr {
+		t.Errorf("value mismatch: have %+v", out)
+	}
+	if out.Id != [4]byte{1, 2, 3, 4} || out.Memo != crypto.Keccak256Hash([]byte("memo")) {
+		t.Errorf("reference mismatch: have %+v", out)
+	}
+	if !reflect.DeepEqual(topics[0], []common.Hash{{31: 1}}) {
+		t.Errorf("bool topic mismatch: have %x", topics[0])
+	}
+	if _, err := makeTopics([]interface{}{3.14}); err == nil {
+		t.Errorf("unsupported type accepted")
+	}
+}
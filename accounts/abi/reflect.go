@@ -80,12 +80,61 @@ func set(dst, src reflect.Value, output Argument) error {
 		dst.Set(src)
 	case dstType.Kind() == reflect.Ptr:
 		return set(dst.Elem(), src, output)
+	case dstType.Kind() == reflect.Struct && srcType.Kind() == reflect.Struct:
+		return setStruct(dst, src, output)
+	case dstType.Kind() == reflect.Slice && srcType.Kind() == reflect.Slice:
+		slice := reflect.MakeSlice(dstType, src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := set(slice.Index(i), src.Index(i), output); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+	case dstType.Kind() == reflect.Array && srcType.Kind() == reflect.Array && dst.Len() == src.Len():
+		for i := 0; i < src.Len(); i++ {
+			if err := set(dst.Index(i), src.Index(i), output); err != nil {
+				return err
+			}
+		}
 	default:
 		return fmt.Errorf("abi: cannot unmarshal %v in to %v", src.Type(), dst.Type())
 	}
 	return nil
 }
 
+// setStruct assigns an unpacked tuple to a Go struct, matching the fields by
+// name, or if the struct has none of them, by their order.
+func setStruct(dst, src reflect.Value, output Argument) error {
+	var (
+		srcType = src.Type()
+		fields  = make([]reflect.Value, src.NumField())
+		named   int
+	)
+	for i := range fields {
+		if fields[i] = dst.FieldByName(srcType.Field(i).Name); fields[i].IsValid() {
+			named++
+		}
+	}
+	switch {
+	case named == len(fields):
+	case named == 0 && dst.NumField() == len(fields):
+		for i := range fields {
+			fields[i] = dst.Field(i)
+		}
+	default:
+		return fmt.Errorf("abi: cannot unmarshal %v in to %v", src.Type(), dst.Type())
+	}
+	for i, field := range fields {
+		if !field.CanSet() {
+			return fmt.Errorf("abi: cannot unmarshal %v in to unexported field of %v", srcType.Field(i).Name, dst.Type())
+		}
+		if err := set(field, src.Field(i), output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // requireAssignable assures that `dest` is a pointer and it's not an interface.
 func requireAssignable(dst, src reflect.Value) error {
 	if dst.Kind() != reflect.Ptr && dst.Kind() != reflect.Interface {
@@ -38,6 +38,7 @@ const (
 	HashTy
 	FixedPointTy
 	FunctionTy
+	TupleTy
 )
 
 // Type is the reflection of the supported argument type
@@ -49,6 +50,9 @@ type Type struct {
 	Size int
 	T    byte // Our own type checking
 
+	TupleElems    []*Type  // Types of the tuple components
+	TupleRawNames []string // Original names of the tuple components, as declared in the ABI
+
 	stringKind string // holds the unparsed string for deriving signatures
 }
 
@@ -59,6 +63,12 @@ var (
 
 // NewType creates a new reflection type of abi type given in t.
 func NewType(t string) (typ Type, err error) {
+	return newType(t, nil)
+}
+
+// newType creates a new reflection type of abi type given in t, using the given
+// components to assemble tuple types.
+func newType(t string, components []ArgumentMarshaling) (typ Type, err error) {
 	// check that array brackets are equal if they exist
 	if strings.Count(t, "[") != strings.Count(t, "]") {
 		return Type{}, fmt.Errorf("invalid arg type in abi")
@@ -71,12 +81,14 @@ func NewType(t string) (typ Type, err error) {
 	if strings.Count(t, "[") != 0 {
 		i := strings.LastIndex(t, "[")
 		// recursively embed the type
-		embeddedType, err := NewType(t[:i])
+		embeddedType, err := newType(t[:i], components)
 		if err != nil {
 			return Type{}, err
 		}
 		// grab the last cell and create a type from there
 		sliced := t[i:]
+		typ.stringKind = embeddedType.stringKind + sliced
+
 		// grab the slice size with regexp
 		re := regexp.MustCompile("[0-9]+")
 		intz := re.FindAllString(sliced, -1)
@@ -158,6 +170,43 @@ func NewType(t string) (typ Type, err error) {
 		typ.T = FunctionTy
 		typ.Size = 24
 		typ.Type = reflect.ArrayOf(24, reflect.TypeOf(byte(0)))
+	case "tuple":
+		var (
+			fields []reflect.StructField
+			elems  []*Type
+			names  []string
+			kinds  []string
+			exists = make(map[string]bool)
+		)
+		for _, c := range components {
+			cType, err := newType(c.Type, c.Components)
+			if err != nil {
+				return Type{}, err
+			}
+			name := capitalise(c.Name)
+			if name == "" {
+				return Type{}, fmt.Errorf("abi: unnamed or purely underscored tuple component")
+			}
+			if exists[name] {
+				return Type{}, fmt.Errorf("abi: multiple tuple components mapping to the same field '%s'", name)
+			}
+			exists[name] = true
+
+			fields = append(fields, reflect.StructField{
+				Name: name,
+				Type: cType.Type,
+				Tag:  reflect.StructTag(fmt.Sprintf("json:\"%s\"", c.Name)),
+			})
+			elems = append(elems, &cType)
+			names = append(names, c.Name)
+			kinds = append(kinds, cType.stringKind)
+		}
+		typ.Kind = reflect.Struct
+		typ.Type = reflect.StructOf(fields)
+		typ.T = TupleTy
+		typ.TupleElems = elems
+		typ.TupleRawNames = names
+		typ.stringKind = "(" + strings.Join(kinds, ",") + ")"
 	default:
 		return Type{}, fmt.Errorf("unsupported arg type: %s", t)
 	}
@@ -178,21 +227,58 @@ func (t Type) pack(v reflect.Value) ([]byte, error) {
 		return nil, err
 	}
 
-	if t.T == SliceTy || t.T == ArrayTy {
-		var packed []byte
-
+	switch t.T {
+	case SliceTy, ArrayTy:
+		var ret []byte
+		if t.T == SliceTy {
+			ret = packNum(reflect.ValueOf(v.Len()))
+		}
+		// dynamic elements are referenced by their offsets and appended after them
+		var (
+			tail    []byte
+			dynamic = isDynamicType(*t.Elem)
+			offset  = getTypeSize(*t.Elem) * v.Len()
+		)
 		for i := 0; i < v.Len(); i++ {
 			val, err := t.Elem.pack(v.Index(i))
 			if err != nil {
 				return nil, err
 			}
-			packed = append(packed, val...)
+			if !dynamic {
+				ret = append(ret, val...)
+				continue
+			}
+			ret = append(ret, packNum(reflect.ValueOf(offset))...)
+			tail = append(tail, val...)
+			offset += len(val)
 		}
-		if t.T == SliceTy {
-			return packBytesSlice(packed, v.Len()), nil
-		} else if t.T == ArrayTy {
-			return packed, nil
+		return append(ret, tail...), nil
+
+	case TupleTy:
+		fields, err := tupleFields(t, v)
+		if err != nil {
+			return nil, err
+		}
+		// dynamic components are referenced by their offsets and appended after them
+		offset := 0
+		for _, elem := range t.TupleElems {
+			offset += getTypeSize(*elem)
 		}
+		var ret, tail []byte
+		for i, elem := range t.TupleElems {
+			val, err := elem.pack(fields[i])
+			if err != nil {
+				return nil, err
+			}
+			if !isDynamicType(*elem) {
+				ret = append(ret, val...)
+				continue
+			}
+			ret = append(ret, packNum(reflect.ValueOf(offset))...)
+			tail = append(tail, val...)
+			offset += len(val)
+		}
+		return append(ret, tail...), nil
 	}
 	return packElement(t, v), nil
 }
@@ -202,3 +288,67 @@ func (t Type) pack(v reflect.Value) ([]byte, error) {
 func (t Type) requiresLengthPrefix() bool {
 	return t.T == StringTy || t.T == BytesTy || t.T == SliceTy
 }
+
+// isDynamicType returns whether the encoding of the type is of variable length,
+// in which case it's referenced by an offset from the head of the enclosing
+// encoding. Static arrays and tuples are dynamic if any of their elements are.
+func isDynamicType(t Type) bool {
+	switch t.T {
+	case StringTy, BytesTy, SliceTy:
+		return true
+	case ArrayTy:
+		return isDynamicType(*t.Elem)
+	case TupleTy:
+		for _, elem := range t.TupleElems {
+			if isDynamicType(*elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getTypeSize returns the number of bytes the type occupies in the head of the
+// enclosing encoding. Dynamic types are only referenced there by a single word,
+// static arrays and tuples are encoded in place.
+func getTypeSize(t Type) int {
+	if isDynamicType(t) {
+		return 32
+	}
+	switch t.T {
+	case ArrayTy:
+		return t.Size * getTypeSize(*t.Elem)
+	case TupleTy:
+		size := 0
+		for _, elem := range t.TupleElems {
+			size += getTypeSize(*elem)
+		}
+		return size
+	}
+	return 32
+}
+
+// tupleFields maps the components of a tuple type to the fields of a Go struct.
+// Fields are matched by the capitalised component names, or if the struct has
+// none of them, by their order.
+func tupleFields(t Type, v reflect.Value) ([]reflect.Value, error) {
+	var (
+		fields = make([]reflect.Value, len(t.TupleElems))
+		named  int
+	)
+	for i, name := range t.TupleRawNames {
+		if fields[i] = v.FieldByName(capitalise(name)); fields[i].IsValid() {
+			named++
+		}
+	}
+	switch {
+	case named == len(fields):
+		return fields, nil
+	case named == 0 && v.NumField() == len(fields):
+		for i := range fields {
+			fields[i] = v.Field(i)
+		}
+		return fields, nil
+	}
+	return nil, fmt.Errorf("abi: cannot map %v to tuple %v", v.Type(), t)
+}
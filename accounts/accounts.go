@@ -113,6 +113,17 @@ type Wallet interface {
 	// the account in a keystore).
 	SignTx(account Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
 
+	// SignTypedData requests the wallet to sign an EIP-712 structured data, given
+	// as its domain separator and message struct hash. Keeping the two hashes apart
+	// allows hardware wallets to display them for confirmation.
+	//
+	// It looks up the account specified either solely via its address contained within,
+	// or optionally with the aid of any location metadata from the embedded URL field.
+	//
+	// If the wallet requires additional authentication to sign the request, an
+	// AuthNeededError instance will be returned, similarly to SignHash.
+	SignTypedData(account Account, domainSeparator, messageHash []byte) ([]byte, error)
+
 	// SignHashWithPassphrase requests the wallet to sign the given hash with the
 	// given passphrase as extra authentication information.
 	//
@@ -126,6 +137,13 @@ type Wallet interface {
 	// It looks up the account specified either solely via its address contained within,
 	// or optionally with the aid of any location metadata from the embedded URL field.
 	SignTxWithPassphrase(account Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+	// SignTypedDataWithPassphrase requests the wallet to sign an EIP-712 structured
+	// data, with the given passphrase as extra authentication information.
+	//
+	// It looks up the account specified either solely via its address contained within,
+	// or optionally with the aid of any location metadata from the embedded URL field.
+	SignTypedDataWithPassphrase(account Account, passphrase string, domainSeparator, messageHash []byte) ([]byte, error)
 }
 
 // Backend is a "wallet provider" that may contain a batch of accounts they can
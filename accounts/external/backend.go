@@ -0,0 +1,267 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package external implements an account backend forwarding all signing requests
+// to an external signer process over its JSON-RPC API.
+package external
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ExternalBackend is an accounts.Backend exposing the accounts of a single
+// external signer as one wallet.
+type ExternalBackend struct {
+	signers []accounts.Wallet
+}
+
+// NewExternalBackend connects to the external signer at the given endpoint (IPC
+// path or HTTP URL) and creates an account backend on top of it.
+func NewExternalBackend(endpoint string) (*ExternalBackend, error) {
+	signer, err := NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalBackend{
+		signers: []accounts.Wallet{signer},
+	}, nil
+}
+
+// Wallets implements accounts.Backend, returning the single external wallet.
+func (eb *ExternalBackend) Wallets() []accounts.Wallet {
+	return eb.signers
+}
+
+// Subscribe implements accounts.Backend. The external wallet is present from
+// the start and never changes, so the subscription never fires.
+func (eb *ExternalBackend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+// ExternalSigner is an accounts.Wallet backed by an external signer process.
+// All approvals and passwords are handled by the signer itself.
+type ExternalSigner struct {
+	client   *rpc.Client
+	endpoint string
+	status   string
+
+	cacheMu sync.RWMutex
+	cache   []accounts.Account
+}
+
+// NewExternalSigner dials the external signer at the given endpoint.
+func NewExternalSigner(endpoint string) (*ExternalSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	extsigner := &ExternalSigner{
+		client:   client,
+		endpoint: endpoint,
+	}
+	// Check if reachable
+	version, err := extsigner.pingVersion()
+	if err != nil {
+		return nil, err
+	}
+	extsigner.status = fmt.Sprintf("ok [version=%v]", version)
+	return extsigner, nil
+}
+
+// URL implements accounts.Wallet, returning the endpoint of the signer.
+func (api *ExternalSigner) URL() accounts.URL {
+	return accounts.URL{
+		Scheme: "extapi",
+		Path:   api.endpoint,
+	}
+}
+
+// Status implements accounts.Wallet, returning the version of the signer's API
+// as reported when connecting.
+func (api *ExternalSigner) Status() (string, error) {
+	return api.status, nil
+}
+
+// Open implements accounts.Wallet. The signer manages its own accounts, so this
+// is a noop.
+func (api *ExternalSigner) Open(passphrase string) error {
+	return nil
+}
+
+// Close implements accounts.Wallet. The signer manages its own accounts, so this
+// is a noop.
+func (api *ExternalSigner) Close() error {
+	return nil
+}
+
+// Accounts implements accounts.Wallet, returning the accounts the signer is
+// willing to reveal. The list is requested once and cached afterwards, as every
+// listing may require an approval on the signer's side.
+func (api *ExternalSigner) Accounts() []accounts.Account {
+	api.cacheMu.RLock()
+	cached := api.cache
+	api.cacheMu.RUnlock()
+	if cached != nil {
+		return cached
+	}
+	var res []struct {
+		Address common.Address `json:"address"`
+	}
+	if err := api.client.Call(&res, "account_list"); err != nil {
+		log.Error("Failed to list accounts of external signer", "err", err)
+		return nil
+	}
+	accnts := make([]accounts.Account, 0, len(res))
+	for _, acc := range res {
+		accnts = append(accnts, accounts.Account{
+			URL:     api.URL(),
+			Address: acc.Address,
+		})
+	}
+	api.cacheMu.Lock()
+	api.cache = accnts
+	api.cacheMu.Unlock()
+
+	return accnts
+}
+
+// Contains implements accounts.Wallet, checking whether the signer revealed the
+// given account.
+func (api *ExternalSigner) Contains(account accounts.Account) bool {
+	for _, a := range api.Accounts() {
+		if a.Address == account.Address && (account.URL == (accounts.URL{}) || account.URL == api.URL()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Derive implements accounts.Wallet, but is not supported by external signers.
+func (api *ExternalSigner) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// SelfDerive implements accounts.Wallet, but is a noop for external signers.
+func (api *ExternalSigner) SelfDerive(base accounts.DerivationPath, chain ethereum.ChainStateReader) {
+}
+
+// SignHash implements accounts.Wallet. The external signer only signs messages
+// by applying the Ethereum message prefix itself, never raw hashes, so this is
+// unsupported.
+func (api *ExternalSigner) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// sendTxArgs mirrors the transaction arguments of the signer's API.
+type sendTxArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice hexutil.Big     `json:"gasPrice"`
+	Value    hexutil.Big     `json:"value"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Data     *hexutil.Bytes  `json:"data"`
+}
+
+// SignTx implements accounts.Wallet, requesting the external signer to sign the
+// transaction. The signer may modify the transaction upon user request, so the
+// returned transaction is the one to use.
+func (api *ExternalSigner) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := &sendTxArgs{
+		From:     account.Address,
+		To:       tx.To(),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     &data,
+	}
+	var res struct {
+		Raw hexutil.Bytes      `json:"raw"`
+		Tx  *types.Transaction `json:"tx"`
+	}
+	if err := api.client.Call(&res, "account_signTransaction", args); err != nil {
+		return nil, err
+	}
+	if res.Tx == nil {
+		return nil, fmt.Errorf("external signer returned no transaction")
+	}
+	// Make sure the signer didn't sign for a different network
+	if chainID != nil && res.Tx.Protected() && res.Tx.ChainId().Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("external signer used chain id %v, want %v", res.Tx.ChainId(), chainID)
+	}
+	// Make sure the signer didn't sign with a different account
+	var signer types.Signer = types.HomesteadSigner{}
+	if res.Tx.Protected() {
+		signer = types.NewEIP155Signer(res.Tx.ChainId())
+	}
+	sender, err := types.Sender(signer, res.Tx)
+	if err != nil {
+		return nil, err
+	}
+	if sender != account.Address {
+		return nil, fmt.Errorf("external signer signed with account %x, want %x", sender, account.Address)
+	}
+	return res.Tx, nil
+}
+
+// SignHashWithPassphrase implements accounts.Wallet. Passwords are managed by
+// the external signer, so this is unsupported.
+func (api *ExternalSigner) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTxWithPassphrase implements accounts.Wallet. Passwords are managed by the
+// external signer, so this is unsupported.
+func (api *ExternalSigner) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTypedData implements accounts.Wallet, but the external signer doesn't
+// support structured data yet.
+func (api *ExternalSigner) SignTypedData(account accounts.Account, domainSeparator, messageHash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTypedDataWithPassphrase implements accounts.Wallet. Passwords are managed
+// by the external signer, so this is unsupported.
+func (api *ExternalSigner) SignTypedDataWithPassphrase(account accounts.Account, passphrase string, domainSeparator, messageHash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// pingVersion retrieves the version of the signer's external API.
+func (api *ExternalSigner) pingVersion() (string, error) {
+	var v string
+	if err := api.client.Call(&v, "account_version"); err != nil {
+		return "", err
+	}
+	return v, nil
+}
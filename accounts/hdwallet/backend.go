@@ -0,0 +1,338 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package hdwallet implements software hierarchical deterministic wallets,
+// deriving accounts from an encrypted BIP-39 mnemonic.
+package hdwallet
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Scheme is the protocol scheme prefixing account and wallet URLs.
+const Scheme = "hdwallet"
+
+// BackendType is the reflect type of an HD wallet backend.
+var BackendType = reflect.TypeOf(&Backend{})
+
+// mnemonicBits is the entropy size of newly generated mnemonics, resulting in
+// 24 words.
+const mnemonicBits = 256
+
+// refreshCycle is the maximum time between wallet directory rescans while there
+// are subscribers to wallet events.
+const refreshCycle = 3 * time.Second
+
+// refreshThrottling is the minimum time between wallet directory rescans.
+const refreshThrottling = 500 * time.Millisecond
+
+var (
+	// ErrWalletExists is returned if a mnemonic is imported that is already
+	// tracked by the backend.
+	ErrWalletExists = errors.New("wallet already exists")
+
+	// ErrInvalidMnemonic is returned if an imported mnemonic is malformed or
+	// has a bad checksum.
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+)
+
+// Backend is an accounts.Backend managing the HD wallet files in a directory.
+type Backend struct {
+	dir string       // Directory containing the wallet files
+	kdf keystore.KDF // Key derivation function to encrypt new wallets with
+
+	refreshed   time.Time               // Time instance when the list of wallets was last refreshed
+	wallets     []*wallet               // List of wallets currently tracked, sorted by URL
+	updateFeed  event.Feed              // Event feed to notify wallet additions/removals
+	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
+	updating    bool                    // Whether the event notification loop is running
+
+	stateLock sync.RWMutex // Protects the internals of the backend from racey access
+}
+
+// NewBackend creates a backend for the HD wallets stored in the given directory,
+// encrypting newly created wallets with the given scrypt parameters.
+func NewBackend(dir string, scryptN, scryptP int) *Backend {
+	return NewBackendWithKDF(dir, keystore.ScryptKDF(scryptN, scryptP))
+}
+
+// NewBackendWithKDF creates a backend for the HD wallets stored in the given
+// directory, encrypting newly created wallets with the given key derivation
+// function. Wallets encrypted with any supported key derivation function can be
+// opened.
+func NewBackendWithKDF(dir string, kdf keystore.KDF) *Backend {
+	backend := &Backend{
+		dir: dir,
+		kdf: kdf,
+	}
+	backend.refreshWallets()
+	return backend
+}
+
+// Wallets implements accounts.Backend, returning all the HD wallets found in the
+// wallet directory.
+func (b *Backend) Wallets() []accounts.Wallet {
+	// Make sure the list of wallets is up to date
+	b.refreshWallets()
+
+	b.stateLock.RLock()
+	defer b.stateLock.RUnlock()
+
+	cpy := make([]accounts.Wallet, len(b.wallets))
+	for i, wallet := range b.wallets {
+		cpy[i] = wallet
+	}
+	return cpy
+}
+
+// refreshWallets scans the wallet directory and updates the list of wallets
+// based on the found files.
+func (b *Backend) refreshWallets() {
+	// Don't scan the disk like crazy it the user fetches wallets in a loop
+	b.stateLock.RLock()
+	elapsed := time.Since(b.refreshed)
+	b.stateLock.RUnlock()
+
+	if elapsed < refreshThrottling {
+		return
+	}
+	// Retrieve the current list of wallet files, sorted by path
+	var paths []string
+
+	files, err := ioutil.ReadDir(b.dir)
+	if err != nil && !os.IsNotExist(err) {
+		log.Warn("Failed to scan HD wallet directory", "dir", b.dir, "err", err)
+		return
+	}
+	for _, fi := range files {
+		// Skip editor backups, hidden and temporary files and anything not regular
+		if strings.HasSuffix(fi.Name(), "~") || strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		if fi.IsDir() || fi.Mode()&os.ModeType != 0 {
+			continue
+		}
+		paths = append(paths, filepath.Join(b.dir, fi.Name()))
+	}
+	sort.Strings(paths)
+
+	// Transform the current list of wallets into the new one
+	b.stateLock.Lock()
+
+	known := make(map[string]*wallet)
+	for _, wallet := range b.wallets {
+		known[wallet.path] = wallet
+	}
+	wallets := make([]*wallet, 0, len(paths))
+	events := []accounts.WalletEvent{}
+
+	for _, path := range paths {
+		if wallet, ok := known[path]; ok {
+			wallets = append(wallets, wallet)
+			delete(known, path)
+			continue
+		}
+		file, err := loadWalletFile(path)
+		if err != nil {
+			log.Debug("Ignoring invalid HD wallet file", "path", path, "err", err)
+			continue
+		}
+		wallet := newWallet(b, path, file)
+
+		events = append(events, accounts.WalletEvent{Wallet: wallet, Kind: accounts.WalletArrived})
+		wallets = append(wallets, wallet)
+	}
+	// Drop any wallets whose files disappeared and set the new batch
+	for _, wallet := range b.wallets {
+		if _, ok := known[wallet.path]; ok {
+			events = append(events, accounts.WalletEvent{Wallet: wallet, Kind: accounts.WalletDropped})
+		}
+	}
+	b.refreshed = time.Now()
+	b.wallets = wallets
+	b.stateLock.Unlock()
+
+	// Fire all wallet events and return
+	for _, event := range events {
+		b.updateFeed.Send(event)
+	}
+}
+
+// Subscribe implements accounts.Backend, creating an async subscription to
+// receive notifications on the addition or removal of HD wallets.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	// We need the mutex to reliably start/stop the update loop
+	b.stateLock.Lock()
+	defer b.stateLock.Unlock()
+
+	// Subscribe the caller and track the subscriber count
+	sub := b.updateScope.Track(b.updateFeed.Subscribe(sink))
+
+	// Subscribers require an active notification loop, start it
+	if !b.updating {
+		b.updating = true
+		go b.updater()
+	}
+	return sub
+}
+
+// updater is responsible for maintaining an up-to-date list of wallets stored
+// in the wallet directory, and for firing wallet addition/removal events.
+func (b *Backend) updater() {
+	for {
+		time.Sleep(refreshCycle)
+
+		// Run the wallet refresher
+		b.refreshWallets()
+
+		// If all our subscribers left, stop the updater
+		b.stateLock.Lock()
+		if b.updateScope.Count() == 0 {
+			b.updating = false
+			b.stateLock.Unlock()
+			return
+		}
+		b.stateLock.Unlock()
+	}
+}
+
+// NewWallet generates a fresh mnemonic and stores a new HD wallet derived from
+// it, protected by the optional BIP-39 passphrase and encrypted with the given
+// wallet passphrase. The mnemonic is returned so the user can back it up.
+func (b *Backend) NewWallet(mnemonicPassphrase, passphrase string) (accounts.Wallet, string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicBits)
+	if err != nil {
+		return nil, "", err
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, "", err
+	}
+	wallet, err := b.Import(mnemonic, mnemonicPassphrase, passphrase)
+	if err != nil {
+		return nil, "", err
+	}
+	return wallet, mnemonic, nil
+}
+
+// Import stores a new HD wallet derived from the given mnemonic and optional
+// BIP-39 passphrase, encrypted with the given wallet passphrase. The account
+// at the default base derivation path is pinned into the new wallet.
+func (b *Backend) Import(mnemonic, mnemonicPassphrase, passphrase string) (accounts.Wallet, error) {
+	entropy, err := bip39.EntropyFromMnemonic(normalizeMnemonic(mnemonic))
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+	secret := &walletSecret{Entropy: entropy, Passphrase: mnemonicPassphrase}
+	defer secret.wipe()
+
+	// Derive the base account to identify the wallet with
+	master, err := secret.masterKey()
+	if err != nil {
+		return nil, err
+	}
+	defer master.wipe()
+
+	address, err := deriveAddress(master, accounts.DefaultBaseDerivationPath)
+	if err != nil {
+		return nil, err
+	}
+	b.refreshWallets()
+
+	b.stateLock.Lock()
+	for _, wallet := range b.wallets {
+		if wallet.file.Address == address {
+			b.stateLock.Unlock()
+			return nil, ErrWalletExists
+		}
+	}
+	b.stateLock.Unlock()
+
+	// Encrypt the wallet and persist it to disk
+	crypto, err := encryptSecret(secret, passphrase, b.kdf)
+	if err != nil {
+		return nil, err
+	}
+	file := &walletFile{
+		Address:  address,
+		Crypto:   crypto,
+		Accounts: []pinnedAccount{{Address: address, Path: accounts.DefaultBaseDerivationPath.String()}},
+		Version:  walletVersion,
+	}
+	path := filepath.Join(b.dir, walletFileName(address))
+	if err := storeWalletFile(path, file); err != nil {
+		return nil, err
+	}
+	// Start tracking the wallet immediately, unless a concurrent rescan did so
+	b.stateLock.Lock()
+	for _, wallet := range b.wallets {
+		if wallet.path == path {
+			b.stateLock.Unlock()
+			return wallet, nil
+		}
+	}
+	wallet := newWallet(b, path, file)
+	b.wallets = append(b.wallets, wallet)
+	sort.Sort(walletsByURL(b.wallets))
+	b.stateLock.Unlock()
+
+	b.updateFeed.Send(accounts.WalletEvent{Wallet: wallet, Kind: accounts.WalletArrived})
+	return wallet, nil
+}
+
+// Export decrypts an HD wallet tracked by the backend with the given passphrase,
+// returning its mnemonic and BIP-39 passphrase.
+func (b *Backend) Export(wlt accounts.Wallet, passphrase string) (string, string, error) {
+	w, ok := wlt.(*wallet)
+	if !ok || w.backend != b {
+		return "", "", accounts.ErrUnknownWallet
+	}
+	w.stateLock.RLock()
+	secret, err := decryptSecret(w.file, passphrase)
+	w.stateLock.RUnlock()
+
+	if err != nil {
+		return "", "", err
+	}
+	defer secret.wipe()
+
+	mnemonic, err := secret.mnemonic()
+	if err != nil {
+		return "", "", err
+	}
+	return mnemonic, secret.Passphrase, nil
+}
+
+// walletsByURL implements sort.Interface for []*wallet by URL.
+type walletsByURL []*wallet
+
+func (w walletsByURL) Len() int           { return len(w) }
+func (w walletsByURL) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
+func (w walletsByURL) Less(i, j int) bool { return w[i].url.Cmp(w[j].url) < 0 }
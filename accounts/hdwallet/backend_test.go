@@ -0,0 +1,254 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	veryLightScryptN = 2
+	veryLightScryptP = 1
+
+	testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+)
+
+func tmpBackend(t *testing.T) (string, *Backend) {
+	dir, err := ioutil.TempDir("", "eth-hdwallet-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, NewBackend(dir, veryLightScryptN, veryLightScryptP)
+}
+
+// Tests that wallets can be created, imported and exported, and that they are
+// persisted across backend instances.
+func TestWalletLifecycle(t *testing.T) {
+	dir, backend := tmpBackend(t)
+	defer os.RemoveAll(dir)
+
+	// Create a brand new wallet and check that its mnemonic round trips
+	created, mnemonic, err := backend.NewWallet("", "foo")
+	if err != nil {
+		t.Fatalf("failed to create wallet: %v", err)
+	}
+	if have, phrase, err := backend.Export(created, "foo"); err != nil || have != mnemonic || phrase != "" {
+		t.Fatalf("export mismatch: have %q/%q (%v), want %q/%q", have, phrase, err, mnemonic, "")
+	}
+	if _, _, err := backend.Export(created, "bar"); err == nil {
+		t.Fatalf("exported wallet with wrong passphrase")
+	}
+	// Import a known mnemonic and check the pinned base account
+	imported, err := backend.Import("  Abandon abandon abandon abandon abandon abandon\nabandon abandon abandon abandon abandon about ", "", "foo")
+	if err != nil {
+		t.Fatalf("failed to import wallet: %v", err)
+	}
+	if accs := imported.Accounts(); len(accs) != 1 || accs[0].Address != common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94") {
+		t.Fatalf("pinned accounts mismatch: have %v", accs)
+	}
+	if _, err := backend.Import(testMnemonic, "", "bar"); err != ErrWalletExists {
+		t.Fatalf("duplicate import error mismatch: have %v, want %v", err, ErrWalletExists)
+	}
+	if _, err := backend.Import(testMnemonic, "TREZOR", "bar"); err != nil {
+		t.Fatalf("failed to import wallet with BIP-39 passphrase: %v", err)
+	}
+	if _, err := backend.Import("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "", "bar"); err != ErrInvalidMnemonic {
+		t.Fatalf("invalid mnemonic error mismatch: have %v, want %v", err, ErrInvalidMnemonic)
+	}
+	// Reload the wallets from disk and check that they are all present
+	wallets := NewBackend(dir, veryLightScryptN, veryLightScryptP).Wallets()
+	if len(wallets) != 3 {
+		t.Fatalf("wallet count mismatch: have %d, want %d", len(wallets), 3)
+	}
+	for i := 1; i < len(wallets); i++ {
+		if wallets[i-1].URL().Cmp(wallets[i].URL()) >= 0 {
+			t.Errorf("wallets not sorted: %v before %v", wallets[i-1].URL(), wallets[i].URL())
+		}
+	}
+}
+
+// Tests that wallets are encrypted with the key derivation function configured
+// on the backend, and can be decrypted regardless of the one used on reload.
+func TestWalletKDF(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eth-hdwallet-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backend := NewBackendWithKDF(dir, keystore.Argon2idKDF(1, 64))
+	created, mnemonic, err := backend.NewWallet("", "foo")
+	if err != nil {
+		t.Fatalf("failed to create wallet: %v", err)
+	}
+	file, err := loadWalletFile(created.URL().Path)
+	if err != nil {
+		t.Fatalf("failed to load wallet file: %v", err)
+	}
+	if file.Crypto.KDF != keystore.KDFArgon2id {
+		t.Fatalf("wallet kdf mismatch: have %s, want %s", file.Crypto.KDF, keystore.KDFArgon2id)
+	}
+	reloaded := NewBackend(dir, veryLightScryptN, veryLightScryptP)
+	if have, _, err := reloaded.Export(reloaded.Wallets()[0], "foo"); err != nil || have != mnemonic {
+		t.Fatalf("export mismatch: have %q (%v), want %q", have, err, mnemonic)
+	}
+}
+
+// Tests that wallets can only derive and sign when opened, and that pinned
+// accounts are persisted.
+func TestWalletDeriveAndSign(t *testing.T) {
+	dir, backend := tmpBackend(t)
+	defer os.RemoveAll(dir)
+
+	wallet, err := backend.Import(testMnemonic, "", "foo")
+	if err != nil {
+		t.Fatalf("failed to import wallet: %v", err)
+	}
+	base := wallet.Accounts()[0]
+
+	path, _ := accounts.ParseDerivationPath("m/44'/60'/0'/0/1")
+	if _, err := wallet.Derive(path, true); err != accounts.ErrWalletClosed {
+		t.Fatalf("closed derivation error mismatch: have %v, want %v", err, accounts.ErrWalletClosed)
+	}
+	if _, err := wallet.SignHash(base, make([]byte, 32)); err != accounts.ErrWalletClosed {
+		t.Fatalf("closed signing error mismatch: have %v, want %v", err, accounts.ErrWalletClosed)
+	}
+	if err := wallet.Open("bar"); err == nil {
+		t.Fatalf("opened wallet with wrong passphrase")
+	}
+	if err := wallet.Open("foo"); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	// Derive a second account and check that it's persisted
+	derived, err := wallet.Derive(path, true)
+	if err != nil {
+		t.Fatalf("failed to derive account: %v", err)
+	}
+	if !wallet.Contains(derived) {
+		t.Fatalf("derived account not pinned")
+	}
+	reloaded := NewBackend(dir, veryLightScryptN, veryLightScryptP).Wallets()[0]
+	if accs := reloaded.Accounts(); len(accs) != 2 || accs[1].Address != derived.Address {
+		t.Fatalf("pinned accounts not persisted: have %v", accs)
+	}
+	// Sign with the open wallet and the passphrase of the closed one
+	hash := crypto.Keccak256([]byte("hello"))
+	for _, account := range []accounts.Account{base, derived} {
+		sig, err := wallet.SignHash(account, hash)
+		if err != nil {
+			t.Fatalf("failed to sign hash: %v", err)
+		}
+		if pubkey, err := crypto.SigToPub(hash, sig); err != nil || crypto.PubkeyToAddress(*pubkey) != account.Address {
+			t.Errorf("signer mismatch for %x", account.Address)
+		}
+		if other, err := reloaded.SignHashWithPassphrase(account, "foo", hash); err != nil || string(other) != string(sig) {
+			t.Errorf("passphrase signature mismatch for %x: %v", account.Address, err)
+		}
+	}
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := wallet.SignTx(derived, tx, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(1)), signed); err != nil || sender != derived.Address {
+		t.Errorf("transaction sender mismatch: have %x, want %x", sender, derived.Address)
+	}
+	// Close the wallet and ensure signing is denied again
+	wallet.Close()
+	if _, err := wallet.SignHash(derived, hash); err != accounts.ErrWalletClosed {
+		t.Fatalf("closed signing error mismatch: have %v, want %v", err, accounts.ErrWalletClosed)
+	}
+}
+
+// testChain is a chain state reader reporting a non-zero nonce for a fixed set
+// of accounts.
+type testChain map[common.Address]bool
+
+func (c testChain) BalanceAt(ctx context.Context, account common.Address, number *big.Int) (*big.Int, error) {
+	return new(big.Int), nil
+}
+
+func (c testChain) NonceAt(ctx context.Context, account common.Address, number *big.Int) (uint64, error) {
+	if c[account] {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (c testChain) StorageAt(ctx context.Context, account common.Address, key common.Hash, number *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (c testChain) CodeAt(ctx context.Context, account common.Address, number *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+// Tests that opened wallets discover used accounts based on the chain state.
+func TestWalletSelfDerive(t *testing.T) {
+	dir, backend := tmpBackend(t)
+	defer os.RemoveAll(dir)
+
+	wallet, err := backend.Import(testMnemonic, "", "foo")
+	if err != nil {
+		t.Fatalf("failed to import wallet: %v", err)
+	}
+	if err := wallet.Open("foo"); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	// Mark the first three accounts as used and check that they get discovered
+	chain := make(testChain)
+	for i := 0; i < 3; i++ {
+		path := append(accounts.DerivationPath{}, accounts.DefaultBaseDerivationPath...)
+		path[len(path)-1] = uint32(i)
+
+		account, err := wallet.Derive(path, false)
+		if err != nil {
+			t.Fatalf("failed to derive account %d: %v", i, err)
+		}
+		chain[account.Address] = true
+	}
+	wallet.SelfDerive(accounts.DefaultBaseDerivationPath, chain)
+	if accs := wallet.Accounts(); len(accs) != 4 {
+		t.Fatalf("discovered account count mismatch: have %d, want %d", len(accs), 4)
+	}
+	// Use up the next account and check it's discovered after the throttling
+	chain[wallet.Accounts()[3].Address] = true
+	time.Sleep(selfDeriveThrottling)
+	if accs := wallet.Accounts(); len(accs) != 5 {
+		t.Fatalf("discovered account count mismatch: have %d, want %d", len(accs), 5)
+	}
+	// Closing the wallet should only retain the pinned accounts
+	wallet.Close()
+	if accs := wallet.Accounts(); len(accs) != 1 {
+		t.Fatalf("account count mismatch after close: have %d, want %d", len(accs), 1)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// masterKeySalt is the HMAC key used by BIP-32 to derive the master key from a
+// seed, fixed by the specification for every currency.
+var masterKeySalt = []byte("Bitcoin seed")
+
+// errInvalidKey is returned if a derivation step yields an invalid private key.
+// The probability of this is lower than 1 in 2^127, but BIP-32 mandates that
+// such indices be skipped.
+var errInvalidKey = errors.New("invalid derived key")
+
+// extendedKey is a BIP-32 extended private key: a secp256k1 private key along
+// with the chain code required to derive its children.
+type extendedKey struct {
+	key   []byte // 32 byte private key
+	chain []byte // 32 byte chain code
+}
+
+// newMasterKey derives the root extended key of a hierarchical deterministic
+// wallet from a BIP-39 seed.
+func newMasterKey(seed []byte) (*extendedKey, error) {
+	mac := hmac.New(sha512.New, masterKeySalt)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	if !validKey(sum[:32]) {
+		return nil, errInvalidKey
+	}
+	return &extendedKey{key: sum[:32], chain: sum[32:]}, nil
+}
+
+// child derives the extended private key at the given index below the current
+// one. Indices from 2^31 onward yield hardened keys.
+func (k *extendedKey) child(index uint32) (*extendedKey, error) {
+	var data []byte
+	if index >= 0x80000000 {
+		data = append([]byte{0x00}, k.key...)
+	} else {
+		priv, err := crypto.ToECDSA(k.key)
+		if err != nil {
+			return nil, err
+		}
+		data = crypto.CompressPubkey(&priv.PublicKey)
+	}
+	var seq [4]byte
+	binary.BigEndian.PutUint32(seq[:], index)
+	data = append(data, seq[:]...)
+
+	mac := hmac.New(sha512.New, k.chain)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	// The child key is the parent key tweaked by the left half of the HMAC
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, errInvalidKey
+	}
+	key := tweak.Add(tweak, new(big.Int).SetBytes(k.key))
+	key.Mod(key, crypto.S256().Params().N)
+	if key.Sign() == 0 {
+		return nil, errInvalidKey
+	}
+	return &extendedKey{key: math.PaddedBigBytes(key, 32), chain: sum[32:]}, nil
+}
+
+// derive walks the given derivation path down from the current key, wiping any
+// intermediate keys along the way.
+func (k *extendedKey) derive(path accounts.DerivationPath) (*extendedKey, error) {
+	current := k
+	for _, index := range path {
+		next, err := current.child(index)
+		if current != k {
+			current.wipe()
+		}
+		if err != nil {
+			return nil, err
+		}
+		current = next
+	}
+	if current == k {
+		return &extendedKey{key: common.CopyBytes(k.key), chain: common.CopyBytes(k.chain)}, nil
+	}
+	return current, nil
+}
+
+// privateKey converts the extended key into an ECDSA private key usable for
+// signing. The caller is responsible for zeroing it out after use.
+func (k *extendedKey) privateKey() (*ecdsa.PrivateKey, error) {
+	return crypto.ToECDSA(k.key)
+}
+
+// wipe zeroes out the key material held by the extended key.
+func (k *extendedKey) wipe() {
+	for i := range k.key {
+		k.key[i] = 0
+	}
+	for i := range k.chain {
+		k.chain[i] = 0
+	}
+}
+
+// validKey reports whether the given bytes are a valid secp256k1 private key.
+func validKey(key []byte) bool {
+	k := new(big.Int).SetBytes(key)
+	return k.Sign() > 0 && k.Cmp(crypto.S256().Params().N) < 0
+}
+
+// zeroKey zeroes a private key in memory.
+func zeroKey(k *ecdsa.PrivateKey) {
+	b := k.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Tests private key derivation against test vector 1 of the BIP-32 specification.
+func TestBIP32Derivation(t *testing.T) {
+	master, err := newMasterKey(common.FromHex("000102030405060708090a0b0c0d0e0f"))
+	if err != nil {
+		t.Fatalf("failed to create master key: %v", err)
+	}
+	tests := []struct {
+		path string
+		key  string
+		code string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"},
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca", "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4", "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8", "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e"},
+	}
+	for i, tt := range tests {
+		var path accounts.DerivationPath
+		if tt.path != "m" {
+			if path, err = accounts.ParseDerivationPath(tt.path); err != nil {
+				t.Fatalf("test %d: invalid derivation path %s: %v", i, tt.path, err)
+			}
+		}
+		child, err := master.derive(path)
+		if err != nil {
+			t.Errorf("test %d: failed to derive %s: %v", i, tt.path, err)
+			continue
+		}
+		if !bytes.Equal(child.key, common.FromHex(tt.key)) {
+			t.Errorf("test %d: key mismatch at %s: have %x, want %s", i, tt.path, child.key, tt.key)
+		}
+		if !bytes.Equal(child.chain, common.FromHex(tt.code)) {
+			t.Errorf("test %d: chain code mismatch at %s: have %x, want %s", i, tt.path, child.chain, tt.code)
+		}
+	}
+}
+
+// Tests that a BIP-39 mnemonic derives the expected seed and Ethereum accounts.
+func TestMnemonicDerivation(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	// Check the seed against the reference vectors of the BIP-39 implementation
+	seed := bip39.NewSeed(mnemonic, "TREZOR")
+	if want := common.FromHex("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"); !bytes.Equal(seed, want) {
+		t.Fatalf("seed mismatch: have %x, want %x", seed, want)
+	}
+	// Check the first account along the default derivation path
+	master, err := newMasterKey(bip39.NewSeed(mnemonic, ""))
+	if err != nil {
+		t.Fatalf("failed to create master key: %v", err)
+	}
+	address, err := deriveAddress(master, accounts.DefaultBaseDerivationPath)
+	if err != nil {
+		t.Fatalf("failed to derive address: %v", err)
+	}
+	if want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94"); address != want {
+		t.Errorf("address mismatch: have %x, want %x", address, want)
+	}
+}
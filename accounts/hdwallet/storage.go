@@ -0,0 +1,167 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// walletVersion is the current version of the wallet file format.
+const walletVersion = 1
+
+// walletFile is the on-disk representation of an HD wallet. The mnemonic is kept
+// encrypted, whereas the pinned accounts are stored in plain text, similarly to
+// the addresses of keystore files, so they can be listed without a passphrase.
+type walletFile struct {
+	Address  common.Address      `json:"address"`  // Account at the default base path, identifying the wallet
+	Crypto   keystore.CryptoJSON `json:"crypto"`   // Encrypted mnemonic entropy and BIP-39 passphrase
+	Accounts []pinnedAccount     `json:"accounts"` // Accounts explicitly pinned by the user
+	Version  int                 `json:"version"`
+}
+
+// pinnedAccount is an account derived and pinned by the user.
+type pinnedAccount struct {
+	Address common.Address `json:"address"`
+	Path    string         `json:"path"`
+}
+
+// walletSecret is the plain text content of the encrypted part of a wallet file.
+// The BIP-39 passphrase is stored alongside the mnemonic entropy so that only
+// the wallet passphrase is needed to open the wallet.
+type walletSecret struct {
+	Entropy    hexutil.Bytes `json:"entropy"`
+	Passphrase string        `json:"passphrase"`
+}
+
+// mnemonic returns the BIP-39 mnemonic sentence of the secret.
+func (s *walletSecret) mnemonic() (string, error) {
+	return bip39.NewMnemonic(s.Entropy)
+}
+
+// masterKey computes the BIP-32 master key of the secret.
+func (s *walletSecret) masterKey() (*extendedKey, error) {
+	mnemonic, err := s.mnemonic()
+	if err != nil {
+		return nil, err
+	}
+	return newMasterKey(bip39.NewSeed(mnemonic, s.Passphrase))
+}
+
+// wipe zeroes out the entropy held by the secret.
+func (s *walletSecret) wipe() {
+	for i := range s.Entropy {
+		s.Entropy[i] = 0
+	}
+}
+
+// encryptSecret encrypts a wallet secret with the given passphrase.
+func encryptSecret(secret *walletSecret, passphrase string, kdf keystore.KDF) (keystore.CryptoJSON, error) {
+	blob, err := json.Marshal(secret)
+	if err != nil {
+		return keystore.CryptoJSON{}, err
+	}
+	defer func() {
+		for i := range blob {
+			blob[i] = 0
+		}
+	}()
+	return keystore.EncryptDataWithKDF(blob, []byte(passphrase), kdf)
+}
+
+// decryptSecret decrypts the secret of a wallet file with the given passphrase.
+func decryptSecret(file *walletFile, passphrase string) (*walletSecret, error) {
+	blob, err := keystore.DecryptDataV3(file.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for i := range blob {
+			blob[i] = 0
+		}
+	}()
+	secret := new(walletSecret)
+	if err := json.Unmarshal(blob, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// normalizeMnemonic collapses any whitespace in a user supplied mnemonic into
+// single spaces and converts it to lower case, as expected by BIP-39.
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// loadWalletFile reads and parses a wallet file from disk.
+func loadWalletFile(path string) (*walletFile, error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := new(walletFile)
+	if err := json.Unmarshal(blob, file); err != nil {
+		return nil, err
+	}
+	if file.Version != walletVersion {
+		return nil, fmt.Errorf("unsupported wallet version %d", file.Version)
+	}
+	return file, nil
+}
+
+// storeWalletFile atomically writes a wallet file to disk, creating the wallet
+// directory if it's not present yet.
+func storeWalletFile(path string, file *walletFile) error {
+	blob, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Create a temporary hidden file first, then move it into place
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(blob); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	f.Close()
+	return os.Rename(f.Name(), path)
+}
+
+// walletFileName implements the naming convention for wallet files, matching
+// the one of keystore files: UTC--<created_at UTC ISO8601>--<address hex>
+func walletFileName(addr common.Address) string {
+	ts := time.Now().UTC()
+	return fmt.Sprintf("UTC--%04d-%02d-%02dT%02d-%02d-%02d.%09dZ--%s", ts.Year(), ts.Month(), ts.Day(),
+		ts.Hour(), ts.Minute(), ts.Second(), ts.Nanosecond(), hex.EncodeToString(addr[:]))
+}
@@ -0,0 +1,457 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Minimum time to wait between self derivation attempts, even it the user is
+// requesting accounts like crazy.
+const selfDeriveThrottling = time.Second
+
+// wallet is a software hierarchical deterministic wallet, deriving all of its
+// accounts from a single BIP-39 mnemonic kept encrypted on disk.
+type wallet struct {
+	backend *Backend     // Backend the wallet belongs to, used for event notifications
+	url     accounts.URL // Textual URL uniquely identifying this wallet
+	path    string       // Path of the wallet file on disk
+
+	file   *walletFile  // Last known content of the wallet file
+	master *extendedKey // Decrypted BIP-32 master key, nil if the wallet is closed
+
+	accounts []accounts.Account                         // List of derived accounts pinned or discovered
+	paths    map[common.Address]accounts.DerivationPath // Known derivation paths for signing operations
+
+	deriveNextPath accounts.DerivationPath   // Next derivation path for account auto-discovery
+	deriveNextAddr common.Address            // Next derived account address for auto-discovery
+	deriveChain    ethereum.ChainStateReader // Blockchain state reader to discover used account with
+	deriveTime     time.Time                 // Time instance when the last self-derivation finished
+	deriveEpoch    uint64                    // Counter of SelfDerive calls to detect reconfigurations
+	deriveLock     chan struct{}             // Mutex (buf=1) allowing a single self-derivation at a time
+
+	stateLock sync.RWMutex // Protects read and write access to the wallet struct fields
+
+	log log.Logger // Contextual logger to tag the wallet with its url
+}
+
+// newWallet creates a closed wallet tracking the given wallet file.
+func newWallet(backend *Backend, path string, file *walletFile) *wallet {
+	url := accounts.URL{Scheme: Scheme, Path: path}
+	w := &wallet{
+		backend:    backend,
+		url:        url,
+		path:       path,
+		deriveLock: make(chan struct{}, 1),
+		log:        log.New("url", url),
+	}
+	w.deriveLock <- struct{}{}
+	w.reset(file)
+	return w
+}
+
+// reset replaces the known content of the wallet file, dropping any accounts
+// not pinned in it.
+//
+// Note, reset assumes the state lock is held!
+func (w *wallet) reset(file *walletFile) {
+	w.file = file
+	w.accounts = make([]accounts.Account, 0, len(file.Accounts))
+	w.paths = make(map[common.Address]accounts.DerivationPath)
+
+	for _, pinned := range file.Accounts {
+		path, err := accounts.ParseDerivationPath(pinned.Path)
+		if err != nil {
+			w.log.Warn("Invalid pinned derivation path", "path", pinned.Path, "err", err)
+			continue
+		}
+		w.track(pinned.Address, path)
+	}
+}
+
+// track adds an account to the list of known ones, if not yet present.
+//
+// Note, track assumes the state lock is held!
+func (w *wallet) track(address common.Address, path accounts.DerivationPath) accounts.Account {
+	account := accounts.Account{
+		Address: address,
+		URL:     accounts.URL{Scheme: w.url.Scheme, Path: fmt.Sprintf("%s/%s", w.url.Path, path)},
+	}
+	if _, ok := w.paths[address]; !ok {
+		w.accounts = append(w.accounts, account)
+		w.paths[address] = path
+	}
+	return account
+}
+
+// URL implements accounts.Wallet, returning the URL of the wallet file.
+func (w *wallet) URL() accounts.URL {
+	return w.url // Immutable, no need for a lock
+}
+
+// Status implements accounts.Wallet, returning whether the master key of the
+// wallet is currently decrypted or not.
+func (w *wallet) Status() (string, error) {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	if w.master == nil {
+		return "Locked", nil
+	}
+	return "Unlocked", nil
+}
+
+// Open implements accounts.Wallet, decrypting the mnemonic of the wallet with
+// the given passphrase and keeping the resulting master key in memory until
+// the wallet is closed.
+func (w *wallet) Open(passphrase string) error {
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	if w.master != nil {
+		return accounts.ErrWalletAlreadyOpen
+	}
+	master, err := w.decrypt(passphrase)
+	if err != nil {
+		return err
+	}
+	w.master = master
+
+	// Notify anyone listening for wallet events that the wallet is accessible
+	go w.backend.updateFeed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletOpened})
+
+	return nil
+}
+
+// decrypt decrypts the master key of the wallet with the given passphrase.
+//
+// Note, decrypt assumes the state lock is held!
+func (w *wallet) decrypt(passphrase string) (*extendedKey, error) {
+	secret, err := decryptSecret(w.file, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer secret.wipe()
+
+	return secret.masterKey()
+}
+
+// Close implements accounts.Wallet, wiping the decrypted master key and any
+// accounts self-derived while the wallet was open.
+func (w *wallet) Close() error {
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	if w.master == nil {
+		return nil
+	}
+	w.master.wipe()
+	w.master = nil
+
+	w.reset(w.file)
+	return nil
+}
+
+// Accounts implements accounts.Wallet, returning the list of accounts pinned to
+// the wallet. If self-derivation was enabled, the account list is periodically
+// expanded based on current chain state.
+func (w *wallet) Accounts() []accounts.Account {
+	w.selfDerive()
+
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	cpy := make([]accounts.Account, len(w.accounts))
+	copy(cpy, w.accounts)
+	return cpy
+}
+
+// selfDerive attempts to find new non-zero accounts, starting from the next
+// derivation path. It is a noop if self-derivation is disabled, the wallet is
+// closed or if a derivation is already in progress or recently finished.
+func (w *wallet) selfDerive() {
+	select {
+	case <-w.deriveLock:
+		defer func() { w.deriveLock <- struct{}{} }()
+	default:
+		return
+	}
+	// Gather the derivation state, skipping if not needed
+	w.stateLock.RLock()
+	if w.master == nil || w.deriveChain == nil || time.Since(w.deriveTime) < selfDeriveThrottling {
+		w.stateLock.RUnlock()
+		return
+	}
+	var (
+		accs  []common.Address
+		paths []accounts.DerivationPath
+
+		opened   = w.master
+		master   = &extendedKey{key: common.CopyBytes(w.master.key), chain: common.CopyBytes(w.master.chain)}
+		chain    = w.deriveChain
+		epoch    = w.deriveEpoch
+		nextAddr = w.deriveNextAddr
+		nextPath = make(accounts.DerivationPath, len(w.deriveNextPath))
+
+		context = context.Background()
+	)
+	copy(nextPath, w.deriveNextPath)
+	w.stateLock.RUnlock()
+
+	defer master.wipe()
+
+	// Derive accounts until an empty one is found
+	for empty := false; !empty; {
+		// Retrieve the next derived Ethereum account
+		if nextAddr == (common.Address{}) {
+			addr, err := deriveAddress(master, nextPath)
+			if err != nil {
+				w.log.Warn("HD wallet account derivation failed", "err", err)
+				break
+			}
+			nextAddr = addr
+		}
+		// Check the account's status against the current chain state
+		balance, err := chain.BalanceAt(context, nextAddr, nil)
+		if err != nil {
+			w.log.Warn("HD wallet balance retrieval failed", "err", err)
+			break
+		}
+		nonce, err := chain.NonceAt(context, nextAddr, nil)
+		if err != nil {
+			w.log.Warn("HD wallet nonce retrieval failed", "err", err)
+			break
+		}
+		// If the next account is empty, stop self-derivation, but add it nonetheless
+		if balance.Sign() == 0 && nonce == 0 {
+			empty = true
+		}
+		path := make(accounts.DerivationPath, len(nextPath))
+		copy(path, nextPath)
+
+		accs = append(accs, nextAddr)
+		paths = append(paths, path)
+
+		// Fetch the next potential account
+		if !empty {
+			nextAddr = common.Address{}
+			nextPath[len(nextPath)-1]++
+		}
+	}
+	// Insert any accounts successfully derived, unless the wallet was closed or
+	// the derivation reconfigured in the mean time
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	w.deriveTime = time.Now()
+	if w.master != opened || w.deriveEpoch != epoch {
+		return
+	}
+	for i, addr := range accs {
+		if _, known := w.paths[addr]; !known {
+			w.log.Info("HD wallet discovered new account", "address", addr, "path", paths[i])
+		}
+		w.track(addr, paths[i])
+	}
+	w.deriveNextAddr = nextAddr
+	w.deriveNextPath = nextPath
+}
+
+// Contains implements accounts.Wallet, returning whether a particular account is
+// or is not pinned into this wallet instance.
+func (w *wallet) Contains(account accounts.Account) bool {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	_, exists := w.paths[account.Address]
+	return exists
+}
+
+// Derive implements accounts.Wallet, deriving a new account at the specific
+// derivation path. If pin is set to true, the account will be added to the list
+// of tracked accounts and persisted into the wallet file.
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	if w.master == nil {
+		return accounts.Account{}, accounts.ErrWalletClosed
+	}
+	address, err := deriveAddress(w.master, path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	account := accounts.Account{
+		Address: address,
+		URL:     accounts.URL{Scheme: w.url.Scheme, Path: fmt.Sprintf("%s/%s", w.url.Path, path)},
+	}
+	if !pin {
+		return account, nil
+	}
+	// Persist the pinned account unless already done so previously
+	for _, pinned := range w.file.Accounts {
+		if pinned.Address == address {
+			return w.track(address, path), nil
+		}
+	}
+	file := *w.file
+	file.Accounts = append(append([]pinnedAccount{}, w.file.Accounts...), pinnedAccount{Address: address, Path: path.String()})
+	if err := storeWalletFile(w.path, &file); err != nil {
+		return accounts.Account{}, err
+	}
+	w.file = &file
+
+	return w.track(address, path), nil
+}
+
+// SelfDerive implements accounts.Wallet, trying to discover accounts that the
+// user used previously (based on the chain state), but ones that he/she did not
+// explicitly pin to the wallet manually. To avoid chain head monitoring, self
+// derivation only runs during account listing (and even then throttled).
+func (w *wallet) SelfDerive(base accounts.DerivationPath, chain ethereum.ChainStateReader) {
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	w.deriveNextPath = make(accounts.DerivationPath, len(base))
+	copy(w.deriveNextPath[:], base[:])
+
+	w.deriveNextAddr = common.Address{}
+	w.deriveChain = chain
+	w.deriveTime = time.Time{}
+	w.deriveEpoch++
+}
+
+// signingKey derives the private key of a known account, either with the master
+// key of the open wallet, or by decrypting the wallet with the given passphrase.
+func (w *wallet) signingKey(account accounts.Account, passphrase *string) (*ecdsa.PrivateKey, error) {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	path, ok := w.paths[account.Address]
+	if !ok {
+		return nil, accounts.ErrUnknownAccount
+	}
+	master := w.master
+	if passphrase != nil {
+		var err error
+		if master, err = w.decrypt(*passphrase); err != nil {
+			return nil, err
+		}
+		defer master.wipe()
+	}
+	if master == nil {
+		return nil, accounts.ErrWalletClosed
+	}
+	child, err := master.derive(path)
+	if err != nil {
+		return nil, err
+	}
+	defer child.wipe()
+
+	return child.privateKey()
+}
+
+// signHash derives the key of the given account and signs the hash with it.
+func (w *wallet) signHash(account accounts.Account, passphrase *string, hash []byte) ([]byte, error) {
+	key, err := w.signingKey(account, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+
+	return crypto.Sign(hash, key)
+}
+
+// signTx derives the key of the given account and signs the transaction with it.
+func (w *wallet) signTx(account accounts.Account, passphrase *string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	key, err := w.signingKey(account, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+
+	// Depending on the presence of the chain ID, sign with EIP155 or homestead
+	if chainID != nil {
+		return types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+	}
+	return types.SignTx(tx, types.HomesteadSigner{}, key)
+}
+
+// SignHash implements accounts.Wallet, signing the given hash with the key of
+// the account. The wallet needs to be open.
+func (w *wallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	return w.signHash(account, nil, hash)
+}
+
+// SignTx implements accounts.Wallet, signing the given transaction with the key
+// of the account. The wallet needs to be open.
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.signTx(account, nil, tx, chainID)
+}
+
+// SignTypedData implements accounts.Wallet, signing the hash of the given EIP-712
+// structured data with the key of the account. The wallet needs to be open.
+func (w *wallet) SignTypedData(account accounts.Account, domainSeparator, messageHash []byte) ([]byte, error) {
+	return w.signHash(account, nil, accounts.TypedDataHash(domainSeparator, messageHash))
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, decrypting the wallet with
+// the given passphrase for the duration of signing the hash.
+func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return w.signHash(account, &passphrase, hash)
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, decrypting the wallet with
+// the given passphrase for the duration of signing the transaction.
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.signTx(account, &passphrase, tx, chainID)
+}
+
+// SignTypedDataWithPassphrase implements accounts.Wallet, decrypting the wallet
+// with the given passphrase for the duration of signing the structured data.
+func (w *wallet) SignTypedDataWithPassphrase(account accounts.Account, passphrase string, domainSeparator, messageHash []byte) ([]byte, error) {
+	return w.signHash(account, &passphrase, accounts.TypedDataHash(domainSeparator, messageHash))
+}
+
+// deriveAddress derives the Ethereum address at the given path below the key.
+func deriveAddress(master *extendedKey, path accounts.DerivationPath) (common.Address, error) {
+	child, err := master.derive(path)
+	if err != nil {
+		return common.Address{}, err
+	}
+	defer child.wipe()
+
+	key, err := child.privateKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	defer zeroKey(key)
+
+	return crypto.PubkeyToAddress(key.PublicKey), nil
+}
@@ -41,12 +41,14 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/randentropy"
 	"github.com/pborman/uuid"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/scrypt"
 )
 
 const (
-	keyHeaderKDF = "scrypt"
+	keyHeaderKDF         = "scrypt"
+	keyHeaderKDFArgon2id = "argon2id"
 
 	// StandardScryptN is the N parameter of Scrypt encryption algorithm, using 256MB
 	// memory and taking approximately 1s CPU time on a modern processor.
@@ -66,12 +68,68 @@ const (
 
 	scryptR     = 8
 	scryptDKLen = 32
+
+	// StandardArgon2idTime is the number of passes over the memory of Argon2id,
+	// using 256MB memory and taking approximately 1s CPU time on a modern processor.
+	StandardArgon2idTime = 3
+
+	// StandardArgon2idMemory is the memory size of Argon2id in KiB, using 256MB
+	// memory and taking approximately 1s CPU time on a modern processor.
+	StandardArgon2idMemory = 256 * 1024
+
+	// LightArgon2idTime is the number of passes over the memory of Argon2id,
+	// using 4MB memory and taking approximately 100ms CPU time on a modern processor.
+	LightArgon2idTime = 24
+
+	// LightArgon2idMemory is the memory size of Argon2id in KiB, using 4MB
+	// memory and taking approximately 100ms CPU time on a modern processor.
+	LightArgon2idMemory = 4 * 1024
+
+	argon2idThreads = 4
+	argon2idDKLen   = 32
+
+	// Upper bounds of the Argon2id parameters accepted from key files, preventing
+	// crafted ones from exhausting the memory or CPU of the node on decryption.
+	maxArgon2idTime    = 64
+	maxArgon2idMemory  = 1024 * 1024 // 1GB, four times the standard memory size
+	maxArgon2idThreads = 16
+	maxArgon2idDKLen   = 64
+)
+
+// Names of the key derivation functions supported to encrypt keys with.
+const (
+	KDFScrypt   = keyHeaderKDF
+	KDFArgon2id = keyHeaderKDFArgon2id
 )
 
+// KDF is the key derivation function configuration used to derive the keys
+// encrypting newly stored keys from their passphrases.
+type KDF struct {
+	Name string // Key derivation function to use, "scrypt" or "argon2id"
+
+	ScryptN int // CPU/memory cost parameter of scrypt
+	ScryptP int // Parallelization parameter of scrypt
+
+	Argon2Time    uint32 // Number of passes over the memory of Argon2id
+	Argon2Memory  uint32 // Memory size of Argon2id in KiB
+	Argon2Threads uint8  // Degree of parallelism of Argon2id
+}
+
+// ScryptKDF creates a key derivation function configuration using scrypt with
+// the given N and P parameters.
+func ScryptKDF(scryptN, scryptP int) KDF {
+	return KDF{Name: keyHeaderKDF, ScryptN: scryptN, ScryptP: scryptP}
+}
+
+// Argon2idKDF creates a key derivation function configuration using Argon2id
+// with the given number of passes and memory size in KiB.
+func Argon2idKDF(time, memory uint32) KDF {
+	return KDF{Name: keyHeaderKDFArgon2id, Argon2Time: time, Argon2Memory: memory, Argon2Threads: argon2idThreads}
+}
+
 type keyStorePassphrase struct {
 	keysDirPath string
-	scryptN     int
-	scryptP     int
+	kdf         KDF
 }
 
 func (ks keyStorePassphrase) GetKey(addr common.Address, filename, auth string) (*Key, error) {
@@ -93,12 +151,18 @@ func (ks keyStorePassphrase) GetKey(addr common.Address, filename, auth string)
 
 // StoreKey generates a key, encrypts with 'auth' and stores in the given directory
 func StoreKey(dir, auth string, scryptN, scryptP int) (common.Address, error) {
-	_, a, err := storeNewKey(&keyStorePassphrase{dir, scryptN, scryptP}, crand.Reader, auth)
+	return StoreKeyWithKDF(dir, auth, ScryptKDF(scryptN, scryptP))
+}
+
+// StoreKeyWithKDF generates a key, encrypts with 'auth' using the given key
+// derivation function and stores in the given directory
+func StoreKeyWithKDF(dir, auth string, kdf KDF) (common.Address, error) {
+	_, a, err := storeNewKey(&keyStorePassphrase{dir, kdf}, crand.Reader, auth)
 	return a.Address, err
 }
 
 func (ks keyStorePassphrase) StoreKey(filename string, key *Key, auth string) error {
-	keyjson, err := EncryptKey(key, auth, ks.scryptN, ks.scryptP)
+	keyjson, err := EncryptKeyWithKDF(key, auth, ks.kdf)
 	if err != nil {
 		return err
 	}
@@ -113,44 +177,101 @@ func (ks keyStorePassphrase) JoinPath(filename string) string {
 	}
 }
 
-// EncryptKey encrypts a key using the specified scrypt parameters into a json
-// blob that can be decrypted later on.
-func EncryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
-	authArray := []byte(auth)
+// EncryptDataV3 encrypts the data given as 'data' with the password 'auth'.
+func EncryptDataV3(data, auth []byte, scryptN, scryptP int) (CryptoJSON, error) {
+	return EncryptDataWithKDF(data, auth, ScryptKDF(scryptN, scryptP))
+}
+
+// EncryptDataWithKDF encrypts the data given as 'data' with the password 'auth',
+// deriving the encryption key with the given key derivation function.
+func EncryptDataWithKDF(data, auth []byte, kdf KDF) (CryptoJSON, error) {
 	salt := randentropy.GetEntropyCSPRNG(32)
-	derivedKey, err := scrypt.Key(authArray, salt, scryptN, scryptR, scryptP, scryptDKLen)
+	derivedKey, kdfParamsJSON, err := deriveKey(auth, salt, kdf)
 	if err != nil {
-		return nil, err
+		return CryptoJSON{}, err
 	}
 	encryptKey := derivedKey[:16]
-	keyBytes := math.PaddedBigBytes(key.PrivateKey.D, 32)
 
 	iv := randentropy.GetEntropyCSPRNG(aes.BlockSize) // 16
-	cipherText, err := aesCTRXOR(encryptKey, keyBytes, iv)
+	cipherText, err := aesCTRXOR(encryptKey, data, iv)
 	if err != nil {
-		return nil, err
+		return CryptoJSON{}, err
 	}
 	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
 
-	scryptParamsJSON := make(map[string]interface{}, 5)
-	scryptParamsJSON["n"] = scryptN
-	scryptParamsJSON["r"] = scryptR
-	scryptParamsJSON["p"] = scryptP
-	scryptParamsJSON["dklen"] = scryptDKLen
-	scryptParamsJSON["salt"] = hex.EncodeToString(salt)
-
 	cipherParamsJSON := cipherparamsJSON{
 		IV: hex.EncodeToString(iv),
 	}
-
-	cryptoStruct := cryptoJSON{
+	cryptoStruct := CryptoJSON{
 		Cipher:       "aes-128-ctr",
 		CipherText:   hex.EncodeToString(cipherText),
 		CipherParams: cipherParamsJSON,
-		KDF:          keyHeaderKDF,
-		KDFParams:    scryptParamsJSON,
+		KDF:          kdf.Name,
+		KDFParams:    kdfParamsJSON,
 		MAC:          hex.EncodeToString(mac),
 	}
+	return cryptoStruct, nil
+}
+
+// deriveKey derives the encryption key from the password 'auth' with the given
+// key derivation function, returning it along with the parameters to store for
+// the decryption.
+func deriveKey(auth, salt []byte, kdf KDF) ([]byte, map[string]interface{}, error) {
+	switch kdf.Name {
+	case keyHeaderKDF:
+		derivedKey, err := scrypt.Key(auth, salt, kdf.ScryptN, scryptR, kdf.ScryptP, scryptDKLen)
+		if err != nil {
+			return nil, nil, err
+		}
+		scryptParamsJSON := make(map[string]interface{}, 5)
+		scryptParamsJSON["n"] = kdf.ScryptN
+		scryptParamsJSON["r"] = scryptR
+		scryptParamsJSON["p"] = kdf.ScryptP
+		scryptParamsJSON["dklen"] = scryptDKLen
+		scryptParamsJSON["salt"] = hex.EncodeToString(salt)
+		return derivedKey, scryptParamsJSON, nil
+
+	case keyHeaderKDFArgon2id:
+		if err := validateArgon2id(int(kdf.Argon2Time), int(kdf.Argon2Memory), int(kdf.Argon2Threads), argon2idDKLen); err != nil {
+			return nil, nil, err
+		}
+		derivedKey := argon2.IDKey(auth, salt, kdf.Argon2Time, kdf.Argon2Memory, kdf.Argon2Threads, argon2idDKLen)
+
+		argon2ParamsJSON := make(map[string]interface{}, 5)
+		argon2ParamsJSON["t"] = kdf.Argon2Time
+		argon2ParamsJSON["m"] = kdf.Argon2Memory
+		argon2ParamsJSON["p"] = kdf.Argon2Threads
+		argon2ParamsJSON["dklen"] = argon2idDKLen
+		argon2ParamsJSON["salt"] = hex.EncodeToString(salt)
+		return derivedKey, argon2ParamsJSON, nil
+	}
+	return nil, nil, fmt.Errorf("Unsupported KDF: %s", kdf.Name)
+}
+
+// validateArgon2id checks that the Argon2id parameters are within sane bounds,
+// keeping the derivation affordable, and derive a key long enough for the MAC.
+func validateArgon2id(time, memory, threads, dkLen int) error {
+	if time < 1 || time > maxArgon2idTime || memory < 1 || memory > maxArgon2idMemory ||
+		threads < 1 || threads > maxArgon2idThreads || dkLen < 32 || dkLen > maxArgon2idDKLen {
+		return fmt.Errorf("Invalid Argon2id parameters: t=%d, m=%d, p=%d, dklen=%d", time, memory, threads, dkLen)
+	}
+	return nil
+}
+
+// EncryptKey encrypts a key using the specified scrypt parameters into a json
+// blob that can be decrypted later on.
+func EncryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
+	return EncryptKeyWithKDF(key, auth, ScryptKDF(scryptN, scryptP))
+}
+
+// EncryptKeyWithKDF encrypts a key using the specified key derivation function
+// into a json blob that can be decrypted later on.
+func EncryptKeyWithKDF(key *Key, auth string, kdf KDF) ([]byte, error) {
+	keyBytes := math.PaddedBigBytes(key.PrivateKey.D, 32)
+	cryptoStruct, err := EncryptDataWithKDF(keyBytes, []byte(auth), kdf)
+	if err != nil {
+		return nil, err
+	}
 	encryptedKeyJSONV3 := encryptedKeyJSONV3{
 		hex.EncodeToString(key.Address[:]),
 		cryptoStruct,
@@ -198,42 +319,50 @@ func DecryptKey(keyjson []byte, auth string) (*Key, error) {
 	}, nil
 }
 
-func decryptKeyV3(keyProtected *encryptedKeyJSONV3, auth string) (keyBytes []byte, keyId []byte, err error) {
-	if keyProtected.Version != version {
-		return nil, nil, fmt.Errorf("Version not supported: %v", keyProtected.Version)
+// DecryptDataV3 decrypts the data encrypted by EncryptDataV3 with the password
+// 'auth'.
+func DecryptDataV3(cryptoJson CryptoJSON, auth string) ([]byte, error) {
+	if cryptoJson.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("Cipher not supported: %v", cryptoJson.Cipher)
 	}
-
-	if keyProtected.Crypto.Cipher != "aes-128-ctr" {
-		return nil, nil, fmt.Errorf("Cipher not supported: %v", keyProtected.Crypto.Cipher)
-	}
-
-	keyId = uuid.Parse(keyProtected.Id)
-	mac, err := hex.DecodeString(keyProtected.Crypto.MAC)
+	mac, err := hex.DecodeString(cryptoJson.MAC)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	iv, err := hex.DecodeString(keyProtected.Crypto.CipherParams.IV)
+	iv, err := hex.DecodeString(cryptoJson.CipherParams.IV)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	cipherText, err := hex.DecodeString(keyProtected.Crypto.CipherText)
+	cipherText, err := hex.DecodeString(cryptoJson.CipherText)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	derivedKey, err := getKDFKey(keyProtected.Crypto, auth)
+	derivedKey, err := getKDFKey(cryptoJson, auth)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	calculatedMAC := crypto.Keccak256(derivedKey[16:32], cipherText)
 	if !bytes.Equal(calculatedMAC, mac) {
-		return nil, nil, ErrDecrypt
+		return nil, ErrDecrypt
 	}
 
 	plainText, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return nil, err
+	}
+	return plainText, err
+}
+
+func decryptKeyV3(keyProtected *encryptedKeyJSONV3, auth string) (keyBytes []byte, keyId []byte, err error) {
+	if keyProtected.Version != version {
+		return nil, nil, fmt.Errorf("Version not supported: %v", keyProtected.Version)
+	}
+	keyId = uuid.Parse(keyProtected.Id)
+	plainText, err := DecryptDataV3(keyProtected.Crypto, auth)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -274,7 +403,7 @@ func decryptKeyV1(keyProtected *encryptedKeyJSONV1, auth string) (keyBytes []byt
 	return plainText, keyId, err
 }
 
-func getKDFKey(cryptoJSON cryptoJSON, auth string) ([]byte, error) {
+func getKDFKey(cryptoJSON CryptoJSON, auth string) ([]byte, error) {
 	authArray := []byte(auth)
 	salt, err := hex.DecodeString(cryptoJSON.KDFParams["salt"].(string))
 	if err != nil {
@@ -296,6 +425,15 @@ func getKDFKey(cryptoJSON cryptoJSON, auth string) ([]byte, error) {
 		}
 		key := pbkdf2.Key(authArray, salt, c, dkLen, sha256.New)
 		return key, nil
+
+	} else if cryptoJSON.KDF == keyHeaderKDFArgon2id {
+		t := ensureInt(cryptoJSON.KDFParams["t"])
+		m := ensureInt(cryptoJSON.KDFParams["m"])
+		p := ensureInt(cryptoJSON.KDFParams["p"])
+		if err := validateArgon2id(t, m, p, dkLen); err != nil {
+			return nil, err
+		}
+		return argon2.IDKey(authArray, salt, uint32(t), uint32(m), uint8(p), uint32(dkLen)), nil
 	}
 
 	return nil, fmt.Errorf("Unsupported KDF: %s", cryptoJSON.KDF)
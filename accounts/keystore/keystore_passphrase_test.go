@@ -17,6 +17,7 @@
 package keystore
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"testing"
 
@@ -58,3 +59,56 @@ func TestKeyEncryptDecrypt(t *testing.T) {
 		}
 	}
 }
+
+// Tests that keys encrypted with Argon2id can be decrypted, and that keys can be
+// migrated between key derivation functions.
+func TestKeyEncryptDecryptArgon2id(t *testing.T) {
+	keyjson, err := ioutil.ReadFile("testdata/very-light-scrypt.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := DecryptKey(keyjson, "")
+	if err != nil {
+		t.Fatalf("json key failed to decrypt: %v", err)
+	}
+	// Re-encrypt the scrypt protected key with Argon2id and decrypt it again
+	if keyjson, err = EncryptKeyWithKDF(key, "foo", Argon2idKDF(1, 64)); err != nil {
+		t.Fatalf("failed to encrypt key with argon2id: %v", err)
+	}
+	var header struct {
+		Crypto CryptoJSON `json:"crypto"`
+	}
+	if err := json.Unmarshal(keyjson, &header); err != nil {
+		t.Fatalf("failed to parse encrypted key: %v", err)
+	}
+	if header.Crypto.KDF != KDFArgon2id {
+		t.Fatalf("kdf mismatch: have %s, want %s", header.Crypto.KDF, KDFArgon2id)
+	}
+	if _, err := DecryptKey(keyjson, "bar"); err != ErrDecrypt {
+		t.Fatalf("bad password error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+	decrypted, err := DecryptKey(keyjson, "foo")
+	if err != nil {
+		t.Fatalf("argon2id key failed to decrypt: %v", err)
+	}
+	if decrypted.Address != key.Address || decrypted.PrivateKey.D.Cmp(key.PrivateKey.D) != 0 {
+		t.Fatalf("decrypted key mismatch: have %x, want %x", decrypted.Address, key.Address)
+	}
+	// Ensure invalid parameters are rejected instead of crashing the derivation
+	if _, err := EncryptKeyWithKDF(key, "foo", Argon2idKDF(0, 64)); err == nil {
+		t.Fatalf("encrypted key with zero argon2id passes")
+	}
+	for param, value := range map[string]int{"p": 0, "t": maxArgon2idTime + 1, "m": maxArgon2idMemory + 1, "dklen": maxArgon2idDKLen + 1} {
+		params := make(map[string]interface{})
+		for k, v := range header.Crypto.KDFParams {
+			params[k] = v
+		}
+		params[param] = value
+
+		crypto := header.Crypto
+		crypto.KDFParams = params
+		if _, err := DecryptDataV3(crypto, "foo"); err == nil {
+			t.Fatalf("decrypted key with argon2id %s=%d", param, value)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 )
 
@@ -113,6 +114,34 @@ func TestSignWithPassphrase(t *testing.T) {
 	}
 }
 
+func TestSignTypedData(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	pass := "passwd"
+	acc, err := ks.NewAccount(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet := &keystoreWallet{account: acc, keystore: ks}
+
+	domain, message := crypto.Keccak256([]byte("domain")), crypto.Keccak256([]byte("message"))
+	if _, err := wallet.SignTypedData(acc, domain, message); err == nil {
+		t.Fatal("expected SignTypedData to fail with locked account")
+	}
+	sig, err := wallet.SignTypedDataWithPassphrase(acc, pass, domain, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := crypto.SigToPub(accounts.TypedDataHash(domain, message), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer := crypto.PubkeyToAddress(*pubkey); signer != acc.Address {
+		t.Fatalf("signer mismatch: have %x, want %x", signer, acc.Address)
+	}
+}
+
 func TestTimedUnlock(t *testing.T) {
 	dir, ks := tmpKeyStore(t, true)
 	defer os.RemoveAll(dir)
@@ -374,6 +403,39 @@ func checkEvents(t *testing.T, want []walletEvent, have []walletEvent) {
 	}
 }
 
+// Tests that updating an account re-encrypts it with the key derivation function
+// of the keystore, keeping it usable from keystores configured differently.
+func TestUpdateKDF(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	a, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	argon := NewKeyStoreWithKDF(dir, Argon2idKDF(1, 64))
+	if err := argon.Update(a, "foo", "bar"); err != nil {
+		t.Fatalf("failed to update account: %v", err)
+	}
+	keyjson, err := ioutil.ReadFile(a.URL.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(keyjson), `"kdf":"argon2id"`) {
+		t.Fatalf("account not re-encrypted with argon2id: %s", keyjson)
+	}
+	// The original scrypt keystore should still be able to use the account
+	if err := ks.Unlock(a, "bar"); err != nil {
+		t.Fatalf("failed to unlock updated account: %v", err)
+	}
+	if err := ks.Update(a, "bar", "baz"); err != nil {
+		t.Fatalf("failed to update account back: %v", err)
+	}
+	if keyjson, _ = ioutil.ReadFile(a.URL.Path); !strings.Contains(string(keyjson), `"kdf":"scrypt"`) {
+		t.Fatalf("account not re-encrypted with scrypt: %s", keyjson)
+	}
+}
+
 func tmpKeyStore(t *testing.T, encrypted bool) (string, *KeyStore) {
 	d, err := ioutil.TempDir("", "eth-keystore-test")
 	if err != nil {
@@ -137,3 +137,17 @@ func (w *keystoreWallet) SignTxWithPassphrase(account accounts.Account, passphra
 	// Account seems valid, request the keystore to sign
 	return w.keystore.SignTxWithPassphrase(account, passphrase, tx, chainID)
 }
+
+// SignTypedData implements accounts.Wallet, attempting to sign the digest of the
+// given EIP-712 structured data with the given account. If the wallet does not
+// wrap this particular account, an error is returned to avoid account leakage.
+func (w *keystoreWallet) SignTypedData(account accounts.Account, domainSeparator, messageHash []byte) ([]byte, error) {
+	return w.SignHash(account, accounts.TypedDataHash(domainSeparator, messageHash))
+}
+
+// SignTypedDataWithPassphrase implements accounts.Wallet, attempting to sign the
+// digest of the given EIP-712 structured data with the given account using the
+// passphrase as extra authentication.
+func (w *keystoreWallet) SignTypedDataWithPassphrase(account accounts.Account, passphrase string, domainSeparator, messageHash []byte) ([]byte, error) {
+	return w.SignHashWithPassphrase(account, passphrase, accounts.TypedDataHash(domainSeparator, messageHash))
+}
@@ -70,6 +70,19 @@ func NewManager(backends ...Backend) *Manager {
 	return am
 }
 
+// AddBackend registers an additional backend with the account manager, merging
+// its wallets into the cache and listening for its wallet notifications.
+func (am *Manager) AddBackend(backend Backend) {
+	am.lock.Lock()
+	defer am.lock.Unlock()
+
+	kind := reflect.TypeOf(backend)
+	am.backends[kind] = append(am.backends[kind], backend)
+
+	am.wallets = merge(am.wallets, backend.Wallets()...)
+	am.updaters = append(am.updaters, backend.Subscribe(am.updates))
+}
+
 // Close terminates the account manager's internal notification processes.
 func (am *Manager) Close() error {
 	errc := make(chan error)
@@ -117,6 +130,9 @@ func (am *Manager) update() {
 
 // Backends retrieves the backend(s) with the given type from the account manager.
 func (am *Manager) Backends(kind reflect.Type) []Backend {
+	am.lock.RLock()
+	defer am.lock.RUnlock()
+
 	return am.backends[kind]
 }
 
@@ -139,7 +155,7 @@ func (am *Manager) Wallet(url string) (Wallet, error) {
 	if err != nil {
 		return nil, err
 	}
-	for _, wallet := range am.Wallets() {
+	for _, wallet := range am.wallets {
 		if wallet.URL() == parsed {
 			return wallet, nil
 		}
@@ -0,0 +1,428 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// typedDataDomainType is the name of the type describing the signing domain of
+// an EIP-712 structured data.
+const typedDataDomainType = "EIP712Domain"
+
+var (
+	// typedDataArrayRegexp matches the trailing dimension of an array type, e.g.
+	// the "[3]" of "Person[][3]".
+	typedDataArrayRegexp = regexp.MustCompile(`^(.+)\[([0-9]*)\]$`)
+
+	// typedDataIntRegexp matches the sized integer types, e.g. "uint256".
+	typedDataIntRegexp = regexp.MustCompile(`^(u?)int([0-9]*)$`)
+
+	// typedDataBytesRegexp matches the fixed size byte array types, e.g. "bytes32".
+	typedDataBytesRegexp = regexp.MustCompile(`^bytes([0-9]+)$`)
+
+	// typedDataNameRegexp matches the valid names of struct types.
+	typedDataNameRegexp = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*$`)
+)
+
+// TypedData is a structured data to sign, as defined by EIP-712. It contains the
+// definitions of all the struct types involved, the signing domain separating
+// the data of different applications, and the message itself.
+type TypedData struct {
+	Types       TypedDataTypes   `json:"types"`
+	PrimaryType string           `json:"primaryType"`
+	Domain      TypedDataMessage `json:"domain"`
+	Message     TypedDataMessage `json:"message"`
+}
+
+// TypedDataTypes maps the struct type names to their member definitions.
+type TypedDataTypes map[string][]TypedDataField
+
+// TypedDataField is a single named and typed member of a struct type.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataMessage is the value of a struct, mapping its member names to values.
+type TypedDataMessage map[string]interface{}
+
+// UnmarshalJSON parses a structured data, retaining the exact numeric values
+// instead of converting them to floating point.
+func (typedData *TypedData) UnmarshalJSON(input []byte) error {
+	type typedDataJSON TypedData
+
+	dec := json.NewDecoder(bytes.NewReader(input))
+	dec.UseNumber()
+
+	var data typedDataJSON
+	if err := dec.Decode(&data); err != nil {
+		return err
+	}
+	*typedData = TypedData(data)
+	return nil
+}
+
+// Validate checks that the structured data is well formed: all the referenced
+// types are defined and the domain and primary types are present.
+func (typedData *TypedData) Validate() error {
+	if _, ok := typedData.Types[typedDataDomainType]; !ok {
+		return fmt.Errorf("missing %s type definition", typedDataDomainType)
+	}
+	if _, ok := typedData.Types[typedData.PrimaryType]; !ok {
+		return fmt.Errorf("primary type %q undefined", typedData.PrimaryType)
+	}
+	for name, fields := range typedData.Types {
+		if !typedDataNameRegexp.MatchString(name) || isAtomicType(name) || isDynamicType(name) {
+			return fmt.Errorf("invalid struct type name %q", name)
+		}
+		seen := make(map[string]bool)
+		for _, field := range fields {
+			if field.Name == "" {
+				return fmt.Errorf("type %s: unnamed field", name)
+			}
+			if seen[field.Name] {
+				return fmt.Errorf("type %s: duplicate field %q", name, field.Name)
+			}
+			seen[field.Name] = true
+
+			base := field.Type
+			for {
+				parts := typedDataArrayRegexp.FindStringSubmatch(base)
+				if parts == nil {
+					break
+				}
+				base = parts[1]
+			}
+			if _, ok := typedData.Types[base]; !ok && !isAtomicType(base) && !isDynamicType(base) {
+				return fmt.Errorf("type %s: field %q has undefined type %q", name, field.Name, field.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// Dependencies returns the names of all the struct types the given type refers
+// to, directly or transitively, including itself.
+func (typedData *TypedData) Dependencies(primaryType string) []string {
+	found := make(map[string]bool)
+	typedData.dependencies(primaryType, found)
+
+	deps := make([]string, 0, len(found))
+	for dep := range found {
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// dependencies collects the struct types referenced by primaryType into found.
+func (typedData *TypedData) dependencies(primaryType string, found map[string]bool) {
+	if parts := typedDataArrayRegexp.FindStringSubmatch(primaryType); parts != nil {
+		primaryType = parts[1]
+		typedData.dependencies(primaryType, found)
+		return
+	}
+	if found[primaryType] {
+		return
+	}
+	fields, ok := typedData.Types[primaryType]
+	if !ok {
+		return
+	}
+	found[primaryType] = true
+	for _, field := range fields {
+		typedData.dependencies(field.Type, found)
+	}
+}
+
+// EncodeType generates the canonical string encoding of a struct type, i.e. its
+// own definition followed by all its dependencies sorted by name:
+//
+//	Mail(Person from,Person to,string contents)Person(string name,address wallet)
+func (typedData *TypedData) EncodeType(primaryType string) string {
+	deps := typedData.Dependencies(primaryType)
+
+	sorted := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		if dep != primaryType {
+			sorted = append(sorted, dep)
+		}
+	}
+	sort.Strings(sorted)
+	sorted = append([]string{primaryType}, sorted...)
+
+	var buffer bytes.Buffer
+	for _, dep := range sorted {
+		buffer.WriteString(dep)
+		buffer.WriteString("(")
+		for i, field := range typedData.Types[dep] {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString(field.Type)
+			buffer.WriteString(" ")
+			buffer.WriteString(field.Name)
+		}
+		buffer.WriteString(")")
+	}
+	return buffer.String()
+}
+
+// TypeHash returns the Keccak256 hash of the canonical encoding of a type.
+func (typedData *TypedData) TypeHash(primaryType string) []byte {
+	return crypto.Keccak256([]byte(typedData.EncodeType(primaryType)))
+}
+
+// EncodeData encodes the members of a struct value, each into a 32 byte word,
+// prefixed with the type hash of the struct.
+func (typedData *TypedData) EncodeData(primaryType string, data map[string]interface{}) ([]byte, error) {
+	fields, ok := typedData.Types[primaryType]
+	if !ok {
+		return nil, fmt.Errorf("type %q undefined", primaryType)
+	}
+	if len(data) > len(fields) {
+		return nil, fmt.Errorf("type %s: %d values provided for %d fields", primaryType, len(data), len(fields))
+	}
+	buffer := bytes.NewBuffer(typedData.TypeHash(primaryType))
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("type %s: missing value for field %q", primaryType, field.Name)
+		}
+		encoded, err := typedData.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: field %q: %v", primaryType, field.Name, err)
+		}
+		buffer.Write(encoded)
+	}
+	return buffer.Bytes(), nil
+}
+
+// HashStruct returns the Keccak256 hash of the encoding of a struct value.
+func (typedData *TypedData) HashStruct(primaryType string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := typedData.EncodeData(primaryType, data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(encoded), nil
+}
+
+// Hashes validates the structured data and returns the domain separator and the
+// struct hash of the message, from which the digest to sign is derived.
+func (typedData *TypedData) Hashes() (domainSeparator []byte, messageHash []byte, err error) {
+	if err := typedData.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if domainSeparator, err = typedData.HashStruct(typedDataDomainType, typedData.Domain); err != nil {
+		return nil, nil, fmt.Errorf("invalid domain: %v", err)
+	}
+	if messageHash, err = typedData.HashStruct(typedData.PrimaryType, typedData.Message); err != nil {
+		return nil, nil, fmt.Errorf("invalid message: %v", err)
+	}
+	return domainSeparator, messageHash, nil
+}
+
+// TypedDataHash calculates the digest to sign for an EIP-712 structured data
+// from its domain separator and message struct hash:
+//
+//	keccak256("\x19\x01" ‖ domainSeparator ‖ messageHash)
+func TypedDataHash(domainSeparator, messageHash []byte) []byte {
+	return crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, messageHash)
+}
+
+// encodeValue encodes a single value of the given type into a 32 byte word.
+// Structs and dynamic types are represented by their hash.
+func (typedData *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	// Arrays are encoded as the hash of their concatenated encoded elements
+	if parts := typedDataArrayRegexp.FindStringSubmatch(typ); parts != nil {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value %v", typ, value)
+		}
+		if parts[2] != "" {
+			size, err := strconv.Atoi(parts[2])
+			if err != nil || size != len(items) {
+				return nil, fmt.Errorf("invalid %s length %d", typ, len(items))
+			}
+		}
+		var buffer bytes.Buffer
+		for _, item := range items {
+			encoded, err := typedData.encodeValue(parts[1], item)
+			if err != nil {
+				return nil, err
+			}
+			buffer.Write(encoded)
+		}
+		return crypto.Keccak256(buffer.Bytes()), nil
+	}
+	// Structs are encoded as their struct hash
+	if _, ok := typedData.Types[typ]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value %v", typ, value)
+		}
+		return typedData.HashStruct(typ, data)
+	}
+	// Dynamic types are encoded as the hash of their contents
+	switch typ {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid string value %v", value)
+		}
+		return crypto.Keccak256([]byte(str)), nil
+	case "bytes":
+		blob, err := parseTypedBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(blob), nil
+	}
+	return encodeAtomicValue(typ, value)
+}
+
+// encodeAtomicValue encodes a value of a fixed size type into a 32 byte word.
+func encodeAtomicValue(typ string, value interface{}) ([]byte, error) {
+	switch {
+	case typ == "address":
+		str, ok := value.(string)
+		if !ok || !common.IsHexAddress(str) {
+			return nil, fmt.Errorf("invalid address value %v", value)
+		}
+		return common.LeftPadBytes(common.HexToAddress(str).Bytes(), 32), nil
+
+	case typ == "bool":
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid bool value %v", value)
+		}
+		if flag {
+			return math.PaddedBigBytes(common.Big1, 32), nil
+		}
+		return math.PaddedBigBytes(common.Big0, 32), nil
+
+	case typedDataBytesRegexp.MatchString(typ):
+		size, _ := strconv.Atoi(typedDataBytesRegexp.FindStringSubmatch(typ)[1])
+		blob, err := parseTypedBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(blob) > size {
+			return nil, fmt.Errorf("invalid %s value of %d bytes", typ, len(blob))
+		}
+		return common.RightPadBytes(blob, 32), nil
+
+	case typedDataIntRegexp.MatchString(typ):
+		parts := typedDataIntRegexp.FindStringSubmatch(typ)
+		bits := 256
+		if parts[2] != "" {
+			bits, _ = strconv.Atoi(parts[2])
+		}
+		number, err := parseTypedInteger(value)
+		if err != nil {
+			return nil, err
+		}
+		if parts[1] == "u" {
+			if number.Sign() < 0 || number.BitLen() > bits {
+				return nil, fmt.Errorf("%s value %v out of range", typ, number)
+			}
+		} else {
+			// Signed integers fit if either the value or its one's complement
+			// fits into the size without the sign bit
+			abs := new(big.Int).Set(number)
+			if abs.Sign() < 0 {
+				abs.Not(abs)
+			}
+			if abs.BitLen() > bits-1 {
+				return nil, fmt.Errorf("%s value %v out of range", typ, number)
+			}
+		}
+		return math.PaddedBigBytes(math.U256(number), 32), nil
+	}
+	return nil, fmt.Errorf("unknown type %q", typ)
+}
+
+// parseTypedBytes parses a hex encoded binary value.
+func parseTypedBytes(value interface{}) ([]byte, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid bytes value %v", value)
+	}
+	return hexutil.Decode(str)
+}
+
+// parseTypedInteger parses an integer value provided either as a JSON number or
+// as a decimal or hex string.
+func parseTypedInteger(value interface{}) (*big.Int, error) {
+	var str string
+	switch v := value.(type) {
+	case json.Number:
+		str = string(v)
+	case string:
+		str = v
+	case float64:
+		// Only reachable if the data wasn't parsed via UnmarshalJSON
+		if v != float64(int64(v)) {
+			return nil, fmt.Errorf("invalid integer value %v", v)
+		}
+		return big.NewInt(int64(v)), nil
+	default:
+		return nil, fmt.Errorf("invalid integer value %v", value)
+	}
+	number, ok := math.ParseBig256(strings.TrimSpace(str))
+	if !ok {
+		return nil, errors.New("invalid integer value " + str)
+	}
+	return number, nil
+}
+
+// isAtomicType reports whether the type is a fixed size elementary type.
+func isAtomicType(typ string) bool {
+	if typ == "address" || typ == "bool" {
+		return true
+	}
+	if parts := typedDataIntRegexp.FindStringSubmatch(typ); parts != nil {
+		if parts[2] == "" {
+			return true
+		}
+		bits, err := strconv.Atoi(parts[2])
+		return err == nil && bits > 0 && bits <= 256 && bits%8 == 0
+	}
+	if parts := typedDataBytesRegexp.FindStringSubmatch(typ); parts != nil {
+		size, err := strconv.Atoi(parts[1])
+		return err == nil && size > 0 && size <= 32
+	}
+	return false
+}
+
+// isDynamicType reports whether the type is a variable size elementary type.
+func isDynamicType(typ string) bool {
+	return typ == "string" || typ == "bytes"
+}
@@ -0,0 +1,187 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// mailTypedData is the example structured data of the EIP-712 specification.
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func parseTypedData(t *testing.T, blob string) *TypedData {
+	var data TypedData
+	if err := json.Unmarshal([]byte(blob), &data); err != nil {
+		t.Fatalf("failed to parse typed data: %v", err)
+	}
+	return &data
+}
+
+// Tests the hashing and signing of the example of the EIP-712 specification.
+func TestTypedDataSpecExample(t *testing.T) {
+	data := parseTypedData(t, mailTypedData)
+
+	if enc, want := data.EncodeType("Mail"), "Mail(Person from,Person to,string contents)Person(string name,address wallet)"; enc != want {
+		t.Errorf("type encoding mismatch: have %s, want %s", enc, want)
+	}
+	if hash, want := data.TypeHash("Mail"), common.FromHex("0xa0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2"); !bytes.Equal(hash, want) {
+		t.Errorf("type hash mismatch: have %x, want %x", hash, want)
+	}
+	domain, message, err := data.Hashes()
+	if err != nil {
+		t.Fatalf("failed to hash typed data: %v", err)
+	}
+	if want := common.FromHex("0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"); !bytes.Equal(domain, want) {
+		t.Errorf("domain separator mismatch: have %x, want %x", domain, want)
+	}
+	if want := common.FromHex("0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"); !bytes.Equal(message, want) {
+		t.Errorf("message hash mismatch: have %x, want %x", message, want)
+	}
+	digest := TypedDataHash(domain, message)
+	if want := common.FromHex("0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"); !bytes.Equal(digest, want) {
+		t.Errorf("digest mismatch: have %x, want %x", digest, want)
+	}
+	// Sign with the key of the specification and compare the signature
+	key, _ := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	sig, err := crypto.Sign(digest, key)
+	if err != nil {
+		t.Fatalf("failed to sign digest: %v", err)
+	}
+	want := common.FromHex("0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b9156201")
+	if !bytes.Equal(sig, want) {
+		t.Errorf("signature mismatch: have %x, want %x", sig, want)
+	}
+}
+
+// Tests the encoding of the various elementary and array types.
+func TestTypedDataEncoding(t *testing.T) {
+	types := TypedDataTypes{
+		"EIP712Domain": {},
+		"Item":         {{Name: "id", Type: "uint8"}},
+	}
+	tests := []struct {
+		typ   string
+		value string
+		want  string
+		fail  bool
+	}{
+		{typ: "bool", value: `true`, want: "0x0000000000000000000000000000000000000000000000000000000000000001"},
+		{typ: "uint256", value: `"0x10"`, want: "0x0000000000000000000000000000000000000000000000000000000000000010"},
+		{typ: "uint256", value: `123456789012345678901234567890`, want: "0x00000000000000000000000000000000000000018ee90ff6c373e0ee4e3f0ad2"},
+		{typ: "int8", value: `-1`, want: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+		{typ: "int8", value: `-128`, want: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff80"},
+		{typ: "int8", value: `128`, fail: true},
+		{typ: "uint8", value: `256`, fail: true},
+		{typ: "uint8", value: `-1`, fail: true},
+		{typ: "bytes4", value: `"0xdeadbeef"`, want: "0xdeadbeef00000000000000000000000000000000000000000000000000000000"},
+		{typ: "bytes4", value: `"0xdeadbeef00"`, fail: true},
+		{typ: "address", value: `"0x1"`, fail: true},
+		{typ: "string", value: `"hello"`, want: hexutil.Encode(crypto.Keccak256([]byte("hello")))},
+		{typ: "bytes", value: `"0x0102"`, want: hexutil.Encode(crypto.Keccak256([]byte{1, 2}))},
+		{typ: "uint8[2]", value: `[1, 2]`, want: hexutil.Encode(crypto.Keccak256(
+			common.LeftPadBytes([]byte{1}, 32), common.LeftPadBytes([]byte{2}, 32)))},
+		{typ: "uint8[2]", value: `[1]`, fail: true},
+		{typ: "bool", value: `1`, fail: true},
+		{typ: "Item[]", value: `[{"id": 1}]`},
+		{typ: "Item", value: `{"id": 1, "extra": 2}`, fail: true},
+		{typ: "Item", value: `{}`, fail: true},
+	}
+	for i, tt := range tests {
+		data := parseTypedData(t, `{"types": {}, "primaryType": "Test", "domain": {}, "message": {"value": `+tt.value+`}}`)
+		data.Types = TypedDataTypes{"Test": {{Name: "value", Type: tt.typ}}}
+		for name, fields := range types {
+			data.Types[name] = fields
+		}
+		if err := data.Validate(); err != nil {
+			t.Fatalf("test %d: invalid types: %v", i, err)
+		}
+		encoded, err := data.EncodeData("Test", data.Message)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: %s value %s: expected failure", i, tt.typ, tt.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: %s value %s: failed to encode: %v", i, tt.typ, tt.value, err)
+			continue
+		}
+		if tt.want != "" && hexutil.Encode(encoded[32:]) != tt.want {
+			t.Errorf("test %d: %s value %s: encoding mismatch: have %x, want %s", i, tt.typ, tt.value, encoded[32:], tt.want)
+		}
+	}
+}
+
+// Tests that malformed type definitions are rejected.
+func TestTypedDataValidation(t *testing.T) {
+	tests := []struct {
+		types   string
+		primary string
+		err     string
+	}{
+		{`{"Mail": []}`, "Mail", "missing EIP712Domain"},
+		{`{"EIP712Domain": []}`, "Mail", "primary type"},
+		{`{"EIP712Domain": [], "Mail": [{"name": "to", "type": "Person"}]}`, "Mail", "undefined type"},
+		{`{"EIP712Domain": [], "Mail": [{"name": "to", "type": "uint7"}]}`, "Mail", "undefined type"},
+		{`{"EIP712Domain": [], "Mail": [{"name": "a", "type": "bool"}, {"name": "a", "type": "bool"}]}`, "Mail", "duplicate field"},
+		{`{"EIP712Domain": [], "uint256": []}`, "uint256", "invalid struct type name"},
+	}
+	for i, tt := range tests {
+		data := parseTypedData(t, `{"types": `+tt.types+`, "primaryType": "`+tt.primary+`", "domain": {}, "message": {}}`)
+		err := data.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %q", i, err, tt.err)
+		}
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/karalabe/hid"
 )
@@ -66,6 +67,11 @@ type driver interface {
 	// SignTx sends the transaction to the USB device and waits for the user to confirm
 	// or deny the transaction.
 	SignTx(path accounts.DerivationPath, tx *types.Transaction, chainID *big.Int) (common.Address, *types.Transaction, error)
+
+	// SignTypedMessage sends the hashes of an EIP-712 structured data to the USB
+	// device and waits for the user to confirm or deny the signature. The returned
+	// signature is in the [R || S || V] format where V is 0 or 1.
+	SignTypedMessage(path accounts.DerivationPath, domainSeparator, messageHash []byte) ([]byte, error)
 }
 
 // wallet represents the common functionality shared by all USB hardware
@@ -560,3 +566,58 @@ func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase str
 func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
 	return w.SignTx(account, tx, chainID)
 }
+
+// SignTypedData implements accounts.Wallet. It sends the hashes of the structured
+// data over to the hardware wallet to request a confirmation from the user. It
+// returns either the signature or a failure if the user denied the request or
+// the device doesn't support signing structured data.
+func (w *wallet) SignTypedData(account accounts.Account, domainSeparator, messageHash []byte) ([]byte, error) {
+	w.stateLock.RLock() // Comms have own mutex, this is for the state fields
+	defer w.stateLock.RUnlock()
+
+	// If the wallet is closed, abort
+	if w.device == nil {
+		return nil, accounts.ErrWalletClosed
+	}
+	// Make sure the requested account is contained within
+	path, ok := w.paths[account.Address]
+	if !ok {
+		return nil, accounts.ErrUnknownAccount
+	}
+	// All infos gathered and metadata checks out, request signing
+	<-w.commsLock
+	defer func() { w.commsLock <- struct{}{} }()
+
+	// Ensure the device isn't screwed with while user confirmation is pending
+	// TODO(karalabe): remove if hotplug lands on Windows
+	w.hub.commsLock.Lock()
+	w.hub.commsPend++
+	w.hub.commsLock.Unlock()
+
+	defer func() {
+		w.hub.commsLock.Lock()
+		w.hub.commsPend--
+		w.hub.commsLock.Unlock()
+	}()
+	// Sign the hashes and verify the signer to avoid hardware fault surprises
+	signature, err := w.driver.SignTypedMessage(path, domainSeparator, messageHash)
+	if err != nil {
+		return nil, err
+	}
+	pubkey, err := crypto.SigToPub(accounts.TypedDataHash(domainSeparator, messageHash), signature)
+	if err != nil {
+		return nil, err
+	}
+	if sender := crypto.PubkeyToAddress(*pubkey); sender != account.Address {
+		return nil, fmt.Errorf("signer mismatch: expected %s, got %s", account.Address.Hex(), sender.Hex())
+	}
+	return signature, nil
+}
+
+// SignTypedDataWithPassphrase implements accounts.Wallet, attempting to sign the
+// given structured data with the given account using passphrase as extra
+// authentication. Since USB wallets don't rely on passphrases, these are silently
+// ignored.
+func (w *wallet) SignTypedDataWithPassphrase(account accounts.Account, passphrase string, domainSeparator, messageHash []byte) ([]byte, error) {
+	return w.SignTypedData(account, domainSeparator, messageHash)
+}
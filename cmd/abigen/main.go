@@ -39,7 +39,8 @@ var (
 
 	pkgFlag  = flag.String("pkg", "", "Package name to generate the binding into")
 	outFlag  = flag.String("out", "", "Output file for the generated binding (default = stdout)")
-	langFlag = flag.String("lang", "go", "Destination language for the bindings (go, java, objc)")
+	langFlag = flag.String("lang", "go", "Destination language for the bindings (go, java, objc, ts)")
+	tmplFlag = flag.String("tmpl", "", "Path to a custom template to generate the bindings with, using the type mappings of --lang")
 )
 
 func main() {
@@ -65,6 +66,8 @@ func main() {
 		lang = bind.LangJava
 	case "objc":
 		lang = bind.LangObjC
+	case "ts":
+		lang = bind.LangTypeScript
 	default:
 		fmt.Printf("Unsupported destination language \"%s\" (--lang)\n", *langFlag)
 		os.Exit(-1)
@@ -122,8 +125,22 @@ func main() {
 		}
 		types = append(types, kind)
 	}
+	// Assemble the template set, replacing the built-in source if requested
+	tmpl, err := bind.LangTemplate(lang)
+	if err != nil {
+		fmt.Printf("Failed to load binding template: %v\n", err)
+		os.Exit(-1)
+	}
+	if *tmplFlag != "" {
+		source, err := ioutil.ReadFile(*tmplFlag)
+		if err != nil {
+			fmt.Printf("Failed to read binding template: %v\n", err)
+			os.Exit(-1)
+		}
+		tmpl.Source = string(source)
+	}
 	// Generate the contract binding
-	code, err := bind.Bind(types, abis, bins, *pkgFlag, lang)
+	code, err := bind.BindTemplate(types, abis, bins, *pkgFlag, tmpl)
 	if err != nil {
 		fmt.Printf("Failed to generate ABI binding: %v\n", err)
 		os.Exit(-1)
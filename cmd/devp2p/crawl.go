@@ -0,0 +1,282 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	crawlCommand = cli.Command{
+		Name:      "crawl",
+		Usage:     "Walk the discovery DHT and record all nodes found",
+		ArgsUsage: "<nodes.json>",
+		Action:    crawl,
+		Flags:     []cli.Flag{crawlBootnodesFlag, crawlTimeoutFlag, crawlRLPxFlag},
+	}
+)
+
+var (
+	crawlBootnodesFlag = cli.StringFlag{
+		Name:  "bootnodes",
+		Usage: "comma separated enode URLs used to join the network (default mainnet bootnodes)",
+	}
+	crawlTimeoutFlag = cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "time limit for the crawl",
+		Value: 30 * time.Minute,
+	}
+	crawlRLPxFlag = cli.BoolFlag{
+		Name:  "rlpx",
+		Usage: "connect to live nodes to collect their client name and capabilities",
+	}
+)
+
+const (
+	crawlParallelism   = 16               // number of concurrent pings/handshakes
+	crawlStatsInterval = 10 * time.Second // how often progress is logged
+	crawlClientName    = "devp2p-crawler"
+)
+
+// crawl runs the crawler and updates the given node list file. Nodes already in
+// the file are revalidated and new nodes are added to it.
+func crawl(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("need node list file as argument")
+	}
+	file := ctx.Args().Get(0)
+	input := make(nodeSet)
+	if _, err := os.Stat(file); err == nil {
+		if input, err = loadNodesJSON(file); err != nil {
+			return err
+		}
+	}
+	bootnodes, err := parseBootnodes(ctx.String(crawlBootnodesFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return err
+	}
+	tab, err := discover.ListenUDP(key, ":0", nil, "", nil)
+	if err != nil {
+		return err
+	}
+	defer tab.Close()
+	if err := tab.SetFallbackNodes(bootnodes); err != nil {
+		return err
+	}
+
+	c := newCrawler(input, tab)
+	c.run(ctx.Duration(crawlTimeoutFlag.Name))
+	if ctx.Bool(crawlRLPxFlag.Name) {
+		c.handshakeAll(key)
+	}
+	c.collectRecords()
+	return writeNodesJSON(file, c.output)
+}
+
+func parseBootnodes(list string) ([]*discover.Node, error) {
+	urls := params.MainnetBootnodes
+	if list != "" {
+		urls = strings.Split(list, ",")
+	}
+	nodes := make([]*discover.Node, len(urls))
+	for i, url := range urls {
+		n, err := discover.ParseNode(url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bootnode %q: %v", url, err)
+		}
+		nodes[i] = n
+	}
+	return nodes, nil
+}
+
+// crawler walks the DHT using random lookups and keeps track of the
+// liveness of every node it encounters.
+type crawler struct {
+	input nodeSet
+	tab   *discover.Table
+
+	mu     sync.Mutex
+	output nodeSet
+	added  int
+}
+
+func newCrawler(input nodeSet, tab *discover.Table) *crawler {
+	c := &crawler{input: input, tab: tab, output: make(nodeSet, len(input))}
+	for id, n := range input {
+		c.output[id] = n
+	}
+	return c
+}
+
+// run revalidates all input nodes and then performs random lookups until the
+// timeout expires.
+func (c *crawler) run(timeout time.Duration) {
+	var (
+		deadline = time.Now().Add(timeout)
+		stats    = time.NewTicker(crawlStatsInterval)
+		done     = make(chan struct{})
+	)
+	defer stats.Stop()
+	go func() {
+		c.revalidate()
+		for time.Now().Before(deadline) {
+			var target discover.NodeID
+			rand.Read(target[:])
+			for _, n := range c.tab.Lookup(target) {
+				// Lookup results have answered a ping during the
+				// lookup, so they are known to be alive.
+				c.update(n, true)
+			}
+		}
+		close(done)
+	}()
+	for {
+		select {
+		case <-stats.C:
+			c.mu.Lock()
+			log.Info("Crawling in progress", "nodes", len(c.output), "added", c.added)
+			c.mu.Unlock()
+		case <-done:
+			log.Info("Crawl finished", "nodes", len(c.output), "added", c.added)
+			return
+		}
+	}
+}
+
+// revalidate pings all input nodes to update their liveness.
+func (c *crawler) revalidate() {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, crawlParallelism)
+	)
+	for _, entry := range c.input {
+		n, err := discover.ParseNode(entry.Enode)
+		if err != nil {
+			log.Warn("Skipping invalid node", "enode", entry.Enode, "err", err)
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			c.update(n, c.tab.Ping(n) == nil)
+		}()
+	}
+	wg.Wait()
+}
+
+// update records the result of a liveness check.
+func (c *crawler) update(n *discover.Node, alive bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := c.output[n.ID]
+	if !ok {
+		c.added++
+	}
+	entry.Enode = n.String()
+	entry.LastCheck = now
+	if alive {
+		if entry.FirstResponse.IsZero() {
+			entry.FirstResponse = now
+		}
+		entry.LastResponse = now
+	}
+	c.output[n.ID] = entry
+}
+
+// handshakeAll connects to all nodes that responded during the crawl and stores
+// the client name and capabilities announced by them.
+func (c *crawler) handshakeAll(key *ecdsa.PrivateKey) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, crawlParallelism)
+	)
+	// Gather the live nodes up front, the handshakes update the output concurrently
+	c.mu.Lock()
+	var alive []*discover.Node
+	for _, entry := range c.output {
+		if entry.LastResponse.Before(entry.LastCheck) {
+			continue // node is not alive
+		}
+		if n, err := discover.ParseNode(entry.Enode); err == nil {
+			alive = append(alive, n)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, n := range alive {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(n *discover.Node) {
+			defer func() { <-sem; wg.Done() }()
+			id := n.ID
+			name, caps, err := p2p.Handshake(key, n, crawlClientName)
+			if err != nil {
+				log.Debug("RLPx handshake failed", "id", id, "err", err)
+				return
+			}
+			capnames := make([]string, len(caps))
+			for i, cap := range caps {
+				capnames[i] = cap.String()
+			}
+			c.mu.Lock()
+			entry := c.output[id]
+			entry.Name, entry.Caps = name, capnames
+			c.output[id] = entry
+			c.mu.Unlock()
+		}(n)
+	}
+	wg.Wait()
+}
+
+// collectRecords adds the node records received during the crawl to the output.
+func (c *crawler) collectRecords() {
+	for id, entry := range c.output {
+		r := c.tab.NodeRecord(id)
+		if r == nil {
+			continue
+		}
+		blob, err := rlp.EncodeToBytes(r)
+		if err != nil {
+			continue
+		}
+		entry.Record = hexutil.Bytes(blob)
+		c.output[id] = entry
+	}
+}
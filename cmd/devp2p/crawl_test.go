@@ -0,0 +1,100 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// newTestTable starts a discovery table on the loopback interface, joining the
+// network through the given bootnodes.
+func newTestTable(t *testing.T, bootnodes []*discover.Node) *discover.Table {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tab, err := discover.ListenUDP(key, "127.0.0.1:0", nil, "", nil)
+	if err != nil {
+		t.Fatalf("failed to start discovery: %v", err)
+	}
+	if err := tab.SetFallbackNodes(bootnodes); err != nil {
+		tab.Close()
+		t.Fatalf("failed to set bootnodes: %v", err)
+	}
+	return tab
+}
+
+// Tests that the crawler finds all nodes of a local discovery network and
+// records the liveness of both the discovered and the revalidated input nodes.
+func TestCrawler(t *testing.T) {
+	// Start a small network of nodes bootstrapped off the first one
+	boot := newTestTable(t, nil)
+	defer boot.Close()
+
+	alive := map[discover.NodeID]bool{boot.Self().ID: true}
+	for i := 0; i < 3; i++ {
+		tab := newTestTable(t, []*discover.Node{boot.Self()})
+		defer tab.Close()
+		alive[tab.Self().ID] = true
+	}
+	// Seed the crawler with a node that doesn't exist
+	key, _ := crypto.GenerateKey()
+	dead := discover.NewNode(discover.PubkeyID(&key.PublicKey), net.IP{127, 0, 0, 1}, 1, 1)
+	input := nodeSet{dead.ID: nodeJSON{Enode: dead.String()}}
+
+	tab := newTestTable(t, []*discover.Node{boot.Self()})
+	defer tab.Close()
+
+	// Crawl until all nodes were found, lookups may be needed for the network to settle
+	c := newCrawler(input, tab)
+	for deadline := time.Now().Add(10 * time.Second); ; {
+		c.run(time.Second)
+
+		found := 0
+		for id := range alive {
+			if entry, ok := c.output[id]; ok && !entry.LastResponse.IsZero() {
+				found++
+			}
+		}
+		if found == len(alive) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("crawler found %d of %d live nodes", found, len(alive))
+		}
+	}
+	if c.added != len(alive) {
+		t.Errorf("added node count mismatch: have %d, want %d", c.added, len(alive))
+	}
+	for id := range alive {
+		if entry := c.output[id]; entry.FirstResponse.IsZero() || entry.LastResponse.Before(entry.LastCheck) {
+			t.Errorf("node %x: not recorded as alive: %+v", id[:8], entry)
+		}
+	}
+	entry, ok := c.output[dead.ID]
+	if !ok {
+		t.Fatalf("input node missing from output")
+	}
+	if entry.LastCheck.IsZero() || !entry.FirstResponse.IsZero() || !entry.LastResponse.IsZero() {
+		t.Errorf("dead input node recorded as alive: %+v", entry)
+	}
+}
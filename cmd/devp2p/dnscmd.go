@@ -0,0 +1,159 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
+	"github.com/ethereum/go-ethereum/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	dnsCommand = cli.Command{
+		Name:  "dns",
+		Usage: "DNS discovery commands",
+		Subcommands: []cli.Command{
+			dnsSignCommand,
+			dnsSyncCommand,
+		},
+	}
+	dnsSignCommand = cli.Command{
+		Name:      "sign",
+		Usage:     "Sign a DNS discovery tree",
+		ArgsUsage: "<nodes.json> <keyfile>",
+		Action:    dnsSign,
+		Flags:     []cli.Flag{dnsDomainFlag, dnsSeqFlag, dnsLinkFlag, dnsOutputFlag},
+	}
+	dnsSyncCommand = cli.Command{
+		Name:      "sync",
+		Usage:     "Download a DNS discovery tree",
+		ArgsUsage: "<url> [ <nodes.json> ]",
+		Action:    dnsSync,
+	}
+)
+
+var (
+	dnsDomainFlag = cli.StringFlag{
+		Name:  "domain",
+		Usage: "domain name the tree is published under",
+	}
+	dnsSeqFlag = cli.UintFlag{
+		Name:  "seq",
+		Usage: "sequence number of the tree",
+	}
+	dnsLinkFlag = cli.StringFlag{
+		Name:  "links",
+		Usage: "comma separated enrtree:// URLs of linked trees",
+	}
+	dnsOutputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "file to write the TXT records to (default stdout)",
+	}
+)
+
+// dnsSign creates and signs a tree from a node list and prints the TXT records
+// that have to be published.
+func dnsSign(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("need node list and key file as arguments")
+	}
+	domain := ctx.String(dnsDomainFlag.Name)
+	if domain == "" {
+		return fmt.Errorf("missing -%s", dnsDomainFlag.Name)
+	}
+	nodes, err := loadNodesJSON(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	key, err := crypto.LoadECDSA(ctx.Args().Get(1))
+	if err != nil {
+		return fmt.Errorf("can't load key: %v", err)
+	}
+	var links []string
+	if l := ctx.String(dnsLinkFlag.Name); l != "" {
+		links = strings.Split(l, ",")
+	}
+
+	records, err := nodes.records()
+	if err != nil {
+		return err
+	}
+	tree, err := dnsdisc.MakeTree(ctx.Uint(dnsSeqFlag.Name), records, links)
+	if err != nil {
+		return err
+	}
+	url, err := tree.Sign(key, domain)
+	if err != nil {
+		return fmt.Errorf("can't sign tree: %v", err)
+	}
+	blob, err := json.MarshalIndent(tree.ToTXT(domain), "", "  ")
+	if err != nil {
+		return err
+	}
+	blob = append(blob, '\n')
+	if out := ctx.String(dnsOutputFlag.Name); out != "" {
+		if err := ioutil.WriteFile(out, blob, 0644); err != nil {
+			return err
+		}
+	} else {
+		os.Stdout.Write(blob)
+	}
+	fmt.Fprintf(os.Stderr, "Signed tree with %d nodes: %s\n", len(records), url)
+	return nil
+}
+
+// dnsSync downloads a tree and optionally writes the contained nodes to a file.
+func dnsSync(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("need tree URL as argument")
+	}
+	client := dnsdisc.NewClient(dnsdisc.Config{})
+	tree, err := client.SyncTree(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	nodes := make(nodeSet)
+	for _, r := range tree.Nodes() {
+		n, err := discover.NodeFromRecord(r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping invalid record: %v\n", err)
+			continue
+		}
+		blob, err := rlp.EncodeToBytes(r)
+		if err != nil {
+			return err
+		}
+		nodes[n.ID] = nodeJSON{Enode: n.String(), Record: hexutil.Bytes(blob)}
+	}
+	fmt.Printf("seq=%d nodes=%d links=%d\n", tree.Seq(), len(nodes), len(tree.Links()))
+	for _, link := range tree.Links() {
+		fmt.Println("link:", link)
+	}
+	if ctx.NArg() > 1 {
+		return writeNodesJSON(ctx.Args().Get(1), nodes)
+	}
+	return nil
+}
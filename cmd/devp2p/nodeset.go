@@ -0,0 +1,96 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// nodeSet is the on-disk format of node lists, keyed by node ID.
+type nodeSet map[discover.NodeID]nodeJSON
+
+type nodeJSON struct {
+	Enode  string        `json:"enode"`
+	Record hexutil.Bytes `json:"record,omitempty"` // RLP encoded node record
+
+	// Liveness information collected by the crawler.
+	FirstResponse time.Time `json:"firstResponse"`
+	LastResponse  time.Time `json:"lastResponse"`
+	LastCheck     time.Time `json:"lastCheck"`
+
+	// Client information collected through the RLPx handshake.
+	Name string   `json:"name,omitempty"`
+	Caps []string `json:"caps,omitempty"`
+}
+
+func loadNodesJSON(file string) (nodeSet, error) {
+	blob, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var nodes nodeSet
+	if err := json.Unmarshal(blob, &nodes); err != nil {
+		return nil, fmt.Errorf("invalid node list %s: %v", file, err)
+	}
+	return nodes, nil
+}
+
+func writeNodesJSON(file string, nodes nodeSet) error {
+	blob, err := json.MarshalIndent(nodes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(blob, '\n'), 0644)
+}
+
+// records returns the decoded node records of the set, in node ID order. Nodes
+// without a record are skipped.
+func (ns nodeSet) records() ([]*enr.Record, error) {
+	ids := make(nodeIDs, 0, len(ns))
+	for id, n := range ns {
+		if len(n.Record) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Sort(ids)
+
+	records := make([]*enr.Record, len(ids))
+	for i, id := range ids {
+		r := new(enr.Record)
+		if err := rlp.DecodeBytes(ns[id].Record, r); err != nil {
+			return nil, fmt.Errorf("invalid record of node %x: %v", id[:8], err)
+		}
+		records[i] = r
+	}
+	return records, nil
+}
+
+// nodeIDs implements sort.Interface for node IDs.
+type nodeIDs []discover.NodeID
+
+func (s nodeIDs) Len() int           { return len(s) }
+func (s nodeIDs) Less(i, j int) bool { return string(s[i][:]) < string(s[j][:]) }
+func (s nodeIDs) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
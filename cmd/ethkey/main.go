@@ -59,6 +59,8 @@ func init() {
 		commandInspect,
 		commandSignMessage,
 		commandVerifyMessage,
+		commandSplit,
+		commandCombine,
 	}
 }
 
@@ -0,0 +1,235 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/console"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/shamir"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// sharedKeyLength is the length of the secret split into shares: the private key
+// followed by the address of the account, so the reconstructed key can be checked.
+const sharedKeyLength = 32 + common.AddressLength
+
+type outputSplit struct {
+	Address   string
+	Threshold int
+	Shares    []string
+}
+
+type outputCombine struct {
+	Address string
+}
+
+var commandSplit = cli.Command{
+	Name:      "split",
+	Usage:     "split a keyfile into secret shares",
+	ArgsUsage: "<keyfile>",
+	Description: `
+Split the private key of a keyfile into a number of shares using Shamir's secret
+sharing, any --threshold of which can reconstruct the key, while fewer reveal
+nothing about it. Each share carries a checksum to detect typos, and can be
+printed as a mnemonic with the --mnemonic flag to make it easier to write down.
+The address of the key is split along with it, to verify the key on recovery.
+
+Store the shares in separate places, as anyone holding enough of them gains
+full control over the key.`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+		cli.IntFlag{
+			Name:  "shares",
+			Usage: "the number of shares to split the key into",
+			Value: 3,
+		},
+		cli.IntFlag{
+			Name:  "threshold",
+			Usage: "the number of shares needed to reconstruct the key",
+			Value: 2,
+		},
+		cli.BoolFlag{
+			Name:  "mnemonic",
+			Usage: "encode the shares as mnemonics instead of hex",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		keyfilepath := ctx.Args().First()
+
+		// Read key from file.
+		keyjson, err := ioutil.ReadFile(keyfilepath)
+		if err != nil {
+			utils.Fatalf("Failed to read the keyfile at '%s': %v", keyfilepath, err)
+		}
+
+		// Decrypt key with passphrase.
+		passphrase := getPassPhrase(ctx, false)
+		key, err := keystore.DecryptKey(keyjson, passphrase)
+		if err != nil {
+			utils.Fatalf("Error decrypting key: %v", err)
+		}
+
+		// Split the private key and encode the shares.
+		threshold := ctx.Int("threshold")
+		secret := append(crypto.FromECDSA(key.PrivateKey), key.Address.Bytes()...)
+		shares, err := shamir.Split(secret, ctx.Int("shares"), threshold)
+		if err != nil {
+			utils.Fatalf("Failed to split key: %v", err)
+		}
+		out := outputSplit{
+			Address:   key.Address.Hex(),
+			Threshold: threshold,
+		}
+		for _, share := range shares {
+			encoded := hex.EncodeToString(share.Bytes())
+			if ctx.Bool("mnemonic") {
+				if encoded, err = share.Mnemonic(); err != nil {
+					utils.Fatalf("Failed to encode share: %v", err)
+				}
+			}
+			out.Shares = append(out.Shares, encoded)
+		}
+
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			fmt.Println("Address:       ", out.Address)
+			fmt.Println("Threshold:     ", out.Threshold)
+			for i, share := range out.Shares {
+				fmt.Printf("Share %d/%d:     %s\n", i+1, len(out.Shares), share)
+			}
+		}
+		return nil
+	},
+}
+
+var commandCombine = cli.Command{
+	Name:      "combine",
+	Usage:     "reconstruct a key from secret shares into a keystore",
+	ArgsUsage: "[ <sharesfile> ]",
+	Description: `
+Reconstruct a private key from the shares created by the split command and import
+it into the keystore directory given by --keystore, encrypted with a new
+passphrase. The key is checked against the address split along with it, so
+mixing up shares of different keys is detected.
+
+The shares, in hex or mnemonic form, are read one per line from the given file,
+or prompted for interactively if no file is given.`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+		cli.StringFlag{
+			Name:  "keystore",
+			Usage: "the keystore directory to import the reconstructed key into",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		keydir := ctx.String("keystore")
+		if keydir == "" {
+			utils.Fatalf("Keystore directory not specified (--keystore)")
+		}
+
+		// Gather the shares from the file or the user.
+		var shares []*shamir.Share
+		if sharesfile := ctx.Args().First(); sharesfile != "" {
+			content, err := ioutil.ReadFile(sharesfile)
+			if err != nil {
+				utils.Fatalf("Failed to read the shares file '%s': %v", sharesfile, err)
+			}
+			for i, line := range strings.Split(string(content), "\n") {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				share, err := parseShare(line)
+				if err != nil {
+					utils.Fatalf("Invalid share on line %d: %v", i+1, err)
+				}
+				shares = append(shares, share)
+			}
+		} else {
+			for len(shares) == 0 || len(shares) < int(shares[0].Threshold) {
+				line, err := console.Stdin.PromptInput(fmt.Sprintf("Share %d: ", len(shares)+1))
+				if err != nil {
+					utils.Fatalf("Failed to read share: %v", err)
+				}
+				share, err := parseShare(line)
+				if err != nil {
+					fmt.Printf("Invalid share: %v\n", err)
+					continue
+				}
+				shares = append(shares, share)
+			}
+		}
+
+		// Reconstruct the private key and import it into the keystore.
+		secret, err := shamir.Combine(shares)
+		if err != nil {
+			utils.Fatalf("Failed to combine shares: %v", err)
+		}
+		if len(secret) != sharedKeyLength {
+			utils.Fatalf("Invalid key length in shares: have %d bytes, want %d", len(secret), sharedKeyLength)
+		}
+		privateKey, err := crypto.ToECDSA(secret[:32])
+		if err != nil {
+			utils.Fatalf("Could not construct ECDSA private key from shares: %v", err)
+		}
+		address := common.BytesToAddress(secret[32:])
+		if have := crypto.PubkeyToAddress(privateKey.PublicKey); have != address {
+			utils.Fatalf("Reconstructed key does not match the shares: have address %s, want %s", have.Hex(), address.Hex())
+		}
+		passphrase := getPassPhrase(ctx, true)
+
+		ks := keystore.NewKeyStore(keydir, keystore.StandardScryptN, keystore.StandardScryptP)
+		account, err := ks.ImportECDSA(privateKey, passphrase)
+		if err != nil {
+			utils.Fatalf("Failed to import key: %v", err)
+		}
+
+		// Output some information.
+		out := outputCombine{
+			Address: account.Address.Hex(),
+		}
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			fmt.Println("Address:       ", out.Address)
+		}
+		return nil
+	},
+}
+
+// parseShare decodes a secret share from either its hex or its mnemonic form.
+func parseShare(text string) (*shamir.Share, error) {
+	text = strings.TrimSpace(text)
+	if len(strings.Fields(text)) > 1 {
+		return shamir.ParseMnemonic(text)
+	}
+	blob, err := hex.DecodeString(strings.TrimPrefix(text, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return shamir.ParseShare(blob)
+}
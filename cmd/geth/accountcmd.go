@@ -21,11 +21,14 @@ import (
 	"io/ioutil"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/hdwallet"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/console"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -54,6 +57,7 @@ passwordfile as argument containing the wallet password in plaintext.`,
 					utils.KeyStoreDirFlag,
 					utils.PasswordFileFlag,
 					utils.LightKDFFlag,
+					utils.KDFFlag,
 				},
 				Description: `
 	geth wallet [options] /path/to/my/presale.wallet
@@ -84,7 +88,7 @@ either new or import). Without it you are not able to unlock your account.
 
 Note that exporting your key in unencrypted format is NOT supported.
 
-Keys are stored under <DATADIR>/keystore.
+Keys are stored under <DATADIR>/keystore, HD wallets under <DATADIR>/keystore/hd.
 It is safe to transfer the entire directory or the individual keys therein
 between ethereum nodes by simply copying.
 
@@ -110,6 +114,7 @@ Print a short summary of all accounts`,
 					utils.KeyStoreDirFlag,
 					utils.PasswordFileFlag,
 					utils.LightKDFFlag,
+					utils.KDFFlag,
 				},
 				Description: `
     geth account new
@@ -135,6 +140,7 @@ password to file or expose in any other way.
 					utils.DataDirFlag,
 					utils.KeyStoreDirFlag,
 					utils.LightKDFFlag,
+					utils.KDFFlag,
 				},
 				Description: `
     geth account update <address>
@@ -153,6 +159,14 @@ For non-interactive use the passphrase can be specified with the --password flag
 
 Since only one password can be given, only format update can be performed,
 changing your password is only possible interactively.
+
+The updated file is encrypted with the key derivation function selected by the
+--kdf and --lightkdf flags, so the command can also be used to re-encrypt an
+existing key with stronger parameters, or with a different function:
+
+    geth account update --kdf argon2id <address>
+
+Keys remain decryptable regardless of the function they were encrypted with.
 `,
 			},
 			{
@@ -164,6 +178,7 @@ changing your password is only possible interactively.
 					utils.KeyStoreDirFlag,
 					utils.PasswordFileFlag,
 					utils.LightKDFFlag,
+					utils.KDFFlag,
 				},
 				ArgsUsage: "<keyFile>",
 				Description: `
@@ -188,8 +203,100 @@ this import mechanism is not needed when you transfer an account between
 nodes.
 `,
 			},
+			{
+				Name:  "hd",
+				Usage: "Manage hierarchical deterministic wallets",
+				Description: `
+
+Manage software hierarchical deterministic (HD) wallets. An HD wallet derives
+all of its accounts from a single BIP-39 mnemonic, optionally protected by an
+additional BIP-39 passphrase. The mnemonic is stored encrypted with a password,
+under <DATADIR>/keystore/hd.
+
+Wallets are referenced either by their URL or by the address of any of their
+pinned accounts. When a password file is given, its first line is the wallet
+password and its second line the BIP-39 passphrase.`,
+				Subcommands: []cli.Command{
+					{
+						Name:   "new",
+						Usage:  "Create a new HD wallet from a fresh mnemonic",
+						Action: utils.MigrateFlags(hdWalletCreate),
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.KeyStoreDirFlag,
+							utils.PasswordFileFlag,
+							utils.LightKDFFlag,
+							bip39PassphraseFlag,
+						},
+						Description: `
+    geth account hd new
+
+Generates a new mnemonic, creates an HD wallet from it and prints the mnemonic
+and the address of the first account, derived at m/44'/60'/0'/0/0.
+
+Write down the mnemonic and keep it safe: it restores all the accounts of the
+wallet, together with the BIP-39 passphrase if one was set.`,
+					},
+					{
+						Name:      "import",
+						Usage:     "Import a BIP-39 mnemonic into a new HD wallet",
+						Action:    utils.MigrateFlags(hdWalletImport),
+						ArgsUsage: "[<mnemonicFile>]",
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.KeyStoreDirFlag,
+							utils.PasswordFileFlag,
+							utils.LightKDFFlag,
+							bip39PassphraseFlag,
+						},
+						Description: `
+    geth account hd import [<mnemonicFile>]
+
+Creates an HD wallet from an existing mnemonic, read from <mnemonicFile> or
+prompted for if no file is given, and prints the address of its first account.`,
+					},
+					{
+						Name:      "export",
+						Usage:     "Print the mnemonic of an HD wallet",
+						Action:    utils.MigrateFlags(hdWalletExport),
+						ArgsUsage: "<wallet>",
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.KeyStoreDirFlag,
+							utils.PasswordFileFlag,
+						},
+						Description: `
+    geth account hd export <wallet>
+
+Decrypts an HD wallet and prints its mnemonic, along with its BIP-39 passphrase
+if one was set.`,
+					},
+					{
+						Name:      "derive",
+						Usage:     "Derive and pin a new account of an HD wallet",
+						Action:    utils.MigrateFlags(hdWalletDerive),
+						ArgsUsage: "<wallet> <path>",
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.KeyStoreDirFlag,
+							utils.PasswordFileFlag,
+						},
+						Description: `
+    geth account hd derive <wallet> <path>
+
+Derives the account at the given derivation path and pins it into the wallet,
+so it's listed without the wallet being unlocked. Paths are either absolute
+(m/44'/60'/0'/0/1) or relative to m/44'/60'/0'/0 (1).`,
+					},
+				},
+			},
 		},
 	}
+
+	bip39PassphraseFlag = cli.BoolFlag{
+		Name:  "bip39passphrase",
+		Usage: "Protect the mnemonic with an additional BIP-39 passphrase",
+	}
 )
 
 func accountList(ctx *cli.Context) error {
@@ -299,15 +406,19 @@ func accountCreate(ctx *cli.Context) error {
 		}
 	}
 	utils.SetNodeConfig(ctx, &cfg.Node)
-	scryptN, scryptP, keydir, err := cfg.Node.AccountConfig()
+	_, _, keydir, err := cfg.Node.AccountConfig()
 
+	if err != nil {
+		utils.Fatalf("Failed to read configuration: %v", err)
+	}
+	kdf, err := cfg.Node.AccountKDF()
 	if err != nil {
 		utils.Fatalf("Failed to read configuration: %v", err)
 	}
 
 	password := getPassPhrase("Your new account is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
 
-	address, err := keystore.StoreKey(keydir, password, scryptN, scryptP)
+	address, err := keystore.StoreKeyWithKDF(keydir, password, kdf)
 
 	if err != nil {
 		utils.Fatalf("Failed to create account: %v", err)
@@ -323,7 +434,7 @@ func accountUpdate(ctx *cli.Context) error {
 		utils.Fatalf("No accounts specified to update")
 	}
 	stack, _ := makeConfigNode(ctx)
-	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	ks := keyStore(stack)
 
 	for _, addr := range ctx.Args() {
 		account, oldPassword := unlockAccount(ctx, ks, addr, 0, nil)
@@ -348,7 +459,7 @@ func importWallet(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 	passphrase := getPassPhrase("", false, 0, utils.MakePasswordList(ctx))
 
-	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	ks := keyStore(stack)
 	acct, err := ks.ImportPreSaleKey(keyJson, passphrase)
 	if err != nil {
 		utils.Fatalf("%v", err)
@@ -369,7 +480,7 @@ func accountImport(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 	passphrase := getPassPhrase("Your new account is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
 
-	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	ks := keyStore(stack)
 	acct, err := ks.ImportECDSA(key, passphrase)
 	if err != nil {
 		utils.Fatalf("Could not create the account: %v", err)
@@ -377,3 +488,139 @@ func accountImport(ctx *cli.Context) error {
 	fmt.Printf("Address: {%x}\n", acct.Address)
 	return nil
 }
+
+// keyStore retrieves the local keystore of the node, which is not available
+// if an external signer is used.
+func keyStore(stack *node.Node) *keystore.KeyStore {
+	backends := stack.AccountManager().Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		utils.Fatalf("Local keystore not available, accounts are managed by the external signer")
+	}
+	return backends[0].(*keystore.KeyStore)
+}
+
+// hdBackend retrieves the HD wallet backend of the node.
+func hdBackend(stack *node.Node) *hdwallet.Backend {
+	backends := stack.AccountManager().Backends(hdwallet.BackendType)
+	if len(backends) == 0 {
+		utils.Fatalf("HD wallets are not available")
+	}
+	return backends[0].(*hdwallet.Backend)
+}
+
+// hdWallet looks up an HD wallet either by its URL or by the address of one of
+// its pinned accounts.
+func hdWallet(stack *node.Node, wallet string) accounts.Wallet {
+	for _, w := range hdBackend(stack).Wallets() {
+		if w.URL().String() == wallet {
+			return w
+		}
+		if common.IsHexAddress(wallet) && w.Contains(accounts.Account{Address: common.HexToAddress(wallet)}) {
+			return w
+		}
+	}
+	utils.Fatalf("Unknown HD wallet: %s", wallet)
+	return nil
+}
+
+// hdPassphrases retrieves the wallet password and the optional BIP-39 passphrase
+// for a new HD wallet.
+func hdPassphrases(ctx *cli.Context) (string, string) {
+	passwords := utils.MakePasswordList(ctx)
+
+	password := getPassPhrase("Your new HD wallet is locked with a password. Please give a password. Do not forget this password.", true, 0, passwords)
+	if !ctx.Bool(bip39PassphraseFlag.Name) {
+		return password, ""
+	}
+	passphrase := getPassPhrase("Please give the BIP-39 passphrase of the mnemonic. It is needed along the mnemonic to restore the wallet.", true, 1, passwords)
+	return password, passphrase
+}
+
+// hdWalletCreate creates a new HD wallet from a freshly generated mnemonic.
+func hdWalletCreate(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	password, passphrase := hdPassphrases(ctx)
+
+	wallet, mnemonic, err := hdBackend(stack).NewWallet(passphrase, password)
+	if err != nil {
+		utils.Fatalf("Failed to create HD wallet: %v", err)
+	}
+	fmt.Printf("Wallet: %s\n", wallet.URL())
+	fmt.Printf("Address: {%x}\n", wallet.Accounts()[0].Address)
+	fmt.Printf("Mnemonic: %s\n", mnemonic)
+	return nil
+}
+
+// hdWalletImport creates a new HD wallet from an existing mnemonic.
+func hdWalletImport(ctx *cli.Context) error {
+	var mnemonic string
+	if file := ctx.Args().First(); file != "" {
+		blob, err := ioutil.ReadFile(file)
+		if err != nil {
+			utils.Fatalf("Failed to read mnemonic file: %v", err)
+		}
+		mnemonic = string(blob)
+	} else {
+		var err error
+		if mnemonic, err = console.Stdin.PromptPassword("Mnemonic: "); err != nil {
+			utils.Fatalf("Failed to read mnemonic: %v", err)
+		}
+	}
+	stack, _ := makeConfigNode(ctx)
+	password, passphrase := hdPassphrases(ctx)
+
+	wallet, err := hdBackend(stack).Import(mnemonic, passphrase, password)
+	if err != nil {
+		utils.Fatalf("Failed to import HD wallet: %v", err)
+	}
+	fmt.Printf("Wallet: %s\n", wallet.URL())
+	fmt.Printf("Address: {%x}\n", wallet.Accounts()[0].Address)
+	return nil
+}
+
+// hdWalletExport decrypts an HD wallet and prints its mnemonic.
+func hdWalletExport(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("A single HD wallet must be given as argument")
+	}
+	stack, _ := makeConfigNode(ctx)
+	wallet := hdWallet(stack, ctx.Args().First())
+
+	password := getPassPhrase(fmt.Sprintf("Unlocking wallet %s", wallet.URL()), false, 0, utils.MakePasswordList(ctx))
+	mnemonic, passphrase, err := hdBackend(stack).Export(wallet, password)
+	if err != nil {
+		utils.Fatalf("Failed to export HD wallet: %v", err)
+	}
+	fmt.Printf("Mnemonic: %s\n", mnemonic)
+	if passphrase != "" {
+		fmt.Printf("BIP-39 passphrase: %s\n", passphrase)
+	}
+	return nil
+}
+
+// hdWalletDerive derives a new account of an HD wallet and pins it.
+func hdWalletDerive(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("An HD wallet and a derivation path must be given as arguments")
+	}
+	path, err := accounts.ParseDerivationPath(ctx.Args().Get(1))
+	if err != nil {
+		utils.Fatalf("Invalid derivation path: %v", err)
+	}
+	stack, _ := makeConfigNode(ctx)
+	wallet := hdWallet(stack, ctx.Args().First())
+
+	password := getPassPhrase(fmt.Sprintf("Unlocking wallet %s", wallet.URL()), false, 0, utils.MakePasswordList(ctx))
+	if err := wallet.Open(password); err != nil {
+		utils.Fatalf("Failed to unlock HD wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	account, err := wallet.Derive(path, true)
+	if err != nil {
+		utils.Fatalf("Failed to derive account: %v", err)
+	}
+	fmt.Printf("Address: {%x}\n", account.Address)
+	fmt.Printf("URL: %s\n", account.URL)
+	return nil
+}
@@ -106,6 +106,30 @@ Repeat passphrase: {{.InputLine "foobar2"}}
 `)
 }
 
+func TestAccountUpdateKDF(t *testing.T) {
+	datadir := tmpDatadirWithKeystore(t)
+	geth := runGeth(t, "account", "update",
+		"--datadir", datadir, "--lightkdf", "--kdf", "argon2id",
+		"f466859ead1932d743d622cb74fc058882e8648a")
+	geth.Expect(`
+Unlocking account f466859ead1932d743d622cb74fc058882e8648a | Attempt 1/3
+!! Unsupported terminal, password will be echoed.
+Passphrase: {{.InputLine "foobar"}}
+Please give a new password. Do not forget this password.
+Passphrase: {{.InputLine "foobar"}}
+Repeat passphrase: {{.InputLine "foobar"}}
+`)
+	geth.ExpectExit()
+
+	keyjson, err := ioutil.ReadFile(filepath.Join(datadir, "keystore", "aaa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(keyjson), `"kdf":"argon2id"`) {
+		t.Fatalf("key not re-encrypted with argon2id: %s", keyjson)
+	}
+}
+
 func TestWalletImport(t *testing.T) {
 	geth := runGeth(t, "wallet", "import", "--lightkdf", "testdata/guswallet.json")
 	defer geth.ExpectExit()
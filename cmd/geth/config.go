@@ -178,6 +178,11 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 		utils.RegisterEthStatsService(stack, cfg.Ethstats.URL)
 	}
 
+	// Add the multisig wallet backend to the account manager if requested.
+	if ctx.GlobalBool(utils.MultisigEnabledFlag.Name) {
+		utils.RegisterMultisigService(stack)
+	}
+
 	// Add the release oracle service so it boots along with node.
 	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
 		config := release.Config{
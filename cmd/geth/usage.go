@@ -74,11 +74,15 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TestnetFlag,
 			utils.RinkebyFlag,
 			utils.SyncModeFlag,
+			utils.CheckpointFlag,
 			utils.EthStatsURLFlag,
 			utils.IdentityFlag,
 			utils.LightServFlag,
 			utils.LightPeersFlag,
+			utils.ULCTrustedServersFlag,
+			utils.ULCMinTrustedFractionFlag,
 			utils.LightKDFFlag,
+			utils.KDFFlag,
 		},
 	},
 	{Name: "DEVELOPER CHAIN",
@@ -135,6 +139,8 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.UnlockedAccountFlag,
 			utils.PasswordFileFlag,
+			utils.ExternalSignerFlag,
+			utils.MultisigEnabledFlag,
 		},
 	},
 	{
@@ -144,6 +150,10 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.RPCListenAddrFlag,
 			utils.RPCPortFlag,
 			utils.RPCApiFlag,
+			utils.LogsMaxRangeFlag,
+			utils.LogsMaxResultsFlag,
+			utils.AddressIndexFlag,
+			utils.AddressIndexInternalFlag,
 			utils.WSEnabledFlag,
 			utils.WSListenAddrFlag,
 			utils.WSPortFlag,
@@ -169,7 +179,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.NATFlag,
 			utils.NoDiscoverFlag,
 			utils.DiscoveryV5Flag,
+			utils.DNSDiscoveryFlag,
 			utils.NetrestrictFlag,
+			utils.BandwidthLimitFlag,
 			utils.NodeKeyFileFlag,
 			utils.NodeKeyHexFlag,
 		},
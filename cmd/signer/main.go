@@ -0,0 +1,459 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// signer is a standalone daemon holding the accounts of the user and signing
+// transactions and data on behalf of external callers, after approval.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/console"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core"
+	"github.com/ethereum/go-ethereum/signer/rules"
+	"github.com/ethereum/go-ethereum/signer/storage"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	gitCommit = "" // Git SHA1 commit hash of the release (set via linker flags)
+
+	app *cli.App // the main app instance
+)
+
+var (
+	logLevelFlag = cli.IntFlag{
+		Name:  "loglevel",
+		Value: 4,
+		Usage: "log level to emit to the screen",
+	}
+	keystoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Value: filepath.Join(node.DefaultDataDir(), "keystore"),
+		Usage: "Directory for the keystore",
+	}
+	configdirFlag = cli.StringFlag{
+		Name:  "configdir",
+		Value: defaultConfigDir(),
+		Usage: "Directory for signer configuration, the master seed and the encrypted vaults",
+	}
+	chainIdFlag = cli.Int64Flag{
+		Name:  "chainid",
+		Value: 1,
+		Usage: "chain id to use for signing (1=mainnet, 3=ropsten, 4=rinkeby)",
+	}
+	rpcPortFlag = cli.IntFlag{
+		Name:  "rpcport",
+		Usage: "HTTP-RPC server listening port",
+		Value: node.DefaultHTTPPort + 5,
+	}
+	signerSecretFlag = cli.StringFlag{
+		Name:  "signersecret",
+		Usage: "A file containing the (encrypted) master seed to encrypt the signer's vaults with",
+	}
+	ruleFlag = cli.StringFlag{
+		Name:  "rules",
+		Usage: "Enable rule-based approval using the given javascript file (must be attested)",
+	}
+	stdiouiFlag = cli.BoolFlag{
+		Name: "stdio-ui",
+		Usage: "Use STDIN/STDOUT as a channel for an external UI. " +
+			"This means that an STDIN/STDOUT is used for RPC-communication with a e.g. a graphical user " +
+			"interface, and can be used when the signer is started by an external process.",
+	}
+	auditLogFlag = cli.StringFlag{
+		Name:  "auditlog",
+		Usage: "File used to emit audit logs. Set to \"\" to disable",
+		Value: "audit.log",
+	}
+)
+
+var (
+	initCommand = cli.Command{
+		Action:    utils.MigrateFlags(initializeSecrets),
+		Name:      "init",
+		Usage:     "Initialize the signer, generate the master seed",
+		ArgsUsage: "",
+		Flags: []cli.Flag{
+			logLevelFlag,
+			configdirFlag,
+		},
+		Description: `
+The init command generates a master seed which the signer can use to store
+credentials and data. The seed is encrypted with a password chosen by the user.`,
+	}
+	attestCommand = cli.Command{
+		Action:    utils.MigrateFlags(attestFile),
+		Name:      "attest",
+		Usage:     "Attest that a js-file is to be used",
+		ArgsUsage: "<sha256sum>",
+		Flags: []cli.Flag{
+			logLevelFlag,
+			configdirFlag,
+			signerSecretFlag,
+		},
+		Description: `
+The attest command stores the sha256 of the rule.js-file that you want to use
+for automatic processing of incoming requests. The rule file is only used by the
+signer if its hash matches the attested one, ensuring it wasn't tampered with.`,
+	}
+	addCredentialCommand = cli.Command{
+		Action:    utils.MigrateFlags(addCredential),
+		Name:      "setpw",
+		Usage:     "Store a credential for a keystore file",
+		ArgsUsage: "<address>",
+		Flags: []cli.Flag{
+			logLevelFlag,
+			configdirFlag,
+			signerSecretFlag,
+		},
+		Description: `
+The setpw command stores a password for a given address (keyfile) in the
+encrypted credential vault. The password is used by the rule engine to sign the
+requests it approves.`,
+	}
+)
+
+func init() {
+	app = utils.NewApp(gitCommit, "Manage Ethereum account operations")
+	app.Flags = []cli.Flag{
+		logLevelFlag,
+		keystoreFlag,
+		configdirFlag,
+		chainIdFlag,
+		utils.LightKDFFlag,
+		utils.NoUSBFlag,
+		utils.RPCListenAddrFlag,
+		utils.IPCDisabledFlag,
+		utils.RPCEnabledFlag,
+		rpcPortFlag,
+		signerSecretFlag,
+		ruleFlag,
+		stdiouiFlag,
+		auditLogFlag,
+	}
+	app.Action = signer
+	app.Commands = []cli.Command{initCommand, attestCommand, addCredentialCommand}
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// defaultConfigDir is the default location of the signer's configuration, in a
+// dedicated folder within the default data directory.
+func defaultConfigDir() string {
+	if datadir := node.DefaultDataDir(); datadir != "" {
+		return filepath.Join(datadir, "signer")
+	}
+	return ""
+}
+
+// setupLogging configures the terminal logger with the requested verbosity.
+func setupLogging(c *cli.Context) {
+	log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(c.Int(logLevelFlag.Name)), log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+}
+
+// initializeSecrets generates a new random master seed, encrypts it with a user
+// provided password and writes it into the configuration directory.
+func initializeSecrets(c *cli.Context) error {
+	setupLogging(c)
+
+	configDir := c.String(configdirFlag.Name)
+	location := filepath.Join(configDir, "secrets.dat")
+	if _, err := os.Stat(location); err == nil {
+		return fmt.Errorf("file %v already exists, will not overwrite", location)
+	}
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return err
+	}
+	masterSeed := make([]byte, 256)
+	if _, err := rand.Read(masterSeed); err != nil {
+		return err
+	}
+	password := getPassPhrase("The master seed of the signer is generated and stored encrypted.\nPlease provide a password to encrypt it with:", true)
+
+	cipherSeed, err := keystore.EncryptDataV3(masterSeed, []byte(password), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt master seed: %v", err)
+	}
+	content, err := json.Marshal(cipherSeed)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(location, content, 0400); err != nil {
+		return err
+	}
+	fmt.Printf("A master seed has been generated into %s\n", location)
+	fmt.Printf(`
+This is required to be able to store credentials, such as:
+* Passwords for keystores (used by rule engine)
+* Storage for javascript rules
+* Hash of rule-file
+
+You should treat that file with utmost secrecy, and make a backup of it.
+NOTE: This file does not contain your accounts. Those need to be backed up separately!
+`)
+	return nil
+}
+
+// attestFile stores the hash of the rule file the user is willing to run.
+func attestFile(c *cli.Context) error {
+	if len(c.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	setupLogging(c)
+
+	hash, err := hex.DecodeString(strings.TrimPrefix(c.Args().First(), "0x"))
+	if err != nil || len(hash) != sha256.Size {
+		utils.Fatalf("Invalid sha256 hash: %s", c.Args().First())
+	}
+	vault, err := openVault(c)
+	if err != nil {
+		utils.Fatalf(err.Error())
+	}
+	vault.config.Put("ruleset_sha256", hex.EncodeToString(hash))
+	log.Info("Ruleset attestation updated", "sha256", hex.EncodeToString(hash))
+	return nil
+}
+
+// addCredential stores the password of an account in the credential vault.
+func addCredential(c *cli.Context) error {
+	if len(c.Args()) < 1 {
+		utils.Fatalf("This command requires an address to be passed as an argument.")
+	}
+	setupLogging(c)
+
+	address := c.Args().First()
+	if !common.IsHexAddress(address) {
+		utils.Fatalf("Invalid address: %s", address)
+	}
+	vault, err := openVault(c)
+	if err != nil {
+		utils.Fatalf(err.Error())
+	}
+	password := getPassPhrase("Enter a password to store with this address.", true)
+	vault.credentials.Put(strings.ToLower(common.HexToAddress(address).Hex()), password)
+
+	log.Info("Credential store updated", "address", common.HexToAddress(address))
+	return nil
+}
+
+// vault is the collection of the encrypted storages of the signer, all keyed
+// from the master seed.
+type vault struct {
+	config      storage.Storage // Signer configuration, e.g. the attested rules
+	credentials storage.Storage // Account passwords for the rule engine
+	jsStorage   storage.Storage // Persistent state of the rule engine
+}
+
+// openVault decrypts the master seed and opens the storages derived from it.
+func openVault(c *cli.Context) (*vault, error) {
+	configDir := c.String(configdirFlag.Name)
+
+	seedFile := c.String(signerSecretFlag.Name)
+	if seedFile == "" {
+		seedFile = filepath.Join(configDir, "secrets.dat")
+	}
+	masterSeed, err := readMasterSeed(seedFile)
+	if err != nil {
+		return nil, err
+	}
+	// Place the vaults into a directory specific to the seed, so that changing
+	// the seed doesn't garble previous data
+	vaultID := crypto.Keccak256(masterSeed)[:10]
+	vaultDir := filepath.Join(configDir, common.Bytes2Hex(vaultID))
+	if err := os.MkdirAll(vaultDir, 0700); err != nil {
+		return nil, err
+	}
+	return &vault{
+		config:      storage.NewAESEncryptedStorage(filepath.Join(vaultDir, "config.json"), deriveKey("config", masterSeed)),
+		credentials: storage.NewAESEncryptedStorage(filepath.Join(vaultDir, "credentials.json"), deriveKey("credentials", masterSeed)),
+		jsStorage:   storage.NewAESEncryptedStorage(filepath.Join(vaultDir, "jsstorage.json"), deriveKey("jsstorage", masterSeed)),
+	}, nil
+}
+
+// deriveKey derives the encryption key of a single vault from the master seed,
+// so that no two vaults share a key.
+func deriveKey(label string, masterSeed []byte) []byte {
+	key := sha256.Sum256(append([]byte(label), masterSeed...))
+	return key[:]
+}
+
+// readMasterSeed loads the encrypted master seed and decrypts it with a password
+// requested from the user.
+func readMasterSeed(file string) ([]byte, error) {
+	cipherKey, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master seed (run 'init' first?): %v", err)
+	}
+	var encSeed keystore.CryptoJSON
+	if err := json.Unmarshal(cipherKey, &encSeed); err != nil {
+		return nil, fmt.Errorf("failed to parse master seed: %v", err)
+	}
+	password := getPassPhrase("Decrypt master seed of the signer", false)
+	masterSeed, err := keystore.DecryptDataV3(encSeed, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the master seed: %v", err)
+	}
+	if len(masterSeed) < 256 {
+		return nil, errors.New("master seed of insufficient length")
+	}
+	return masterSeed, nil
+}
+
+// signer runs the signer daemon until interrupted.
+func signer(c *cli.Context) error {
+	setupLogging(c)
+	if args := c.Args(); len(args) > 0 {
+		return fmt.Errorf("invalid command: %q", args[0])
+	}
+	var ui core.SignerUI
+	if c.Bool(stdiouiFlag.Name) {
+		log.Info("Using stdin/stdout as UI-channel")
+		ui = core.NewStdIOUI()
+	} else {
+		log.Info("Using CLI as UI-channel")
+		ui = core.NewCommandlineUI()
+	}
+	// Rule based approval needs the vault for the attestation and credentials
+	if ruleFile := c.String(ruleFlag.Name); ruleFile != "" {
+		ruleset, err := ioutil.ReadFile(ruleFile)
+		if err != nil {
+			utils.Fatalf("Failed to read rules file: %v", err)
+		}
+		vault, err := openVault(c)
+		if err != nil {
+			utils.Fatalf(err.Error())
+		}
+		hash := sha256.Sum256(ruleset)
+		if attested := vault.config.Get("ruleset_sha256"); attested != hex.EncodeToString(hash[:]) {
+			log.Warn("Rules not attested, ignoring", "sha256", hex.EncodeToString(hash[:]), "attested", attested)
+		} else {
+			engine, err := rules.NewRuleEvaluator(ui, vault.jsStorage, vault.credentials)
+			if err != nil {
+				utils.Fatalf(err.Error())
+			}
+			if err := engine.Init(string(ruleset)); err != nil {
+				utils.Fatalf("Failed to load rules: %v", err)
+			}
+			ui = engine
+			log.Info("Rule engine configured", "file", ruleFile)
+		}
+	}
+	var (
+		api       core.ExternalAPI
+		signerAPI = core.NewSignerAPI(c.Int64(chainIdFlag.Name), c.String(keystoreFlag.Name), c.Bool(utils.NoUSBFlag.Name), ui, c.Bool(utils.LightKDFFlag.Name))
+	)
+	api = signerAPI
+	if logfile := c.String(auditLogFlag.Name); logfile != "" {
+		audit, err := core.NewAuditLogger(logfile, api)
+		if err != nil {
+			utils.Fatalf(err.Error())
+		}
+		log.Info("Audit logs configured", "file", logfile)
+		api = audit
+	}
+	// Register the signer's API and start the configured endpoints
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", api); err != nil {
+		utils.Fatalf("Could not register API: %v", err)
+	}
+	extapiURL, ipcapiURL := "n/a", "n/a"
+
+	if c.Bool(utils.RPCEnabledFlag.Name) {
+		endpoint := fmt.Sprintf("%s:%d", c.String(utils.RPCListenAddrFlag.Name), c.Int(rpcPortFlag.Name))
+		listener, err := net.Listen("tcp", endpoint)
+		if err != nil {
+			utils.Fatalf("Could not start http listener: %v", err)
+		}
+		extapiURL = fmt.Sprintf("http://%s", endpoint)
+		log.Info("HTTP endpoint opened", "url", extapiURL)
+
+		go rpc.NewHTTPServer(nil, server).Serve(listener)
+		defer listener.Close()
+	}
+	if !c.Bool(utils.IPCDisabledFlag.Name) {
+		ipcapiURL = filepath.Join(c.String(configdirFlag.Name), "signer.ipc")
+		if err := os.MkdirAll(filepath.Dir(ipcapiURL), 0700); err != nil {
+			utils.Fatalf("Could not create IPC directory: %v", err)
+		}
+		listener, err := rpc.CreateIPCListener(ipcapiURL)
+		if err != nil {
+			utils.Fatalf("Could not start IPC api: %v", err)
+		}
+		log.Info("IPC endpoint opened", "url", ipcapiURL)
+
+		go server.ServeListener(listener)
+		defer listener.Close()
+	}
+	ui.OnSignerStartup(core.StartupInfo{
+		Info: map[string]interface{}{
+			"extapi_version": core.ExternalAPIVersion,
+			"extapi_http":    extapiURL,
+			"extapi_ipc":     ipcapiURL,
+		},
+	})
+	abortChan := make(chan os.Signal, 1)
+	signal.Notify(abortChan, os.Interrupt)
+
+	sig := <-abortChan
+	log.Info("Exiting...", "signal", sig)
+
+	server.Stop()
+	return nil
+}
+
+// getPassPhrase requests a password interactively from the user, optionally
+// asking for it a second time to confirm it.
+func getPassPhrase(prompt string, confirmation bool) string {
+	fmt.Println(prompt)
+	password, err := console.Stdin.PromptPassword("Passphrase: ")
+	if err != nil {
+		utils.Fatalf("Failed to read passphrase: %v", err)
+	}
+	if confirmation {
+		confirm, err := console.Stdin.PromptPassword("Repeat passphrase: ")
+		if err != nil {
+			utils.Fatalf("Failed to read passphrase confirmation: %v", err)
+		}
+		if password != confirm {
+			utils.Fatalf("Passphrases do not match")
+		}
+	}
+	return password
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/contracts/multisig"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -41,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/dashboard"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/ethstats"
@@ -125,6 +127,14 @@ var (
 		Name:  "nousb",
 		Usage: "Disables monitoring for and managing USB hardware wallets",
 	}
+	ExternalSignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "External signer (url or path to ipc file) to use instead of the local accounts",
+	}
+	MultisigEnabledFlag = cli.BoolFlag{
+		Name:  "multisig",
+		Usage: "Enable multisig wallet contracts as accounts, managed via the multisig RPC API",
+	}
 	NetworkIdFlag = cli.Uint64Flag{
 		Name:  "networkid",
 		Usage: "Network identifier (integer, 1=Frontier, 2=Morden (disused), 3=Ropsten, 4=Rinkeby)",
@@ -169,6 +179,10 @@ var (
 		Usage: `Blockchain sync mode ("fast", "full", or "light")`,
 		Value: &defaultSyncMode,
 	}
+	CheckpointFlag = cli.StringFlag{
+		Name:  "checkpoint",
+		Usage: "Trusted checkpoint the synced chain must contain (<number>:<hash>:<td>)",
+	}
 
 	LightServFlag = cli.IntFlag{
 		Name:  "lightserv",
@@ -180,10 +194,24 @@ var (
 		Usage: "Maximum number of LES client peers",
 		Value: 20,
 	}
+	ULCTrustedServersFlag = cli.StringFlag{
+		Name:  "ulc.trusted",
+		Usage: "Comma separated list of trusted LES server enode URLs (enables ultra light client mode)",
+	}
+	ULCMinTrustedFractionFlag = cli.IntFlag{
+		Name:  "ulc.fraction",
+		Usage: "Percentage of trusted servers that must announce a header before an ultra light client accepts it",
+		Value: eth.DefaultULCMinTrustedFraction,
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
 	}
+	KDFFlag = cli.StringFlag{
+		Name:  "kdf",
+		Usage: `Key derivation function to encrypt new and updated keys with ("scrypt" or "argon2id")`,
+		Value: keystore.KDFScrypt,
+	}
 	// Dashboard settings
 	DashboardEnabledFlag = cli.BoolFlag{
 		Name:  "dashboard",
@@ -387,6 +415,24 @@ var (
 		Usage: "API's offered over the HTTP-RPC interface",
 		Value: "",
 	}
+	LogsMaxRangeFlag = cli.Uint64Flag{
+		Name:  "logs.maxrange",
+		Usage: "Maximum number of blocks searched by a log query, wider queries must be paginated (0 = unlimited)",
+		Value: eth.DefaultConfig.Filters.MaxBlockRange,
+	}
+	LogsMaxResultsFlag = cli.IntFlag{
+		Name:  "logs.maxresults",
+		Usage: "Maximum number of logs returned by a log query, larger results must be paginated (0 = unlimited)",
+		Value: eth.DefaultConfig.Filters.MaxResults,
+	}
+	AddressIndexFlag = cli.BoolFlag{
+		Name:  "addrindex",
+		Usage: "Maintain an index of the transactions sent from and to every address",
+	}
+	AddressIndexInternalFlag = cli.BoolFlag{
+		Name:  "addrindex.internal",
+		Usage: "Index the targets of internal calls too (requires the historical state)",
+	}
 	IPCDisabledFlag = cli.BoolFlag{
 		Name:  "ipcdisable",
 		Usage: "Disable the IPC-RPC server",
@@ -480,10 +526,19 @@ var (
 		Name:  "v5disc",
 		Usage: "Enables the experimental RLPx V5 (Topic Discovery) mechanism",
 	}
+	DNSDiscoveryFlag = cli.StringFlag{
+		Name:  "discovery.dns",
+		Usage: "Comma separated enrtree:// URLs of DNS node lists used for peer discovery",
+		Value: "",
+	}
 	NetrestrictFlag = cli.StringFlag{
 		Name:  "netrestrict",
 		Usage: "Restricts network communication to the given IP networks (CIDR masks)",
 	}
+	BandwidthLimitFlag = cli.StringFlag{
+		Name:  "bwlimit",
+		Usage: "Comma separated per-protocol bandwidth limits in bytes/s, as protocol=ingress:egress (e.g. les=1048576:524288, 0 = unlimited)",
+	}
 
 	// ATM the url is left to the user and deployment to
 	JSpathFlag = cli.StringFlag{
@@ -742,6 +797,9 @@ func MakeAddress(ks *keystore.KeyStore, account string) (accounts.Account, error
 		return accounts.Account{Address: common.HexToAddress(account)}, nil
 	}
 	// Otherwise try to interpret the account as a keystore index
+	if ks == nil {
+		return accounts.Account{}, fmt.Errorf("invalid account address %q", account)
+	}
 	index, err := strconv.Atoi(account)
 	if err != nil || index < 0 {
 		return accounts.Account{}, fmt.Errorf("invalid account address or index %q", account)
@@ -789,6 +847,35 @@ func MakePasswordList(ctx *cli.Context) []string {
 	return lines
 }
 
+// parseBandwidthLimits parses a comma separated list of per-protocol bandwidth
+// limits in the form protocol=ingress:egress.
+func parseBandwidthLimits(spec string) (map[string]p2p.BandwidthLimit, error) {
+	limits := make(map[string]p2p.BandwidthLimit)
+	for _, entry := range strings.Split(spec, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "=")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid limit %q, want protocol=ingress:egress", entry)
+		}
+		rates := strings.Split(parts[1], ":")
+		if len(rates) != 2 {
+			return nil, fmt.Errorf("invalid limit %q, want protocol=ingress:egress", entry)
+		}
+		ingress, err := strconv.ParseInt(rates[0], 10, 64)
+		if err != nil || ingress < 0 {
+			return nil, fmt.Errorf("invalid ingress limit %q", rates[0])
+		}
+		egress, err := strconv.ParseInt(rates[1], 10, 64)
+		if err != nil || egress < 0 {
+			return nil, fmt.Errorf("invalid egress limit %q", rates[1])
+		}
+		limits[parts[0]] = p2p.BandwidthLimit{Ingress: ingress, Egress: egress}
+	}
+	return limits, nil
+}
+
 func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	setNodeKey(ctx, cfg)
 	setNAT(ctx, cfg)
@@ -817,6 +904,23 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 		cfg.DiscoveryV5 = true
 	}
 
+	if ctx.GlobalIsSet(DNSDiscoveryFlag.Name) {
+		urls := ctx.GlobalString(DNSDiscoveryFlag.Name)
+		if urls == "" {
+			cfg.DNSDiscovery = nil
+		} else {
+			cfg.DNSDiscovery = strings.Split(urls, ",")
+		}
+	}
+
+	if ctx.GlobalIsSet(BandwidthLimitFlag.Name) {
+		limits, err := parseBandwidthLimits(ctx.GlobalString(BandwidthLimitFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", BandwidthLimitFlag.Name, err)
+		}
+		cfg.BandwidthLimits = limits
+	}
+
 	if netrestrict := ctx.GlobalString(NetrestrictFlag.Name); netrestrict != "" {
 		list, err := netutil.ParseNetlist(netrestrict)
 		if err != nil {
@@ -832,6 +936,7 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 		cfg.DiscoveryV5Addr = ":0"
 		cfg.NoDiscovery = true
 		cfg.DiscoveryV5 = false
+		cfg.DNSDiscovery = nil
 	}
 }
 
@@ -860,9 +965,15 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(LightKDFFlag.Name) {
 		cfg.UseLightweightKDF = ctx.GlobalBool(LightKDFFlag.Name)
 	}
+	if ctx.GlobalIsSet(KDFFlag.Name) {
+		cfg.KeyStoreKDF = ctx.GlobalString(KDFFlag.Name)
+	}
 	if ctx.GlobalIsSet(NoUSBFlag.Name) {
 		cfg.NoUSB = ctx.GlobalBool(NoUSBFlag.Name)
 	}
+	if ctx.GlobalIsSet(ExternalSignerFlag.Name) {
+		cfg.ExternalSigner = ctx.GlobalString(ExternalSignerFlag.Name)
+	}
 }
 
 func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
@@ -874,6 +985,42 @@ func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
 	}
 }
 
+func setAddressIndex(ctx *cli.Context, cfg *eth.Config) {
+	if ctx.GlobalIsSet(AddressIndexFlag.Name) {
+		cfg.AddressIndex = ctx.GlobalBool(AddressIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(AddressIndexInternalFlag.Name) {
+		cfg.AddressIndexInternal = ctx.GlobalBool(AddressIndexInternalFlag.Name)
+		cfg.AddressIndex = cfg.AddressIndex || cfg.AddressIndexInternal
+	}
+}
+
+// setULC configures the ultra light client mode from the command line flags.
+func setULC(ctx *cli.Context, cfg *eth.Config) {
+	if !ctx.GlobalIsSet(ULCTrustedServersFlag.Name) {
+		return
+	}
+	if cfg.SyncMode != downloader.LightSync {
+		Fatalf("Option %q requires light sync mode", ULCTrustedServersFlag.Name)
+	}
+	cfg.ULC = &eth.ULCConfig{
+		TrustedServers:     splitAndTrim(ctx.GlobalString(ULCTrustedServersFlag.Name)),
+		MinTrustedFraction: ctx.GlobalInt(ULCMinTrustedFractionFlag.Name),
+	}
+	if fraction := cfg.ULC.MinTrustedFraction; fraction <= 0 || fraction > 100 {
+		Fatalf("Option %q: fraction must be between 1 and 100", ULCMinTrustedFractionFlag.Name)
+	}
+}
+
+func setFilters(ctx *cli.Context, cfg *filters.Config) {
+	if ctx.GlobalIsSet(LogsMaxRangeFlag.Name) {
+		cfg.MaxBlockRange = ctx.GlobalUint64(LogsMaxRangeFlag.Name)
+	}
+	if ctx.GlobalIsSet(LogsMaxResultsFlag.Name) {
+		cfg.MaxResults = ctx.GlobalInt(LogsMaxResultsFlag.Name)
+	}
+}
+
 func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolNoLocalsFlag.Name) {
 		cfg.NoLocals = ctx.GlobalBool(TxPoolNoLocalsFlag.Name)
@@ -984,9 +1131,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	checkExclusive(ctx, LightServFlag, LightModeFlag)
 	checkExclusive(ctx, LightServFlag, SyncModeFlag, "light")
 
-	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	// The local keystore is missing if accounts are managed by an external signer
+	var ks *keystore.KeyStore
+	if keystores := stack.AccountManager().Backends(keystore.KeyStoreType); len(keystores) > 0 {
+		ks = keystores[0].(*keystore.KeyStore)
+	}
 	setEtherbase(ctx, ks, cfg)
 	setGPO(ctx, &cfg.GPO)
+	setFilters(ctx, &cfg.Filters)
+	setAddressIndex(ctx, cfg)
 	setTxPool(ctx, &cfg.TxPool)
 	setEthash(ctx, cfg)
 
@@ -998,12 +1151,19 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	case ctx.GlobalBool(LightModeFlag.Name):
 		cfg.SyncMode = downloader.LightSync
 	}
+	if ctx.GlobalIsSet(CheckpointFlag.Name) {
+		cfg.Checkpoint = new(downloader.Checkpoint)
+		if err := cfg.Checkpoint.UnmarshalText([]byte(ctx.GlobalString(CheckpointFlag.Name))); err != nil {
+			Fatalf("Option %q: %v", CheckpointFlag.Name, err)
+		}
+	}
 	if ctx.GlobalIsSet(LightServFlag.Name) {
 		cfg.LightServ = ctx.GlobalInt(LightServFlag.Name)
 	}
 	if ctx.GlobalIsSet(LightPeersFlag.Name) {
 		cfg.LightPeers = ctx.GlobalInt(LightPeersFlag.Name)
 	}
+	setULC(ctx, cfg)
 	if ctx.GlobalIsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.GlobalUint64(NetworkIdFlag.Name)
 	}
@@ -1043,6 +1203,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		}
 		cfg.Genesis = core.DefaultRinkebyGenesisBlock()
 	case ctx.GlobalBool(DeveloperFlag.Name):
+		if ks == nil {
+			Fatalf("Developer mode requires a local keystore, not an external signer")
+		}
 		// Create new developer account or reuse existing one
 		var (
 			developer accounts.Account
@@ -1135,6 +1298,14 @@ func RegisterEthStatsService(stack *node.Node, url string) {
 	}
 }
 
+// RegisterMultisigService adds a multisig wallet backend to the account manager
+// of the node and exposes it over RPC.
+func RegisterMultisigService(stack *node.Node) {
+	if err := stack.Register(multisig.NewService); err != nil {
+		Fatalf("Failed to register the multisig wallet service: %v", err)
+	}
+}
+
 // SetupNetwork configures the system for either the main net or some test network.
 func SetupNetwork(ctx *cli.Context) {
 	// TODO(fjl): move target gas limit into config
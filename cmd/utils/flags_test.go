@@ -0,0 +1,43 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+func TestParseBandwidthLimits(t *testing.T) {
+	limits, err := parseBandwidthLimits("les=1048576:524288, eth=0:1024")
+	if err != nil {
+		t.Fatalf("failed to parse limits: %v", err)
+	}
+	want := map[string]p2p.BandwidthLimit{
+		"les": {Ingress: 1048576, Egress: 524288},
+		"eth": {Ingress: 0, Egress: 1024},
+	}
+	if !reflect.DeepEqual(limits, want) {
+		t.Fatalf("limits mismatch: have %v, want %v", limits, want)
+	}
+	for _, spec := range []string{"les", "les=1", "=1:2", "les=a:1", "les=1:-1"} {
+		if _, err := parseBandwidthLimits(spec); err == nil {
+			t.Errorf("invalid limit %q accepted", spec)
+		}
+	}
+}
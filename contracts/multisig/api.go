@@ -0,0 +1,102 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package multisig
+
+import (
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PublicMultisigAPI provides an API to inspect the multisig wallets tracked by
+// a backend.
+type PublicMultisigAPI struct {
+	backend *Backend
+}
+
+// NewPublicMultisigAPI creates a new API for the multisig wallets of a backend.
+func NewPublicMultisigAPI(backend *Backend) *PublicMultisigAPI {
+	return &PublicMultisigAPI{backend: backend}
+}
+
+// RPCProposal is an outstanding proposal of a multisig wallet, formatted for
+// the RPC API.
+type RPCProposal struct {
+	ID            hexutil.Uint64   `json:"id"`
+	To            common.Address   `json:"to"`
+	Value         *hexutil.Big     `json:"value"`
+	Data          hexutil.Bytes    `json:"data"`
+	Confirmations []common.Address `json:"confirmations"`
+}
+
+// Wallets returns the addresses of the tracked multisig wallet contracts.
+func (api *PublicMultisigAPI) Wallets() []common.Address {
+	addresses := make([]common.Address, 0) // return [] instead of nil if empty
+	for _, wallet := range api.backend.Wallets() {
+		addresses = append(addresses, wallet.Accounts()[0].Address)
+	}
+	return addresses
+}
+
+// Proposals returns the outstanding proposals of an open multisig wallet, along
+// with the owners who already confirmed them.
+func (api *PublicMultisigAPI) Proposals(address common.Address) ([]*RPCProposal, error) {
+	pending, err := api.backend.Proposals(address)
+	if err != nil {
+		return nil, err
+	}
+	proposals := make([]*RPCProposal, len(pending))
+	for i, tx := range pending {
+		proposals[i] = &RPCProposal{
+			ID:            hexutil.Uint64(tx.ID),
+			To:            tx.To,
+			Value:         (*hexutil.Big)(tx.Value),
+			Data:          tx.Data,
+			Confirmations: tx.Confirmations,
+		}
+	}
+	return proposals, nil
+}
+
+// PrivateMultisigAPI provides an API to manage the multisig wallets tracked by a
+// backend. It's private, as tracked wallets sign with the owner accounts of the
+// node.
+type PrivateMultisigAPI struct {
+	backend *Backend
+}
+
+// NewPrivateMultisigAPI creates a new API to manage the multisig wallets of a
+// backend.
+func NewPrivateMultisigAPI(backend *Backend) *PrivateMultisigAPI {
+	return &PrivateMultisigAPI{backend: backend}
+}
+
+// Track starts managing the multisig wallet contract deployed at the given
+// address, signing its transactions with the given owner account. It returns
+// the URL of the new wallet.
+func (api *PrivateMultisigAPI) Track(address common.Address, owner common.Address) (string, error) {
+	wallet, err := api.backend.Track(address, accounts.Account{Address: owner})
+	if err != nil {
+		return "", err
+	}
+	return wallet.URL().String(), nil
+}
+
+// Untrack stops managing the multisig wallet deployed at the given address.
+func (api *PrivateMultisigAPI) Untrack(address common.Address) error {
+	return api.backend.Untrack(address)
+}
@@ -0,0 +1,197 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package multisig
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/multisig/contract"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Scheme is the protocol scheme prefixing account and wallet URLs.
+const Scheme = "multisig"
+
+// BackendType is the reflect type of a multisig wallet backend.
+var BackendType = reflect.TypeOf(&Backend{})
+
+var (
+	// ErrWalletExists is returned if a multisig wallet is tracked that is
+	// already tracked by the backend.
+	ErrWalletExists = errors.New("wallet already tracked")
+
+	// ErrNotOwner is returned if a multisig wallet is tracked with a signer
+	// account that is not one of the owners of the contract.
+	ErrNotOwner = errors.New("account not owner of the wallet")
+)
+
+// Signers is the source of the owner wallets signing the transactions on behalf
+// of a multisig wallet, usually an accounts.Manager.
+type Signers interface {
+	// Find returns the wallet containing the given owner account.
+	Find(account accounts.Account) (accounts.Wallet, error)
+}
+
+// Backend is an accounts.Backend managing multisig wallet contracts, where each
+// wallet signs through one of the owner accounts of its contract.
+type Backend struct {
+	client  bind.ContractBackend // Chain access to interact with the contracts
+	signers Signers              // Source of the owner wallets to sign with
+
+	wallets     []*wallet               // List of wallets currently tracked, sorted by URL
+	updateFeed  event.Feed              // Event feed to notify wallet additions/removals
+	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
+
+	stateLock sync.RWMutex // Protects the internals of the backend from racey access
+}
+
+// NewBackend creates a backend for multisig wallets, interacting with the chain
+// through the given client and signing with owner accounts from signers.
+func NewBackend(client bind.ContractBackend, signers Signers) *Backend {
+	return &Backend{
+		client:  client,
+		signers: signers,
+	}
+}
+
+// Wallets implements accounts.Backend, returning all the multisig wallets
+// currently tracked.
+func (b *Backend) Wallets() []accounts.Wallet {
+	b.stateLock.RLock()
+	defer b.stateLock.RUnlock()
+
+	cpy := make([]accounts.Wallet, len(b.wallets))
+	for i, wallet := range b.wallets {
+		cpy[i] = wallet
+	}
+	return cpy
+}
+
+// Subscribe implements accounts.Backend, creating an async subscription to
+// receive notifications on the addition or removal of multisig wallets.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return b.updateScope.Track(b.updateFeed.Subscribe(sink))
+}
+
+// Track starts managing the multisig wallet contract deployed at the given
+// address, signing its transactions with the given owner account.
+func (b *Backend) Track(address common.Address, owner accounts.Account) (accounts.Wallet, error) {
+	if b.wallet(address) != nil {
+		return nil, ErrWalletExists
+	}
+	multisig, err := contract.NewMultiSig(address, b.client)
+	if err != nil {
+		return nil, err
+	}
+	isOwner, err := multisig.IsOwner(&bind.CallOpts{Context: context.Background()}, owner.Address)
+	if err != nil {
+		return nil, err
+	}
+	if !isOwner {
+		return nil, ErrNotOwner
+	}
+	b.stateLock.Lock()
+	for _, wallet := range b.wallets {
+		if wallet.address == address {
+			b.stateLock.Unlock()
+			return nil, ErrWalletExists
+		}
+	}
+	wallet := newWallet(b, address, owner, multisig)
+	b.wallets = append(b.wallets, wallet)
+	sort.Sort(walletsByURL(b.wallets))
+	b.stateLock.Unlock()
+
+	b.updateFeed.Send(accounts.WalletEvent{Wallet: wallet, Kind: accounts.WalletArrived})
+	return wallet, nil
+}
+
+// Untrack stops managing the multisig wallet deployed at the given address,
+// closing it if it's open.
+func (b *Backend) Untrack(address common.Address) error {
+	b.stateLock.Lock()
+	var dropped *wallet
+	for i, wallet := range b.wallets {
+		if wallet.address == address {
+			dropped = wallet
+			b.wallets = append(b.wallets[:i], b.wallets[i+1:]...)
+			break
+		}
+	}
+	b.stateLock.Unlock()
+
+	if dropped == nil {
+		return accounts.ErrUnknownWallet
+	}
+	dropped.Close()
+	b.updateFeed.Send(accounts.WalletEvent{Wallet: dropped, Kind: accounts.WalletDropped})
+	return nil
+}
+
+// Proposals returns the outstanding proposals of a tracked multisig wallet,
+// sorted by id. The wallet needs to be open for its proposals to be tracked.
+func (b *Backend) Proposals(address common.Address) ([]*Transaction, error) {
+	wallet := b.wallet(address)
+	if wallet == nil {
+		return nil, accounts.ErrUnknownWallet
+	}
+	return wallet.proposals()
+}
+
+// APIs returns the RPC APIs exposing the multisig wallets of the backend.
+func (b *Backend) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "multisig",
+			Version:   "1.0",
+			Service:   NewPublicMultisigAPI(b),
+			Public:    true,
+		}, {
+			Namespace: "multisig",
+			Version:   "1.0",
+			Service:   NewPrivateMultisigAPI(b),
+		},
+	}
+}
+
+// wallet returns the tracked wallet of the given contract, or nil if unknown.
+func (b *Backend) wallet(address common.Address) *wallet {
+	b.stateLock.RLock()
+	defer b.stateLock.RUnlock()
+
+	for _, wallet := range b.wallets {
+		if wallet.address == address {
+			return wallet
+		}
+	}
+	return nil
+}
+
+// walletsByURL implements sort.Interface for []*wallet by URL.
+type walletsByURL []*wallet
+
+func (w walletsByURL) Len() int           { return len(w) }
+func (w walletsByURL) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
+func (w walletsByURL) Less(i, j int) bool { return w[i].url.Cmp(w[j].url) < 0 }
@@ -0,0 +1,1297 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contract
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// MultiSigABI is the input ABI used to generate the binding from.
+const MultiSigABI = "[{\"type\":\"constructor\",\"inputs\":[{\"name\":\"_owners\",\"type\":\"address[]\"},{\"name\":\"_required\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"required\",\"constant\":true,\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"transactionCount\",\"constant\":true,\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"isOwner\",\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getOwners\",\"constant\":true,\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"confirmations\",\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getConfirmationCount\",\"constant\":true,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"outputs\":[{\"name\":\"count\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"isConfirmed\",\"constant\":true,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"transactions\",\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"outputs\":[{\"name\":\"destination\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"},{\"name\":\"data\",\"type\":\"bytes\"},{\"name\":\"executed\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"submitTransaction\",\"constant\":false,\"inputs\":[{\"name\":\"destination\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"},{\"name\":\"data\",\"type\":\"bytes\"}],\"outputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"confirmTransaction\",\"constant\":false,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"revokeConfirmation\",\"constant\":false,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"executeTransaction\",\"constant\":false,\"inputs\":[{\"name\":\"transactionId\",\"type\":\"uint256\"}],\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\"},{\"type\":\"fallback\",\"payable\":true,\"stateMutability\":\"payable\"},{\"type\":\"event\",\"name\":\"Confirmation\",\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}]},{\"type\":\"event\",\"name\":\"Revocation\",\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}]},{\"type\":\"event\",\"name\":\"Submission\",\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}]},{\"type\":\"event\",\"name\":\"Execution\",\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}]},{\"type\":\"event\",\"name\":\"ExecutionFailure\",\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"transactionId\",\"type\":\"uint256\"}]},{\"type\":\"event\",\"name\":\"Deposit\",\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}]}]"
+
+// MultiSigBin is the compiled bytecode used for deploying new contracts.
+const MultiSigBin = `0x6106ff38036106ff6101003961012051801563000000c0578060005561010051610100018051801563000000c05780831163000000c0578060321063000000c0578060025560005b8181101563000000ac578060200283016020015173ffffffffffffffffffffffffffffffffffffffff16801563000000c0578060005260036020526040600020805463000000c057600190558160005260046020526040600020556001016300000047565b61063a61063a6106ff0360003961063a6000f35b600080fd6004361063000000c8577c0100000000000000000000000000000000000000000000000000000000600035048063dc8452cd14630000010c578063b77bf60014630000011d5780632f54bf6e14630000012e578063a0e67e2b1463000001635780633411c81c1463000001b35780638b51d13f1463000001ee578063784547a71463000002075780639ace38c214630000029f578063c6427474146300000318578063c01a8c841463000003dd57806320ea8d8614630000046d578063ee22610b1463000004fa575b341563000000fc5734600052337fe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c60206000a25b005b600080fd5b60005260206000f35b3463000000fe576000546300000103565b3463000000fe576001546300000103565b3463000000fe5760043573ffffffffffffffffffffffffffffffffffffffff1660005260036020526040600020546300000103565b3463000000fe57602061010052600254806101205260005b8181101563000001a757806000526004602052604060002054816020026101400152600101630000017b565b50602002604001610100f35b3463000000fe5760043560005260243573ffffffffffffffffffffffffffffffffffffffff1660205260066040526060600020546300000103565b3463000000fe57600435608052600060c0526300000220565b3463000000fe57600435608052600160c0526300000220565b600060e05260025460005b81811015630000026957806000526004602052604060002054602052608051600052600660405260606000205460e0510160e052600101630000022b565b505060c05180156300000286576001146300000290576300000564565b60e0516300000103565b60005460e05110156300000103565b3463000000fe576004356000526005602052604060002080546101005280600101546101205260806101405280600201546101605280600301548061018052601f016020900460005b81811015630000030c5780830160040154816020026101a0015260010163000002e8565b5060200260a001610100f35b3463000000fe5760043573ffffffffffffffffffffffffffffffffffffffff16801563000000fe576001548060010160015580608052600052600560205260406000208060a0525560243560a0516001015560443560040180358060a05160030155601f016020900460005b8181101563000003ab57806020028301602001358160a05101600401556001016300000384565b5050506080517fc0ba8fe4b176c1714197d43b9cc6bcf797a4a7461c5fe8d0ef6e184ae7601e51600080a26300000407565b3463000000fe57600435806080526000526005602052604060002060a05260a051541563000000fe575b3360005260036020526040600020541563000000fe576080516000523360205260066040526060600020805463000000fe5760019055608051337f4a504a94899432a9846e1aa406dceb1bcfd538bb839071d49d1e5e23f5be30ef600080a3630000054b565b3463000000fe57600435806080526000526005602052604060002060a0523360005260036020526040600020541563000000fe5760a0516002015463000000fe57608051600052336020526006604052606060002080541563000000fe5760009055608051337ff6a317157440607f36269043eb55f1287a5a19ba2216afeab88cd46cbcfb88e9600080a3005b3463000000fe57600435806080526000526005602052604060002060a0523360005260036020526040600020541563000000fe576080516000523360205260066040526060600020541563000000fe575b60a0516002015463000000fe57600260c0526300000220565b60005460e05110630000063057600160a0516002015560a0516003015480601f016020900460005b8181101563000005b2578060a0510160040154816020026101000152600101630000058c565b5050600060008261010060a0516001015460a051545af1630000060757600060a051600201556080517f526441bb6c1aba3c9a4a6ca1d6545da9c2333c8c48343ef398eb858d72b79236600080a26300000630565b6080517f33e13ecb54c3076d8e8bb8c2881800a4d972b792045ffae98fdf46df365fed75600080a25b608051630000010356`
+
+// DeployMultiSig deploys a new Ethereum contract, binding an instance of MultiSig to it.
+func DeployMultiSig(auth *bind.TransactOpts, backend bind.ContractBackend, _owners []common.Address, _required *big.Int) (common.Address, *types.Transaction, *MultiSig, error) {
+	parsed, err := abi.JSON(strings.NewReader(MultiSigABI))
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	address, tx, contract, err := bind.DeployContract(auth, parsed, common.FromHex(MultiSigBin), backend, _owners, _required)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &MultiSig{MultiSigCaller: MultiSigCaller{contract: contract}, MultiSigTransactor: MultiSigTransactor{contract: contract}, MultiSigFilterer: MultiSigFilterer{contract: contract}}, nil
+}
+
+// MultiSig is an auto generated Go binding around an Ethereum contract.
+type MultiSig struct {
+	MultiSigCaller     // Read-only binding to the contract
+	MultiSigTransactor // Write-only binding to the contract
+	MultiSigFilterer   // Log filterer for contract events
+}
+
+// MultiSigCaller is an auto generated read-only Go binding around an Ethereum contract.
+type MultiSigCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSigTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type MultiSigTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSigFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type MultiSigFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSigSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type MultiSigSession struct {
+	Contract     *MultiSig         // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// MultiSigCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type MultiSigCallerSession struct {
+	Contract *MultiSigCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts   // Call options to use throughout this session
+}
+
+// MultiSigTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type MultiSigTransactorSession struct {
+	Contract     *MultiSigTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts   // Transaction auth options to use throughout this session
+}
+
+// MultiSigRaw is an auto generated low-level Go binding around an Ethereum contract.
+type MultiSigRaw struct {
+	Contract *MultiSig // Generic contract binding to access the raw methods on
+}
+
+// MultiSigCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type MultiSigCallerRaw struct {
+	Contract *MultiSigCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// MultiSigTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type MultiSigTransactorRaw struct {
+	Contract *MultiSigTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewMultiSig creates a new instance of MultiSig, bound to a specific deployed contract.
+func NewMultiSig(address common.Address, backend bind.ContractBackend) (*MultiSig, error) {
+	contract, err := bindMultiSig(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSig{MultiSigCaller: MultiSigCaller{contract: contract}, MultiSigTransactor: MultiSigTransactor{contract: contract}, MultiSigFilterer: MultiSigFilterer{contract: contract}}, nil
+}
+
+// NewMultiSigCaller creates a new read-only instance of MultiSig, bound to a specific deployed contract.
+func NewMultiSigCaller(address common.Address, caller bind.ContractCaller) (*MultiSigCaller, error) {
+	contract, err := bindMultiSig(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigCaller{contract: contract}, nil
+}
+
+// NewMultiSigTransactor creates a new write-only instance of MultiSig, bound to a specific deployed contract.
+func NewMultiSigTransactor(address common.Address, transactor bind.ContractTransactor) (*MultiSigTransactor, error) {
+	contract, err := bindMultiSig(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigTransactor{contract: contract}, nil
+}
+
+// NewMultiSigFilterer creates a new log filterer instance of MultiSig, bound to a specific deployed contract.
+func NewMultiSigFilterer(address common.Address, filterer bind.ContractFilterer) (*MultiSigFilterer, error) {
+	contract, err := bindMultiSig(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigFilterer{contract: contract}, nil
+}
+
+// bindMultiSig binds a generic wrapper to an already deployed contract.
+func bindMultiSig(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(MultiSigABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MultiSig *MultiSigRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _MultiSig.Contract.MultiSigCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MultiSig *MultiSigRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MultiSig.Contract.MultiSigTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MultiSig *MultiSigRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MultiSig.Contract.MultiSigTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MultiSig *MultiSigCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _MultiSig.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MultiSig *MultiSigTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MultiSig.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MultiSig *MultiSigTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MultiSig.Contract.contract.Transact(opts, method, params...)
+}
+
+// Confirmations is a free data retrieval call binding the contract method 0x3411c81c.
+//
+// Solidity: function confirmations( uint256,  address) constant returns(bool)
+func (_MultiSig *MultiSigCaller) Confirmations(opts *bind.CallOpts, arg0 *big.Int, arg1 common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "confirmations", arg0, arg1)
+	return *ret0, err
+}
+
+// Confirmations is a free data retrieval call binding the contract method 0x3411c81c.
+//
+// Solidity: function confirmations( uint256,  address) constant returns(bool)
+func (_MultiSig *MultiSigSession) Confirmations(arg0 *big.Int, arg1 common.Address) (bool, error) {
+	return _MultiSig.Contract.Confirmations(&_MultiSig.CallOpts, arg0, arg1)
+}
+
+// Confirmations is a free data retrieval call binding the contract method 0x3411c81c.
+//
+// Solidity: function confirmations( uint256,  address) constant returns(bool)
+func (_MultiSig *MultiSigCallerSession) Confirmations(arg0 *big.Int, arg1 common.Address) (bool, error) {
+	return _MultiSig.Contract.Confirmations(&_MultiSig.CallOpts, arg0, arg1)
+}
+
+// GetConfirmationCount is a free data retrieval call binding the contract method 0x8b51d13f.
+//
+// Solidity: function getConfirmationCount(transactionId uint256) constant returns(count uint256)
+func (_MultiSig *MultiSigCaller) GetConfirmationCount(opts *bind.CallOpts, transactionId *big.Int) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "getConfirmationCount", transactionId)
+	return *ret0, err
+}
+
+// GetConfirmationCount is a free data retrieval call binding the contract method 0x8b51d13f.
+//
+// Solidity: function getConfirmationCount(transactionId uint256) constant returns(count uint256)
+func (_MultiSig *MultiSigSession) GetConfirmationCount(transactionId *big.Int) (*big.Int, error) {
+	return _MultiSig.Contract.GetConfirmationCount(&_MultiSig.CallOpts, transactionId)
+}
+
+// GetConfirmationCount is a free data retrieval call binding the contract method 0x8b51d13f.
+//
+// Solidity: function getConfirmationCount(transactionId uint256) constant returns(count uint256)
+func (_MultiSig *MultiSigCallerSession) GetConfirmationCount(transactionId *big.Int) (*big.Int, error) {
+	return _MultiSig.Contract.GetConfirmationCount(&_MultiSig.CallOpts, transactionId)
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() constant returns(address[])
+func (_MultiSig *MultiSigCaller) GetOwners(opts *bind.CallOpts) ([]common.Address, error) {
+	var (
+		ret0 = new([]common.Address)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "getOwners")
+	return *ret0, err
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() constant returns(address[])
+func (_MultiSig *MultiSigSession) GetOwners() ([]common.Address, error) {
+	return _MultiSig.Contract.GetOwners(&_MultiSig.CallOpts)
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() constant returns(address[])
+func (_MultiSig *MultiSigCallerSession) GetOwners() ([]common.Address, error) {
+	return _MultiSig.Contract.GetOwners(&_MultiSig.CallOpts)
+}
+
+// IsConfirmed is a free data retrieval call binding the contract method 0x784547a7.
+//
+// Solidity: function isConfirmed(transactionId uint256) constant returns(bool)
+func (_MultiSig *MultiSigCaller) IsConfirmed(opts *bind.CallOpts, transactionId *big.Int) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "isConfirmed", transactionId)
+	return *ret0, err
+}
+
+// IsConfirmed is a free data retrieval call binding the contract method 0x784547a7.
+//
+// Solidity: function isConfirmed(transactionId uint256) constant returns(bool)
+func (_MultiSig *MultiSigSession) IsConfirmed(transactionId *big.Int) (bool, error) {
+	return _MultiSig.Contract.IsConfirmed(&_MultiSig.CallOpts, transactionId)
+}
+
+// IsConfirmed is a free data retrieval call binding the contract method 0x784547a7.
+//
+// Solidity: function isConfirmed(transactionId uint256) constant returns(bool)
+func (_MultiSig *MultiSigCallerSession) IsConfirmed(transactionId *big.Int) (bool, error) {
+	return _MultiSig.Contract.IsConfirmed(&_MultiSig.CallOpts, transactionId)
+}
+
+// IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
+//
+// Solidity: function isOwner( address) constant returns(bool)
+func (_MultiSig *MultiSigCaller) IsOwner(opts *bind.CallOpts, arg0 common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "isOwner", arg0)
+	return *ret0, err
+}
+
+// IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
+//
+// Solidity: function isOwner( address) constant returns(bool)
+func (_MultiSig *MultiSigSession) IsOwner(arg0 common.Address) (bool, error) {
+	return _MultiSig.Contract.IsOwner(&_MultiSig.CallOpts, arg0)
+}
+
+// IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
+//
+// Solidity: function isOwner( address) constant returns(bool)
+func (_MultiSig *MultiSigCallerSession) IsOwner(arg0 common.Address) (bool, error) {
+	return _MultiSig.Contract.IsOwner(&_MultiSig.CallOpts, arg0)
+}
+
+// Required is a free data retrieval call binding the contract method 0xdc8452cd.
+//
+// Solidity: function required() constant returns(uint256)
+func (_MultiSig *MultiSigCaller) Required(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "required")
+	return *ret0, err
+}
+
+// Required is a free data retrieval call binding the contract method 0xdc8452cd.
+//
+// Solidity: function required() constant returns(uint256)
+func (_MultiSig *MultiSigSession) Required() (*big.Int, error) {
+	return _MultiSig.Contract.Required(&_MultiSig.CallOpts)
+}
+
+// Required is a free data retrieval call binding the contract method 0xdc8452cd.
+//
+// Solidity: function required() constant returns(uint256)
+func (_MultiSig *MultiSigCallerSession) Required() (*big.Int, error) {
+	return _MultiSig.Contract.Required(&_MultiSig.CallOpts)
+}
+
+// TransactionCount is a free data retrieval call binding the contract method 0xb77bf600.
+//
+// Solidity: function transactionCount() constant returns(uint256)
+func (_MultiSig *MultiSigCaller) TransactionCount(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSig.contract.Call(opts, out, "transactionCount")
+	return *ret0, err
+}
+
+// TransactionCount is a free data retrieval call binding the contract method 0xb77bf600.
+//
+// Solidity: function transactionCount() constant returns(uint256)
+func (_MultiSig *MultiSigSession) TransactionCount() (*big.Int, error) {
+	return _MultiSig.Contract.TransactionCount(&_MultiSig.CallOpts)
+}
+
+// TransactionCount is a free data retrieval call binding the contract method 0xb77bf600.
+//
+// Solidity: function transactionCount() constant returns(uint256)
+func (_MultiSig *MultiSigCallerSession) TransactionCount() (*big.Int, error) {
+	return _MultiSig.Contract.TransactionCount(&_MultiSig.CallOpts)
+}
+
+// Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
+//
+// Solidity: function transactions( uint256) constant returns(destination address, value uint256, data bytes, executed bool)
+func (_MultiSig *MultiSigCaller) Transactions(opts *bind.CallOpts, arg0 *big.Int) (struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+	Executed    bool
+}, error) {
+	ret := new(struct {
+		Destination common.Address
+		Value       *big.Int
+		Data        []byte
+		Executed    bool
+	})
+	out := ret
+	err := _MultiSig.contract.Call(opts, out, "transactions", arg0)
+	return *ret, err
+}
+
+// Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
+//
+// Solidity: function transactions( uint256) constant returns(destination address, value uint256, data bytes, executed bool)
+func (_MultiSig *MultiSigSession) Transactions(arg0 *big.Int) (struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+	Executed    bool
+}, error) {
+	return _MultiSig.Contract.Transactions(&_MultiSig.CallOpts, arg0)
+}
+
+// Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
+//
+// Solidity: function transactions( uint256) constant returns(destination address, value uint256, data bytes, executed bool)
+func (_MultiSig *MultiSigCallerSession) Transactions(arg0 *big.Int) (struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+	Executed    bool
+}, error) {
+	return _MultiSig.Contract.Transactions(&_MultiSig.CallOpts, arg0)
+}
+
+// ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
+//
+// Solidity: function confirmTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactor) ConfirmTransaction(opts *bind.TransactOpts, transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "confirmTransaction", transactionId)
+}
+
+// ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
+//
+// Solidity: function confirmTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigSession) ConfirmTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ConfirmTransaction(&_MultiSig.TransactOpts, transactionId)
+}
+
+// ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
+//
+// Solidity: function confirmTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactorSession) ConfirmTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ConfirmTransaction(&_MultiSig.TransactOpts, transactionId)
+}
+
+// ExecuteTransaction is a paid mutator transaction binding the contract method 0xee22610b.
+//
+// Solidity: function executeTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactor) ExecuteTransaction(opts *bind.TransactOpts, transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "executeTransaction", transactionId)
+}
+
+// ExecuteTransaction is a paid mutator transaction binding the contract method 0xee22610b.
+//
+// Solidity: function executeTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigSession) ExecuteTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ExecuteTransaction(&_MultiSig.TransactOpts, transactionId)
+}
+
+// ExecuteTransaction is a paid mutator transaction binding the contract method 0xee22610b.
+//
+// Solidity: function executeTransaction(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactorSession) ExecuteTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.ExecuteTransaction(&_MultiSig.TransactOpts, transactionId)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactor) RevokeConfirmation(opts *bind.TransactOpts, transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "revokeConfirmation", transactionId)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(transactionId uint256) returns()
+func (_MultiSig *MultiSigSession) RevokeConfirmation(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.RevokeConfirmation(&_MultiSig.TransactOpts, transactionId)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(transactionId uint256) returns()
+func (_MultiSig *MultiSigTransactorSession) RevokeConfirmation(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSig.Contract.RevokeConfirmation(&_MultiSig.TransactOpts, transactionId)
+}
+
+// SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
+//
+// Solidity: function submitTransaction(destination address, value uint256, data bytes) returns(transactionId uint256)
+func (_MultiSig *MultiSigTransactor) SubmitTransaction(opts *bind.TransactOpts, destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return _MultiSig.contract.Transact(opts, "submitTransaction", destination, value, data)
+}
+
+// SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
+//
+// Solidity: function submitTransaction(destination address, value uint256, data bytes) returns(transactionId uint256)
+func (_MultiSig *MultiSigSession) SubmitTransaction(destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return _MultiSig.Contract.SubmitTransaction(&_MultiSig.TransactOpts, destination, value, data)
+}
+
+// SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
+//
+// Solidity: function submitTransaction(destination address, value uint256, data bytes) returns(transactionId uint256)
+func (_MultiSig *MultiSigTransactorSession) SubmitTransaction(destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return _MultiSig.Contract.SubmitTransaction(&_MultiSig.TransactOpts, destination, value, data)
+}
+
+// MultiSigConfirmationIterator is returned from FilterConfirmation and is used to iterate over the raw logs and unpacked data for Confirmation events raised by the MultiSig contract.
+type MultiSigConfirmationIterator struct {
+	Event *MultiSigConfirmation // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log     // Log channel receiving the found contract events
+	sub  event.Subscription // Subscription for errors, completion and termination
+	done bool               // Whether the subscription completed delivering logs
+	fail error              // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigConfirmationIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigConfirmation)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigConfirmation)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigConfirmationIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigConfirmationIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigConfirmation represents a Confirmation event raised by the MultiSig contract.
+type MultiSigConfirmation struct {
+	Sender        common.Address
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterConfirmation is a free log retrieval operation binding the contract event 0x4a504a94899432a9846e1aa406dceb1bcfd538bb839071d49d1e5e23f5be30ef.
+//
+// Solidity: event Confirmation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterConfirmation(opts *bind.FilterOpts, sender []common.Address, transactionId []*big.Int) (*MultiSigConfirmationIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Confirmation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigConfirmationIterator{contract: _MultiSig.contract, event: "Confirmation", logs: logs, sub: sub}, nil
+}
+
+// WatchConfirmation is a free log subscription operation binding the contract event 0x4a504a94899432a9846e1aa406dceb1bcfd538bb839071d49d1e5e23f5be30ef.
+//
+// Solidity: event Confirmation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchConfirmation(opts *bind.WatchOpts, sink chan<- *MultiSigConfirmation, sender []common.Address, transactionId []*big.Int) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Confirmation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigConfirmation)
+				if err := _MultiSig.contract.UnpackLog(event, "Confirmation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigDepositIterator is returned from FilterDeposit and is used to iterate over the raw logs and unpacked data for Deposit events raised by the MultiSig contract.
+type MultiSigDepositIterator struct {
+	Event *MultiSigDeposit // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log     // Log channel receiving the found contract events
+	sub  event.Subscription // Subscription for errors, completion and termination
+	done bool               // Whether the subscription completed delivering logs
+	fail error              // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigDepositIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigDeposit)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigDeposit)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigDepositIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigDepositIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigDeposit represents a Deposit event raised by the MultiSig contract.
+type MultiSigDeposit struct {
+	Sender common.Address
+	Value  *big.Int
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterDeposit is a free log retrieval operation binding the contract event 0xe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c.
+//
+// Solidity: event Deposit(sender indexed address, value uint256)
+func (_MultiSig *MultiSigFilterer) FilterDeposit(opts *bind.FilterOpts, sender []common.Address) (*MultiSigDepositIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Deposit", senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigDepositIterator{contract: _MultiSig.contract, event: "Deposit", logs: logs, sub: sub}, nil
+}
+
+// WatchDeposit is a free log subscription operation binding the contract event 0xe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c.
+//
+// Solidity: event Deposit(sender indexed address, value uint256)
+func (_MultiSig *MultiSigFilterer) WatchDeposit(opts *bind.WatchOpts, sink chan<- *MultiSigDeposit, sender []common.Address) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Deposit", senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigDeposit)
+				if err := _MultiSig.contract.UnpackLog(event, "Deposit", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigExecutionIterator is returned from FilterExecution and is used to iterate over the raw logs and unpacked data for Execution events raised by the MultiSig contract.
+type MultiSigExecutionIterator struct {
+	Event *MultiSigExecution // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log     // Log channel receiving the found contract events
+	sub  event.Subscription // Subscription for errors, completion and termination
+	done bool               // Whether the subscription completed delivering logs
+	fail error              // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigExecutionIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigExecution)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigExecution)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigExecutionIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigExecutionIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigExecution represents a Execution event raised by the MultiSig contract.
+type MultiSigExecution struct {
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterExecution is a free log retrieval operation binding the contract event 0x33e13ecb54c3076d8e8bb8c2881800a4d972b792045ffae98fdf46df365fed75.
+//
+// Solidity: event Execution(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterExecution(opts *bind.FilterOpts, transactionId []*big.Int) (*MultiSigExecutionIterator, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Execution", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigExecutionIterator{contract: _MultiSig.contract, event: "Execution", logs: logs, sub: sub}, nil
+}
+
+// WatchExecution is a free log subscription operation binding the contract event 0x33e13ecb54c3076d8e8bb8c2881800a4d972b792045ffae98fdf46df365fed75.
+//
+// Solidity: event Execution(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchExecution(opts *bind.WatchOpts, sink chan<- *MultiSigExecution, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Execution", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigExecution)
+				if err := _MultiSig.contract.UnpackLog(event, "Execution", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigExecutionFailureIterator is returned from FilterExecutionFailure and is used to iterate over the raw logs and unpacked data for ExecutionFailure events raised by the MultiSig contract.
+type MultiSigExecutionFailureIterator struct {
+	Event *MultiSigExecutionFailure // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log     // Log channel receiving the found contract events
+	sub  event.Subscription // Subscription for errors, completion and termination
+	done bool               // Whether the subscription completed delivering logs
+	fail error              // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigExecutionFailureIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigExecutionFailure)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigExecutionFailure)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigExecutionFailureIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigExecutionFailureIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigExecutionFailure represents a ExecutionFailure event raised by the MultiSig contract.
+type MultiSigExecutionFailure struct {
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterExecutionFailure is a free log retrieval operation binding the contract event 0x526441bb6c1aba3c9a4a6ca1d6545da9c2333c8c48343ef398eb858d72b79236.
+//
+// Solidity: event ExecutionFailure(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterExecutionFailure(opts *bind.FilterOpts, transactionId []*big.Int) (*MultiSigExecutionFailureIterator, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "ExecutionFailure", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigExecutionFailureIterator{contract: _MultiSig.contract, event: "ExecutionFailure", logs: logs, sub: sub}, nil
+}
+
+// WatchExecutionFailure is a free log subscription operation binding the contract event 0x526441bb6c1aba3c9a4a6ca1d6545da9c2333c8c48343ef398eb858d72b79236.
+//
+// Solidity: event ExecutionFailure(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchExecutionFailure(opts *bind.WatchOpts, sink chan<- *MultiSigExecutionFailure, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "ExecutionFailure", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigExecutionFailure)
+				if err := _MultiSig.contract.UnpackLog(event, "ExecutionFailure", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigRevocationIterator is returned from FilterRevocation and is used to iterate over the raw logs and unpacked data for Revocation events raised by the MultiSig contract.
+type MultiSigRevocationIterator struct {
+	Event *MultiSigRevocation // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log     // Log channel receiving the found contract events
+	sub  event.Subscription // Subscription for errors, completion and termination
+	done bool               // Whether the subscription completed delivering logs
+	fail error              // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigRevocationIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigRevocation)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigRevocation)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigRevocationIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigRevocationIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigRevocation represents a Revocation event raised by the MultiSig contract.
+type MultiSigRevocation struct {
+	Sender        common.Address
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterRevocation is a free log retrieval operation binding the contract event 0xf6a317157440607f36269043eb55f1287a5a19ba2216afeab88cd46cbcfb88e9.
+//
+// Solidity: event Revocation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterRevocation(opts *bind.FilterOpts, sender []common.Address, transactionId []*big.Int) (*MultiSigRevocationIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Revocation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigRevocationIterator{contract: _MultiSig.contract, event: "Revocation", logs: logs, sub: sub}, nil
+}
+
+// WatchRevocation is a free log subscription operation binding the contract event 0xf6a317157440607f36269043eb55f1287a5a19ba2216afeab88cd46cbcfb88e9.
+//
+// Solidity: event Revocation(sender indexed address, transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchRevocation(opts *bind.WatchOpts, sink chan<- *MultiSigRevocation, sender []common.Address, transactionId []*big.Int) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Revocation", senderRule, transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigRevocation)
+				if err := _MultiSig.contract.UnpackLog(event, "Revocation", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MultiSigSubmissionIterator is returned from FilterSubmission and is used to iterate over the raw logs and unpacked data for Submission events raised by the MultiSig contract.
+type MultiSigSubmissionIterator struct {
+	Event *MultiSigSubmission // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log     // Log channel receiving the found contract events
+	sub  event.Subscription // Subscription for errors, completion and termination
+	done bool               // Whether the subscription completed delivering logs
+	fail error              // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MultiSigSubmissionIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MultiSigSubmission)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MultiSigSubmission)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MultiSigSubmissionIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MultiSigSubmissionIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MultiSigSubmission represents a Submission event raised by the MultiSig contract.
+type MultiSigSubmission struct {
+	TransactionId *big.Int
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterSubmission is a free log retrieval operation binding the contract event 0xc0ba8fe4b176c1714197d43b9cc6bcf797a4a7461c5fe8d0ef6e184ae7601e51.
+//
+// Solidity: event Submission(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) FilterSubmission(opts *bind.FilterOpts, transactionId []*big.Int) (*MultiSigSubmissionIterator, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.FilterLogs(opts, "Submission", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigSubmissionIterator{contract: _MultiSig.contract, event: "Submission", logs: logs, sub: sub}, nil
+}
+
+// WatchSubmission is a free log subscription operation binding the contract event 0xc0ba8fe4b176c1714197d43b9cc6bcf797a4a7461c5fe8d0ef6e184ae7601e51.
+//
+// Solidity: event Submission(transactionId indexed uint256)
+func (_MultiSig *MultiSigFilterer) WatchSubmission(opts *bind.WatchOpts, sink chan<- *MultiSigSubmission, transactionId []*big.Int) (event.Subscription, error) {
+
+	var transactionIdRule []interface{}
+	for _, transactionIdItem := range transactionId {
+		transactionIdRule = append(transactionIdRule, transactionIdItem)
+	}
+
+	logs, sub, err := _MultiSig.contract.WatchLogs(opts, "Submission", transactionIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MultiSigSubmission)
+				if err := _MultiSig.contract.UnpackLog(event, "Submission", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build none
+
+// This program generates contract/multisig.bin, the deployment bytecode of the
+// multisig wallet, by assembling the runtime code in contract/multisig.evm and
+// prefixing it with the constructor in contract/deploy.evm.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/asm"
+)
+
+// assemble compiles an EVM assembly source into hex encoded bytecode.
+func assemble(name string, source string) string {
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex(name, []byte(source), false))
+
+	bin, errs := compiler.Compile()
+	if len(errs) > 0 {
+		panic(fmt.Sprintf("%s: %v", name, errs))
+	}
+	return bin
+}
+
+func main() {
+	runtime, err := ioutil.ReadFile("contract/multisig.evm")
+	if err != nil {
+		panic(err)
+	}
+	deploy, err := ioutil.ReadFile("contract/deploy.evm")
+	if err != nil {
+		panic(err)
+	}
+	code := assemble("multisig.evm", string(runtime))
+
+	// The constructor needs its own length to locate the runtime code and the
+	// arguments. Both offsets are pushed as two byte values, so the constructor
+	// length doesn't depend on them: assemble it with placeholders first.
+	link := func(size, offset int) string {
+		if size < 0x100 || offset >= 0x10000 {
+			panic(fmt.Sprintf("runtime size %d or end %d not encodable in two bytes", size, offset))
+		}
+		source := strings.Replace(string(deploy), "RUNTIME_SIZE", fmt.Sprintf("%#x", size), -1)
+		source = strings.Replace(source, "ARGS_OFFSET", fmt.Sprintf("%#x", offset), -1)
+		return assemble("deploy.evm", source)
+	}
+	size := len(code) / 2
+	constructor := link(size, 0x1000)
+	constructor = link(size, len(constructor)/2+size)
+
+	if err := ioutil.WriteFile("contract/multisig.bin", []byte("0x"+constructor+code+"\n"), 0644); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package multisig wraps the multi-signature wallet contract, a subset of the
+// Gnosis MultiSigWallet where a fixed set of owners propose transactions that
+// are executed once enough of them confirmed.
+//
+// The package also implements an accounts backend representing such contracts
+// as wallets: signing a transaction with one submits it as a proposal, or
+// confirms an identical outstanding one, through an owner account.
+package multisig
+
+//go:generate go run ./gencode.go
+//go:generate abigen --abi contract/multisig.abi --bin contract/multisig.bin --pkg contract --type MultiSig --out contract/multisig.go
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/multisig/contract"
+)
+
+// MultiSig is a multi-signature wallet contract, transacting with the given
+// owner account.
+type MultiSig struct {
+	*contract.MultiSigSession
+	contractBackend bind.ContractBackend
+}
+
+// Transaction is a transaction proposed to the multisig wallet, along with the
+// owners who confirmed it.
+type Transaction struct {
+	ID            uint64
+	To            common.Address
+	Value         *big.Int
+	Data          []byte
+	Executed      bool
+	Confirmations []common.Address
+}
+
+// NewMultiSig creates a struct exposing convenient high-level operations for
+// interacting with a multisig wallet deployed at the given address.
+func NewMultiSig(transactOpts *bind.TransactOpts, contractAddr common.Address, contractBackend bind.ContractBackend) (*MultiSig, error) {
+	multisig, err := contract.NewMultiSig(contractAddr, contractBackend)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSig{
+		&contract.MultiSigSession{
+			Contract:     multisig,
+			TransactOpts: *transactOpts,
+		},
+		contractBackend,
+	}, nil
+}
+
+// DeployMultiSig deploys a new multisig wallet managed by the given owners,
+// requiring the given number of them to confirm each transaction.
+func DeployMultiSig(transactOpts *bind.TransactOpts, contractBackend bind.ContractBackend, owners []common.Address, required uint64) (common.Address, *MultiSig, error) {
+	addr, _, _, err := contract.DeployMultiSig(transactOpts, contractBackend, owners, new(big.Int).SetUint64(required))
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	multisig, err := NewMultiSig(transactOpts, addr, contractBackend)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return addr, multisig, nil
+}
+
+// Transaction retrieves a proposed transaction by its id, along with the owners
+// who confirmed it.
+func (self *MultiSig) Transaction(id uint64) (*Transaction, error) {
+	return loadTransaction(&self.Contract.MultiSigCaller, &self.CallOpts, id)
+}
+
+// loadTransaction retrieves a proposed transaction by its id from a multisig
+// wallet, along with the owners who confirmed it.
+func loadTransaction(caller *contract.MultiSigCaller, opts *bind.CallOpts, id uint64) (*Transaction, error) {
+	number := new(big.Int).SetUint64(id)
+
+	tx, err := caller.Transactions(opts, number)
+	if err != nil {
+		return nil, err
+	}
+	owners, err := caller.GetOwners(opts)
+	if err != nil {
+		return nil, err
+	}
+	confirmations := make([]common.Address, 0, len(owners))
+	for _, owner := range owners {
+		confirmed, err := caller.Confirmations(opts, number, owner)
+		if err != nil {
+			return nil, err
+		}
+		if confirmed {
+			confirmations = append(confirmations, owner)
+		}
+	}
+	return &Transaction{
+		ID:            id,
+		To:            tx.Destination,
+		Value:         tx.Value,
+		Data:          tx.Data,
+		Executed:      tx.Executed,
+		Confirmations: confirmations,
+	}, nil
+}
@@ -0,0 +1,297 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/multisig/contract"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	key0, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	key1, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	key2, _ = crypto.HexToECDSA("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+	addr0   = crypto.PubkeyToAddress(key0.PublicKey)
+	addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+	addr2   = crypto.PubkeyToAddress(key2.PublicKey)
+
+	ether = big.NewInt(1000000000000000000)
+)
+
+// newTestMultiSig deploys a 2-of-3 multisig wallet and returns its address
+// along with a handle for each owner.
+func newTestMultiSig(t *testing.T) (*backends.SimulatedBackend, common.Address, []*MultiSig) {
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		addr0: {Balance: new(big.Int).Mul(ether, big.NewInt(10))},
+		addr1: {Balance: new(big.Int).Mul(ether, big.NewInt(10))},
+		addr2: {Balance: new(big.Int).Mul(ether, big.NewInt(10))},
+	})
+	addr, _, err := DeployMultiSig(bind.NewKeyedTransactor(key0), backend, []common.Address{addr0, addr1, addr2}, 2)
+	if err != nil {
+		t.Fatalf("failed to deploy multisig: %v", err)
+	}
+	backend.Commit()
+
+	var owners []*MultiSig
+	for _, key := range []*ecdsa.PrivateKey{key0, key1, key2} {
+		multisig, err := NewMultiSig(bind.NewKeyedTransactor(key), addr, backend)
+		if err != nil {
+			t.Fatalf("failed to bind multisig: %v", err)
+		}
+		owners = append(owners, multisig)
+	}
+	return backend, addr, owners
+}
+
+// checkReceipt ensures that the given transaction got included and whether its
+// execution succeeded.
+func checkReceipt(t *testing.T, backend *backends.SimulatedBackend, tx *types.Transaction, success bool) *types.Receipt {
+	receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatalf("failed to retrieve receipt: %v", err)
+	}
+	if have := receipt.Status == types.ReceiptStatusSuccessful; have != success {
+		t.Fatalf("transaction success mismatch: have %v, want %v", have, success)
+	}
+	return receipt
+}
+
+// fund deposits an ether into the multisig wallet through its fallback function.
+func fund(t *testing.T, backend *backends.SimulatedBackend, addr common.Address) {
+	nonce, err := backend.PendingNonceAt(context.Background(), addr0)
+	if err != nil {
+		t.Fatalf("failed to retrieve nonce: %v", err)
+	}
+	tx, _ := types.SignTx(types.NewTransaction(nonce, addr, ether, 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key0)
+	if err := backend.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("failed to fund multisig: %v", err)
+	}
+	backend.Commit()
+
+	receipt := checkReceipt(t, backend, tx, true)
+	if have, want := receipt.Logs[0].Topics[0], crypto.Keccak256Hash([]byte("Deposit(address,uint256)")); have != want {
+		t.Fatalf("deposit event mismatch: have %x, want %x", have, want)
+	}
+}
+
+// Tests that the constructor rejects invalid owner sets and requirements.
+func TestDeployValidation(t *testing.T) {
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{addr0: {Balance: ether}})
+	auth := bind.NewKeyedTransactor(key0)
+	auth.GasLimit = 3000000
+
+	tests := []struct {
+		owners   []common.Address
+		required uint64
+		success  bool
+	}{
+		{[]common.Address{addr0, addr1}, 2, true},
+		{[]common.Address{addr0, addr1}, 0, false},
+		{[]common.Address{addr0, addr1}, 3, false},
+		{[]common.Address{}, 0, false},
+		{[]common.Address{addr0, addr0}, 1, false},
+		{[]common.Address{addr0, {}}, 1, false},
+	}
+	for i, tt := range tests {
+		_, tx, _, err := contract.DeployMultiSig(auth, backend, tt.owners, new(big.Int).SetUint64(tt.required))
+		if err != nil {
+			t.Fatalf("test %d: failed to deploy multisig: %v", i, err)
+		}
+		backend.Commit()
+
+		receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+		if err != nil {
+			t.Fatalf("test %d: failed to retrieve receipt: %v", i, err)
+		}
+		if have := receipt.Status == types.ReceiptStatusSuccessful; have != tt.success {
+			t.Errorf("test %d: deployment success mismatch: have %v, want %v", i, have, tt.success)
+		}
+	}
+}
+
+// Tests that the views of the contract report the deployed configuration.
+func TestViews(t *testing.T) {
+	_, _, owners := newTestMultiSig(t)
+
+	if required, err := owners[0].Required(); err != nil || required.Uint64() != 2 {
+		t.Errorf("requirement mismatch: have %v (%v), want %d", required, err, 2)
+	}
+	if have, err := owners[0].GetOwners(); err != nil || !reflect.DeepEqual(have, []common.Address{addr0, addr1, addr2}) {
+		t.Errorf("owners mismatch: have %x (%v), want %x", have, err, []common.Address{addr0, addr1, addr2})
+	}
+	for _, addr := range []common.Address{addr0, addr1, addr2} {
+		if owner, err := owners[0].IsOwner(addr); err != nil || !owner {
+			t.Errorf("owner %x not reported: %v", addr, err)
+		}
+	}
+	if owner, err := owners[0].IsOwner(common.Address{1}); err != nil || owner {
+		t.Errorf("non-owner reported as owner: %v", err)
+	}
+	if count, err := owners[0].TransactionCount(); err != nil || count.Sign() != 0 {
+		t.Errorf("transaction count mismatch: have %v (%v), want 0", count, err)
+	}
+}
+
+// Tests the lifecycle of a proposed transaction: submission, confirmation,
+// revocation and execution with a value transfer.
+func TestTransactionLifecycle(t *testing.T) {
+	backend, addr, owners := newTestMultiSig(t)
+
+	// Fund the wallet and submit a transfer with some payload, that should only be
+	// confirmed by the sender
+	fund(t, backend, addr)
+	recipient := common.Address{0xaa}
+	payload := bytes.Repeat([]byte{0x01, 0x02, 0x03}, 15)
+
+	tx, err := owners[0].SubmitTransaction(recipient, big.NewInt(1000), payload)
+	if err != nil {
+		t.Fatalf("failed to submit transaction: %v", err)
+	}
+	backend.Commit()
+	checkReceipt(t, backend, tx, true)
+
+	proposal, err := owners[0].Transaction(0)
+	if err != nil {
+		t.Fatalf("failed to retrieve transaction: %v", err)
+	}
+	want := &Transaction{ID: 0, To: recipient, Value: big.NewInt(1000), Data: payload, Confirmations: []common.Address{addr0}}
+	if !reflect.DeepEqual(proposal, want) {
+		t.Fatalf("transaction mismatch: have %+v, want %+v", proposal, want)
+	}
+	if confirmed, err := owners[0].IsConfirmed(big.NewInt(0)); err != nil || confirmed {
+		t.Fatalf("transaction confirmed with a single owner: %v", err)
+	}
+	// Non-owners and double confirmations must be rejected
+	stranger, _ := NewMultiSig(bind.NewKeyedTransactor(key0), addr, backend)
+	stranger.TransactOpts.From = common.Address{0xff}
+
+	if _, err := stranger.ConfirmTransaction(big.NewInt(0)); err == nil {
+		t.Fatalf("non-owner confirmed transaction")
+	}
+	if _, err := owners[0].ConfirmTransaction(big.NewInt(0)); err == nil {
+		t.Fatalf("owner confirmed transaction twice")
+	}
+	if _, err := owners[0].ConfirmTransaction(big.NewInt(1)); err == nil {
+		t.Fatalf("owner confirmed non-existent transaction")
+	}
+	// Revoke and re-confirm, neither should execute the transaction
+	if tx, err = owners[0].RevokeConfirmation(big.NewInt(0)); err != nil {
+		t.Fatalf("failed to revoke confirmation: %v", err)
+	}
+	backend.Commit()
+	checkReceipt(t, backend, tx, true)
+
+	if count, err := owners[0].GetConfirmationCount(big.NewInt(0)); err != nil || count.Sign() != 0 {
+		t.Fatalf("confirmation count mismatch: have %v (%v), want 0", count, err)
+	}
+	if tx, err = owners[2].ConfirmTransaction(big.NewInt(0)); err != nil {
+		t.Fatalf("failed to confirm transaction: %v", err)
+	}
+	backend.Commit()
+	checkReceipt(t, backend, tx, true)
+
+	if balance, _ := backend.BalanceAt(context.Background(), recipient, nil); balance.Sign() != 0 {
+		t.Fatalf("transaction executed prematurely")
+	}
+	// Confirm by a second owner, which should execute the transfer
+	if tx, err = owners[1].ConfirmTransaction(big.NewInt(0)); err != nil {
+		t.Fatalf("failed to confirm transaction: %v", err)
+	}
+	backend.Commit()
+	receipt := checkReceipt(t, backend, tx, true)
+
+	if balance, _ := backend.BalanceAt(context.Background(), recipient, nil); balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("recipient balance mismatch: have %v, want %v", balance, 1000)
+	}
+	if have := len(receipt.Logs); have != 2 {
+		t.Fatalf("log count mismatch: have %d, want %d", have, 2)
+	}
+	if have, want := receipt.Logs[1].Topics[0], crypto.Keccak256Hash([]byte("Execution(uint256)")); have != want {
+		t.Fatalf("execution event mismatch: have %x, want %x", have, want)
+	}
+	if confirmed, err := owners[0].IsConfirmed(big.NewInt(0)); err != nil || !confirmed {
+		t.Fatalf("executed transaction not confirmed: %v", err)
+	}
+	proposal, _ = owners[0].Transaction(0)
+	if !proposal.Executed || !reflect.DeepEqual(proposal.Confirmations, []common.Address{addr1, addr2}) {
+		t.Fatalf("executed transaction mismatch: have %+v", proposal)
+	}
+	// Executed transactions can neither be revoked, nor executed again
+	if _, err := owners[1].RevokeConfirmation(big.NewInt(0)); err == nil {
+		t.Fatalf("owner revoked executed transaction")
+	}
+	if _, err := owners[0].ConfirmTransaction(big.NewInt(0)); err == nil {
+		t.Fatalf("owner confirmed executed transaction")
+	}
+}
+
+// Tests that failing transactions are marked as such, and can be retried once
+// the cause of the failure is gone.
+func TestExecutionFailure(t *testing.T) {
+	backend, addr, owners := newTestMultiSig(t)
+
+	// Propose a transfer exceeding the balance of the wallet
+	recipient := common.Address{0xbb}
+	if _, err := owners[0].SubmitTransaction(recipient, ether, nil); err != nil {
+		t.Fatalf("failed to submit transaction: %v", err)
+	}
+	backend.Commit()
+
+	tx, err := owners[1].ConfirmTransaction(big.NewInt(0))
+	if err != nil {
+		t.Fatalf("failed to confirm transaction: %v", err)
+	}
+	backend.Commit()
+	receipt := checkReceipt(t, backend, tx, true)
+
+	if have, want := receipt.Logs[len(receipt.Logs)-1].Topics[0], crypto.Keccak256Hash([]byte("ExecutionFailure(uint256)")); have != want {
+		t.Fatalf("failure event mismatch: have %x, want %x", have, want)
+	}
+	if proposal, _ := owners[0].Transaction(0); proposal.Executed {
+		t.Fatalf("failed transaction marked executed")
+	}
+	// Fund the wallet and retry the execution
+	fund(t, backend, addr)
+
+	if _, err := owners[2].ExecuteTransaction(big.NewInt(0)); err == nil {
+		t.Fatalf("non-confirming owner executed the transaction")
+	}
+	if tx, err = owners[1].ExecuteTransaction(big.NewInt(0)); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	backend.Commit()
+	checkReceipt(t, backend, tx, true)
+
+	if balance, _ := backend.BalanceAt(context.Background(), recipient, nil); balance.Cmp(ether) != 0 {
+		t.Fatalf("recipient balance mismatch: have %v, want %v", balance, ether)
+	}
+	if proposal, _ := owners[0].Transaction(0); !proposal.Executed {
+		t.Fatalf("retried transaction not marked executed")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package multisig
+
+import (
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/les"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Service is a node service registering a multisig wallet backend with the
+// account manager of the node, signing with the other wallets of the manager,
+// and exposing the backend over RPC.
+type Service struct {
+	backend *Backend
+}
+
+// NewService creates a multisig wallet service on top of the Ethereum service of
+// the node, which may be either a full or a light client.
+func NewService(ctx *node.ServiceContext) (node.Service, error) {
+	// Retrieve the Ethereum service dependency to access the blockchain
+	var client *eth.ContractBackend
+	var ethereum *eth.Ethereum
+	if err := ctx.Service(&ethereum); err == nil {
+		client = eth.NewFilterContractBackend(ethereum.ApiBackend, ethereum.ApiBackend, false)
+	} else {
+		var ethereum *les.LightEthereum
+		if err := ctx.Service(&ethereum); err == nil {
+			client = eth.NewFilterContractBackend(ethereum.ApiBackend, ethereum.ApiBackend, true)
+		} else {
+			return nil, err
+		}
+	}
+	backend := NewBackend(client, ctx.AccountManager)
+	ctx.AccountManager.AddBackend(backend)
+
+	return &Service{backend: backend}, nil
+}
+
+// Protocols returns an empty list of P2P protocols as the multisig service does
+// not have a networking component.
+func (s *Service) Protocols() []p2p.Protocol { return nil }
+
+// APIs returns the RPC APIs exposing the multisig wallets.
+func (s *Service) APIs() []rpc.API { return s.backend.APIs() }
+
+// Start implements node.Service, but the wallets are only tracked on request.
+func (s *Service) Start(server *p2p.Server) error { return nil }
+
+// Stop closes all the open multisig wallets, terminating their event trackers.
+func (s *Service) Stop() error {
+	for _, wallet := range s.backend.Wallets() {
+		wallet.Close()
+	}
+	return nil
+}
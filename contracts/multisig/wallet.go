@@ -0,0 +1,473 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/multisig/contract"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrContractCreation is returned if a multisig wallet is requested to sign a
+// contract creation, which the contract cannot propose.
+var ErrContractCreation = errors.New("contract creation not supported")
+
+// multisigABI is the parsed ABI of the contract, used to pack the proposals.
+var multisigABI abi.ABI
+
+func init() {
+	var err error
+	if multisigABI, err = abi.JSON(strings.NewReader(contract.MultiSigABI)); err != nil {
+		panic(err)
+	}
+}
+
+// wallet represents a multisig wallet contract, signing the proposals through
+// one of its owners. While open, it tracks the outstanding proposals of the
+// contract via its events.
+type wallet struct {
+	backend  *Backend           // Backend that tracks this wallet
+	url      accounts.URL       // Textual URL uniquely identifying this wallet
+	address  common.Address     // Address of the multisig wallet contract
+	owner    accounts.Account   // Owner account signing on behalf of the wallet
+	contract *contract.MultiSig // Bindings to interact with the contract
+	log      log.Logger         // Contextual logger to tag the wallet with its address
+
+	pending   map[uint64]*Transaction // Outstanding proposals of the contract, nil if closed
+	trackQuit chan chan error         // Quit channel of the event tracker, nil if closed
+	trackErr  error                   // Failure that stopped the event tracker, if any
+
+	stateLock sync.RWMutex // Protects the internals of the wallet from racey access
+}
+
+// newWallet creates a closed multisig wallet for the given contract.
+func newWallet(backend *Backend, address common.Address, owner accounts.Account, multisig *contract.MultiSig) *wallet {
+	return &wallet{
+		backend:  backend,
+		url:      accounts.URL{Scheme: Scheme, Path: address.Hex()},
+		address:  address,
+		owner:    owner,
+		contract: multisig,
+		log:      log.New("url", accounts.URL{Scheme: Scheme, Path: address.Hex()}),
+	}
+}
+
+// URL implements accounts.Wallet, returning the URL of the multisig wallet.
+func (w *wallet) URL() accounts.URL {
+	return w.url
+}
+
+// Status implements accounts.Wallet, returning whether the outstanding proposals
+// are being tracked.
+func (w *wallet) Status() (string, error) {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	if w.trackErr != nil {
+		return "Failed", w.trackErr
+	}
+	if w.pending == nil {
+		return "Closed", nil
+	}
+	return fmt.Sprintf("Owner %x, %d pending proposals", w.owner.Address, len(w.pending)), nil
+}
+
+// Open implements accounts.Wallet, starting to track the outstanding proposals
+// of the contract. The passphrase is not needed, as the owner account signing
+// on behalf of the wallet is unlocked independently.
+func (w *wallet) Open(passphrase string) error {
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	if w.pending != nil {
+		return accounts.ErrWalletAlreadyOpen
+	}
+	// Subscribe to the contract events before retrieving the past ones, so
+	// nothing happening in between gets lost
+	var (
+		submissions = make(chan *contract.MultiSigSubmission)
+		confirms    = make(chan *contract.MultiSigConfirmation)
+		revokes     = make(chan *contract.MultiSigRevocation)
+		executions  = make(chan *contract.MultiSigExecution)
+		failures    = make(chan *contract.MultiSigExecutionFailure)
+
+		subs []event.Subscription
+	)
+	watch := func(sub event.Subscription, err error) error {
+		if err == nil {
+			subs = append(subs, sub)
+		}
+		return err
+	}
+	opts := &bind.WatchOpts{Context: context.Background()}
+	if err := watch(w.contract.WatchSubmission(opts, submissions, nil)); err != nil {
+		return w.unsubscribe(subs, err)
+	}
+	if err := watch(w.contract.WatchConfirmation(opts, confirms, nil, nil)); err != nil {
+		return w.unsubscribe(subs, err)
+	}
+	if err := watch(w.contract.WatchRevocation(opts, revokes, nil, nil)); err != nil {
+		return w.unsubscribe(subs, err)
+	}
+	if err := watch(w.contract.WatchExecution(opts, executions, nil)); err != nil {
+		return w.unsubscribe(subs, err)
+	}
+	if err := watch(w.contract.WatchExecutionFailure(opts, failures, nil)); err != nil {
+		return w.unsubscribe(subs, err)
+	}
+	// Collect all the proposals that were submitted but not executed yet
+	pending, err := w.history()
+	if err != nil {
+		return w.unsubscribe(subs, err)
+	}
+	w.pending = pending
+	w.trackQuit = make(chan chan error)
+	w.trackErr = nil
+
+	go w.track(w.trackQuit, subs, submissions, confirms, revokes, executions, failures)
+
+	// Notify listeners asynchronously, as they may be calling into the wallet
+	go w.backend.updateFeed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletOpened})
+	return nil
+}
+
+// unsubscribe tears down the given event subscriptions, returning the error that
+// caused their abandonment.
+func (w *wallet) unsubscribe(subs []event.Subscription, err error) error {
+	for _, sub := range subs {
+		sub.Unsubscribe()
+	}
+	return err
+}
+
+// history retrieves the outstanding proposals of the contract, filtering out the
+// executed ones from all past submissions.
+func (w *wallet) history() (map[uint64]*Transaction, error) {
+	opts := &bind.FilterOpts{Context: context.Background()}
+
+	submissions, err := w.contract.FilterSubmission(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer submissions.Close()
+
+	ids := make(map[uint64]bool)
+	for submissions.Next() {
+		ids[submissions.Event.TransactionId.Uint64()] = true
+	}
+	if err := submissions.Error(); err != nil {
+		return nil, err
+	}
+	executions, err := w.contract.FilterExecution(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer executions.Close()
+
+	for executions.Next() {
+		delete(ids, executions.Event.TransactionId.Uint64())
+	}
+	if err := executions.Error(); err != nil {
+		return nil, err
+	}
+	// Load the details and confirmations of the remaining ones
+	pending := make(map[uint64]*Transaction)
+	for id := range ids {
+		tx, err := loadTransaction(&w.contract.MultiSigCaller, &bind.CallOpts{Context: context.Background()}, id)
+		if err != nil {
+			return nil, err
+		}
+		if !tx.Executed {
+			pending[id] = tx
+		}
+	}
+	return pending, nil
+}
+
+// track is the event loop updating the outstanding proposals of the contract
+// until the wallet is closed or an event subscription fails.
+func (w *wallet) track(quit chan chan error, subs []event.Subscription, submissions chan *contract.MultiSigSubmission, confirms chan *contract.MultiSigConfirmation,
+	revokes chan *contract.MultiSigRevocation, executions chan *contract.MultiSigExecution, failures chan *contract.MultiSigExecutionFailure) {
+
+	w.log.Debug("Multisig wallet tracking started")
+	defer w.log.Debug("Multisig wallet tracking stopped")
+
+	// Merge the subscription failures into a single channel
+	fails := make(chan error, len(subs))
+	for _, sub := range subs {
+		go func(sub event.Subscription) {
+			if err, ok := <-sub.Err(); ok {
+				fails <- err
+			}
+		}(sub)
+	}
+	defer w.unsubscribe(subs, nil)
+
+	// Update the proposals on every event until termination or error
+	var (
+		errc chan error
+		err  error
+	)
+	for errc == nil && err == nil {
+		select {
+		case errc = <-quit:
+			// Termination requested
+			continue
+		case err = <-fails:
+			// Event subscription failed
+			continue
+
+		case ev := <-submissions:
+			err = w.update(ev.TransactionId, ev.Raw)
+		case ev := <-confirms:
+			err = w.update(ev.TransactionId, ev.Raw)
+		case ev := <-revokes:
+			err = w.update(ev.TransactionId, ev.Raw)
+		case ev := <-executions:
+			err = w.update(ev.TransactionId, ev.Raw)
+		case ev := <-failures:
+			err = w.update(ev.TransactionId, ev.Raw)
+		}
+	}
+	// In case of error, wait for termination
+	if err != nil {
+		w.log.Warn("Multisig wallet tracking failed", "err", err)
+
+		w.stateLock.Lock()
+		w.trackErr = err
+		w.stateLock.Unlock()
+
+		errc = <-quit
+	}
+	errc <- err
+}
+
+// update refreshes an outstanding proposal after a contract event concerning it.
+func (w *wallet) update(id *big.Int, raw types.Log) error {
+	w.log.Trace("Multisig wallet event", "id", id, "block", raw.BlockNumber, "tx", raw.TxHash, "removed", raw.Removed)
+
+	// Successful executions are final, everything else needs reloading
+	var (
+		tx  *Transaction
+		err error
+	)
+	if raw.Topics[0] != multisigABI.Events["Execution"].Id() || raw.Removed {
+		if tx, err = loadTransaction(&w.contract.MultiSigCaller, &bind.CallOpts{Context: context.Background()}, id.Uint64()); err != nil {
+			return err
+		}
+	}
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	if w.pending == nil {
+		return nil // Closed while loading
+	}
+	if tx == nil || tx.Executed {
+		delete(w.pending, id.Uint64())
+	} else {
+		w.pending[id.Uint64()] = tx
+	}
+	return nil
+}
+
+// Close implements accounts.Wallet, stopping the tracking of the outstanding
+// proposals of the contract.
+func (w *wallet) Close() error {
+	// Claim the event tracker, so concurrent closes don't wait on it twice
+	w.stateLock.Lock()
+	quit := w.trackQuit
+	w.trackQuit = nil
+	w.stateLock.Unlock()
+
+	if quit == nil {
+		return nil
+	}
+	// Terminate the event tracker
+	errc := make(chan error)
+	quit <- errc
+	err := <-errc
+
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	w.pending = nil
+	w.trackErr = nil
+
+	return err
+}
+
+// Accounts implements accounts.Wallet, returning the contract address as the
+// single account of the wallet.
+func (w *wallet) Accounts() []accounts.Account {
+	return []accounts.Account{{Address: w.address, URL: w.url}}
+}
+
+// Contains implements accounts.Wallet, returning whether the account is the
+// multisig wallet contract.
+func (w *wallet) Contains(account accounts.Account) bool {
+	return account.Address == w.address && (account.URL == (accounts.URL{}) || account.URL == w.url)
+}
+
+// Derive implements accounts.Wallet, but is a noop for multisig wallets since
+// there is no notion of hierarchical account derivation for contracts.
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// SelfDerive implements accounts.Wallet, but is a noop for multisig wallets since
+// there is no notion of hierarchical account derivation for contracts.
+func (w *wallet) SelfDerive(base accounts.DerivationPath, chain ethereum.ChainStateReader) {}
+
+// SignHash implements accounts.Wallet, but contracts cannot sign hashes.
+func (w *wallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTypedData implements accounts.Wallet, but contracts cannot sign messages.
+func (w *wallet) SignTypedData(account accounts.Account, domainSeparator, messageHash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, but contracts cannot sign
+// hashes.
+func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTypedDataWithPassphrase implements accounts.Wallet, but contracts cannot
+// sign messages.
+func (w *wallet) SignTypedDataWithPassphrase(account accounts.Account, passphrase string, domainSeparator, messageHash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTx implements accounts.Wallet, proposing the transaction to the multisig
+// wallet. The returned transaction is a call to the contract signed by the owner
+// account: it confirms an identical outstanding proposal if there is one not
+// yet confirmed by the owner, or submits a new proposal otherwise.
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.signTx(account, tx, func(signer accounts.Wallet, call *types.Transaction) (*types.Transaction, error) {
+		return signer.SignTx(w.owner, call, chainID)
+	})
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, proposing the transaction to
+// the multisig wallet just like SignTx, but unlocking the owner account with the
+// given passphrase.
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.signTx(account, tx, func(signer accounts.Wallet, call *types.Transaction) (*types.Transaction, error) {
+		return signer.SignTxWithPassphrase(w.owner, passphrase, call, chainID)
+	})
+}
+
+// signTx wraps a transaction into a call proposing it to the contract and signs
+// that with the owner account via the given signing method.
+func (w *wallet) signTx(account accounts.Account, tx *types.Transaction, sign func(accounts.Wallet, *types.Transaction) (*types.Transaction, error)) (*types.Transaction, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	if tx.To() == nil {
+		return nil, ErrContractCreation
+	}
+	input, err := w.proposal(tx)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := w.backend.signers.Find(w.owner)
+	if err != nil {
+		return nil, err
+	}
+	// Assemble the contract call from the owner account
+	ctx := context.Background()
+
+	nonce, err := w.backend.client.PendingNonceAt(ctx, w.owner.Address)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := w.backend.client.EstimateGas(ctx, ethereum.CallMsg{From: w.owner.Address, To: &w.address, Data: input})
+	if err != nil {
+		return nil, err
+	}
+	return sign(signer, types.NewTransaction(nonce, w.address, new(big.Int), gas, tx.GasPrice(), input))
+}
+
+// proposal packs the contract call proposing the given transaction: confirming
+// an identical outstanding proposal if the owner didn't confirm it yet, or
+// submitting a new one.
+func (w *wallet) proposal(tx *types.Transaction) ([]byte, error) {
+	pending, err := w.proposals()
+	if err != nil {
+		return nil, err
+	}
+	for _, proposal := range pending {
+		if proposal.To != *tx.To() || proposal.Value.Cmp(tx.Value()) != 0 || !bytes.Equal(proposal.Data, tx.Data()) {
+			continue
+		}
+		confirmed := false
+		for _, owner := range proposal.Confirmations {
+			if owner == w.owner.Address {
+				confirmed = true
+				break
+			}
+		}
+		if !confirmed {
+			return multisigABI.Pack("confirmTransaction", new(big.Int).SetUint64(proposal.ID))
+		}
+	}
+	data := tx.Data()
+	if data == nil {
+		data = []byte{}
+	}
+	return multisigABI.Pack("submitTransaction", *tx.To(), tx.Value(), data)
+}
+
+// proposals returns the outstanding proposals of the contract, sorted by id.
+func (w *wallet) proposals() ([]*Transaction, error) {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	if w.pending == nil {
+		return nil, accounts.ErrWalletClosed
+	}
+	pending := make([]*Transaction, 0, len(w.pending))
+	for _, tx := range w.pending {
+		pending = append(pending, tx)
+	}
+	sort.Sort(transactionsByID(pending))
+	return pending, nil
+}
+
+// transactionsByID implements sort.Interface for []*Transaction by id.
+type transactionsByID []*Transaction
+
+func (t transactionsByID) Len() int           { return len(t) }
+func (t transactionsByID) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t transactionsByID) Less(i, j int) bool { return t[i].ID < t[j].ID }
@@ -0,0 +1,267 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// newTestSigners creates a keystore containing the given owner keys, unlocked.
+func newTestSigners(t *testing.T, keys ...*ecdsa.PrivateKey) (string, *accounts.Manager, []accounts.Account) {
+	dir, err := ioutil.TempDir("", "multisig-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewKeyStore(dir, 2, 1)
+
+	var owners []accounts.Account
+	for _, key := range keys {
+		account, err := ks.ImportECDSA(key, "")
+		if err != nil {
+			t.Fatalf("failed to import key: %v", err)
+		}
+		if err := ks.Unlock(account, ""); err != nil {
+			t.Fatalf("failed to unlock account: %v", err)
+		}
+		owners = append(owners, account)
+	}
+	return dir, accounts.NewManager(ks), owners
+}
+
+// waitProposals waits until the outstanding proposals of a multisig wallet match
+// the expected number, returning them.
+func waitProposals(t *testing.T, backend *Backend, addr common.Address, count int) []*Transaction {
+	for i := 0; ; i++ {
+		proposals, err := backend.Proposals(addr)
+		if err != nil {
+			t.Fatalf("failed to retrieve proposals: %v", err)
+		}
+		if len(proposals) == count {
+			return proposals
+		}
+		if i == 100 {
+			t.Fatalf("proposal count mismatch: have %d, want %d", len(proposals), count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// sendSigned signs a transaction with a multisig wallet and includes the call
+// to the contract in a block.
+func sendSigned(t *testing.T, client *backends.SimulatedBackend, wallet accounts.Wallet, tx *types.Transaction) *types.Transaction {
+	signed, err := wallet.SignTx(wallet.Accounts()[0], tx, nil)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if err := client.SendTransaction(context.Background(), signed); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	client.Commit()
+	checkReceipt(t, client, signed, true)
+
+	return signed
+}
+
+// Tests that multisig wallets can only be tracked with owner accounts.
+func TestWalletTracking(t *testing.T) {
+	client, addr, _ := newTestMultiSig(t)
+
+	dir, signers, owners := newTestSigners(t, key0)
+	defer os.RemoveAll(dir)
+
+	backend := NewBackend(client, signers)
+	if _, err := backend.Track(addr, accounts.Account{Address: common.Address{0xff}}); err != ErrNotOwner {
+		t.Fatalf("non-owner tracking error mismatch: have %v, want %v", err, ErrNotOwner)
+	}
+	events := make(chan accounts.WalletEvent, 2)
+	sub := backend.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	wallet, err := backend.Track(addr, owners[0])
+	if err != nil {
+		t.Fatalf("failed to track wallet: %v", err)
+	}
+	if _, err := backend.Track(addr, owners[0]); err != ErrWalletExists {
+		t.Fatalf("duplicate tracking error mismatch: have %v, want %v", err, ErrWalletExists)
+	}
+	if accs := wallet.Accounts(); len(accs) != 1 || accs[0].Address != addr || accs[0].URL.Scheme != Scheme {
+		t.Fatalf("accounts mismatch: have %v", accs)
+	}
+	if have := NewPublicMultisigAPI(backend).Wallets(); !reflect.DeepEqual(have, []common.Address{addr}) {
+		t.Fatalf("tracked wallets mismatch: have %x, want %x", have, []common.Address{addr})
+	}
+	if err := backend.Untrack(addr); err != nil {
+		t.Fatalf("failed to untrack wallet: %v", err)
+	}
+	if wallets := backend.Wallets(); len(wallets) != 0 {
+		t.Fatalf("untracked wallet still present: %v", wallets)
+	}
+	for _, kind := range []accounts.WalletEventType{accounts.WalletArrived, accounts.WalletDropped} {
+		if ev := <-events; ev.Kind != kind || ev.Wallet != wallet {
+			t.Errorf("wallet event mismatch: have %v, want %v", ev.Kind, kind)
+		}
+	}
+}
+
+// Tests that signing transactions with multisig wallets submits and confirms the
+// proposals through the owner accounts, tracking the outstanding ones.
+func TestWalletSigning(t *testing.T) {
+	client, addr, _ := newTestMultiSig(t)
+	fund(t, client, addr)
+
+	dir, signers, owners := newTestSigners(t, key0, key1)
+	defer os.RemoveAll(dir)
+
+	// Track the wallet from the point of view of two of its owners
+	backend0, backend1 := NewBackend(client, signers), NewBackend(client, signers)
+
+	wallet0, err := backend0.Track(addr, owners[0])
+	if err != nil {
+		t.Fatalf("failed to track wallet: %v", err)
+	}
+	wallet1, err := backend1.Track(addr, owners[1])
+	if err != nil {
+		t.Fatalf("failed to track wallet: %v", err)
+	}
+	recipient := common.Address{0xcc}
+	transfer := types.NewTransaction(0, recipient, big.NewInt(1000), 0, big.NewInt(1), []byte{0xde, 0xad})
+
+	if _, err := wallet0.SignTx(wallet0.Accounts()[0], transfer, nil); err != accounts.ErrWalletClosed {
+		t.Fatalf("closed signing error mismatch: have %v, want %v", err, accounts.ErrWalletClosed)
+	}
+	if _, err := wallet0.SignHash(wallet0.Accounts()[0], make([]byte, 32)); err != accounts.ErrNotSupported {
+		t.Fatalf("hash signing error mismatch: have %v, want %v", err, accounts.ErrNotSupported)
+	}
+	if err := wallet0.Open(""); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	defer wallet0.Close()
+
+	// Propose the transfer with the first owner and ensure it's tracked
+	signed := sendSigned(t, client, wallet0, transfer)
+	if sender, _ := types.Sender(types.HomesteadSigner{}, signed); sender != owners[0].Address {
+		t.Fatalf("proposal sender mismatch: have %x, want %x", sender, owners[0].Address)
+	}
+	if !bytes.Equal(signed.Data()[:4], multisigABI.Methods["submitTransaction"].Id()) {
+		t.Fatalf("proposal not a submission: %x", signed.Data())
+	}
+	proposals := waitProposals(t, backend0, addr, 1)
+
+	want := &Transaction{ID: 0, To: recipient, Value: big.NewInt(1000), Data: []byte{0xde, 0xad}, Confirmations: []common.Address{owners[0].Address}}
+	if !reflect.DeepEqual(proposals[0], want) {
+		t.Fatalf("proposal mismatch: have %+v, want %+v", proposals[0], want)
+	}
+	// Open the wallet of the second owner, which should find the proposal in
+	// the past events, and sign the same transfer to confirm and execute it
+	if err := wallet1.Open(""); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	defer wallet1.Close()
+
+	waitProposals(t, backend1, addr, 1)
+
+	signed = sendSigned(t, client, wallet1, transfer)
+	if sender, _ := types.Sender(types.HomesteadSigner{}, signed); sender != owners[1].Address {
+		t.Fatalf("confirmation sender mismatch: have %x, want %x", sender, owners[1].Address)
+	}
+	if !bytes.Equal(signed.Data()[:4], multisigABI.Methods["confirmTransaction"].Id()) {
+		t.Fatalf("proposal not a confirmation: %x", signed.Data())
+	}
+	waitProposals(t, backend0, addr, 0)
+	waitProposals(t, backend1, addr, 0)
+
+	if balance, _ := client.BalanceAt(context.Background(), recipient, nil); balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("recipient balance mismatch: have %v, want %v", balance, 1000)
+	}
+	// Signing the transfer again should propose a new one
+	signed = sendSigned(t, client, wallet1, transfer)
+	if !bytes.Equal(signed.Data()[:4], multisigABI.Methods["submitTransaction"].Id()) {
+		t.Fatalf("repeated proposal not a submission: %x", signed.Data())
+	}
+	if proposals := waitProposals(t, backend0, addr, 1); proposals[0].ID != 1 {
+		t.Fatalf("repeated proposal id mismatch: have %d, want %d", proposals[0].ID, 1)
+	}
+	if status, err := wallet0.Status(); err != nil || status != fmt.Sprintf("Owner %x, 1 pending proposals", owners[0].Address) {
+		t.Fatalf("status mismatch: have %q (%v)", status, err)
+	}
+	// Closing the wallet should stop tracking the proposals
+	wallet0.Close()
+	if _, err := backend0.Proposals(addr); err != accounts.ErrWalletClosed {
+		t.Fatalf("closed proposal listing error mismatch: have %v, want %v", err, accounts.ErrWalletClosed)
+	}
+}
+
+// Tests that wallets tracked by a backend registered with an account manager
+// show up there, and that they can be closed concurrently.
+func TestWalletConcurrentClose(t *testing.T) {
+	client, addr, _ := newTestMultiSig(t)
+
+	dir, signers, owners := newTestSigners(t, key0)
+	defer os.RemoveAll(dir)
+
+	backend := NewBackend(client, signers)
+	signers.AddBackend(backend)
+
+	wallet, err := backend.Track(addr, owners[0])
+	if err != nil {
+		t.Fatalf("failed to track wallet: %v", err)
+	}
+	if err := wallet.Open(""); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	for i := 0; ; i++ {
+		if found, err := signers.Find(wallet.Accounts()[0]); err == nil && found == wallet {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("tracked wallet not found by the account manager")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	done := make(chan error)
+	for i := 0; i < 4; i++ {
+		go func() { done <- wallet.Close() }()
+	}
+	for i := 0; i < 4; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("failed to close wallet: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("concurrent close %d timed out", i)
+		}
+	}
+	if status, _ := wallet.Status(); status != "Closed" {
+		t.Fatalf("status mismatch: have %q, want %q", status, "Closed")
+	}
+}
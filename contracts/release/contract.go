@@ -118,7 +118,7 @@ func bindReleaseOracle(address common.Address, caller bind.ContractCaller, trans
 	if err != nil {
 		return nil, err
 	}
-	return bind.NewBoundContract(address, parsed, caller, transactor), nil
+	return bind.NewBoundContract(address, parsed, caller, transactor, nil), nil
 }
 
 // Call invokes the (constant) contract method with params as input values and
@@ -57,12 +57,15 @@ var (
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
 	lookupPrefix        = []byte("l") // lookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix     = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	addrIndexPrefix     = []byte("A") // addrIndexPrefix + address + section (uint64 big endian) + hash -> address transactions
+	addrGapsPrefix      = []byte("g") // addrGapsPrefix + section (uint64 big endian) + hash -> blocks missing internal calls
 
 	preimagePrefix = "secure-key-"              // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	AddressIndexPrefix   = []byte("iA") // AddressIndexPrefix is the data table of the address indexer to track its progress
 
 	// used by old db, now only used for conversion
 	oldReceiptsPrefix = []byte("receipts-")
@@ -341,6 +344,32 @@ func GetBloomBits(db DatabaseReader, bit uint, section uint64, head common.Hash)
 	return db.Get(key)
 }
 
+// GetAddressIndex retrieves the encoded list of transactions touching the given
+// address within the given section.
+func GetAddressIndex(db DatabaseReader, addr common.Address, section uint64, head common.Hash) ([]byte, error) {
+	return db.Get(addressIndexKey(addr, section, head))
+}
+
+// addressIndexKey = addrIndexPrefix + address + section (uint64 big endian) + hash
+func addressIndexKey(addr common.Address, section uint64, head common.Hash) []byte {
+	key := make([]byte, 0, len(addrIndexPrefix)+common.AddressLength+8+common.HashLength)
+	key = append(append(key, addrIndexPrefix...), addr.Bytes()...)
+	return append(append(key, encodeBlockNumber(section)...), head.Bytes()...)
+}
+
+// GetAddressIndexGaps retrieves the encoded list of blocks within the given
+// section whose internal calls are missing from the address index.
+func GetAddressIndexGaps(db DatabaseReader, section uint64, head common.Hash) ([]byte, error) {
+	return db.Get(addressGapsKey(section, head))
+}
+
+// addressGapsKey = addrGapsPrefix + section (uint64 big endian) + hash
+func addressGapsKey(section uint64, head common.Hash) []byte {
+	key := make([]byte, 0, len(addrGapsPrefix)+8+common.HashLength)
+	key = append(key, addrGapsPrefix...)
+	return append(append(key, encodeBlockNumber(section)...), head.Bytes()...)
+}
+
 // WriteCanonicalHash stores the canonical hash for the given block number.
 func WriteCanonicalHash(db ethdb.Putter, hash common.Hash, number uint64) error {
 	key := append(append(headerPrefix, encodeBlockNumber(number)...), numSuffix...)
@@ -493,6 +522,23 @@ func WriteBloomBits(db ethdb.Putter, bit uint, section uint64, head common.Hash,
 	}
 }
 
+// WriteAddressIndex writes the encoded list of transactions touching the given
+// address within the given section.
+func WriteAddressIndex(db ethdb.Putter, addr common.Address, section uint64, head common.Hash, entries []byte) {
+	key := addressIndexKey(addr, section, head)
+	if err := db.Put(key, entries); err != nil {
+		log.Crit("Failed to store address index", "err", err)
+	}
+}
+
+// WriteAddressIndexGaps writes the encoded list of blocks within the given
+// section whose internal calls are missing from the address index.
+func WriteAddressIndexGaps(db ethdb.Putter, section uint64, head common.Hash, gaps []byte) {
+	if err := db.Put(addressGapsKey(section, head), gaps); err != nil {
+		log.Crit("Failed to store address index gaps", "err", err)
+	}
+}
+
 // DeleteCanonicalHash removes the number to hash canonical mapping.
 func DeleteCanonicalHash(db DatabaseDeleter, number uint64) {
 	db.Delete(append(append(headerPrefix, encodeBlockNumber(number)...), numSuffix...))
@@ -0,0 +1,147 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+// checksumLength is the number of Keccak256 hash bytes appended to the binary
+// form of a share to detect corruption.
+const checksumLength = 4
+
+var (
+	// ErrInvalidChecksum is returned if a serialized share fails its checksum,
+	// i.e. it was corrupted or mistyped.
+	ErrInvalidChecksum = errors.New("invalid share checksum")
+
+	// ErrInvalidMnemonic is returned if a share mnemonic has the wrong number of
+	// words for the length it declares.
+	ErrInvalidMnemonic = errors.New("invalid share mnemonic")
+)
+
+// wordIndex maps the words of the mnemonic wordlist to their index.
+var wordIndex = make(map[string]int, len(wordlists.English))
+
+func init() {
+	for i, word := range wordlists.English {
+		wordIndex[word] = i
+	}
+}
+
+// Bytes serializes a share into its binary form: the threshold, the index, the
+// data and a checksum of all of them.
+func (s *Share) Bytes() []byte {
+	blob := make([]byte, 2+len(s.Data), 2+len(s.Data)+checksumLength)
+	blob[0], blob[1] = s.Threshold, s.Index
+	copy(blob[2:], s.Data)
+
+	return append(blob, crypto.Keccak256(blob)[:checksumLength]...)
+}
+
+// ParseShare deserializes the binary form of a share, verifying its checksum.
+func ParseShare(blob []byte) (*Share, error) {
+	if len(blob) <= 2+checksumLength {
+		return nil, fmt.Errorf("share too short: %d bytes", len(blob))
+	}
+	payload, checksum := blob[:len(blob)-checksumLength], blob[len(blob)-checksumLength:]
+	if !bytes.Equal(crypto.Keccak256(payload)[:checksumLength], checksum) {
+		return nil, ErrInvalidChecksum
+	}
+	share := &Share{
+		Threshold: payload[0],
+		Index:     payload[1],
+		Data:      common.CopyBytes(payload[2:]),
+	}
+	if share.Threshold == 0 || share.Index == 0 {
+		return nil, ErrShareMismatch
+	}
+	return share, nil
+}
+
+// Mnemonic encodes the binary form of a share into words of the BIP-39 English
+// wordlist. The first word encodes the length of the binary form in bytes, each
+// following one 11 bits of it, the last word being padded with zero bits.
+//
+// Since the length needs to fit into the first word, only shares of secrets up
+// to 2041 bytes can be encoded as mnemonics.
+func (s *Share) Mnemonic() (string, error) {
+	blob := s.Bytes()
+	if len(blob) >= len(wordlists.English) {
+		return "", fmt.Errorf("share too long for mnemonic: %d bytes", len(blob))
+	}
+	words := []string{wordlists.English[len(blob)]}
+	var (
+		acc  uint32 // Bit accumulator of the not yet encoded bits
+		bits uint   // Number of bits in the accumulator
+	)
+	for _, b := range blob {
+		acc, bits = acc<<8|uint32(b), bits+8
+		for bits >= 11 {
+			bits -= 11
+			words = append(words, wordlists.English[(acc>>bits)&0x7ff])
+		}
+	}
+	if bits > 0 {
+		words = append(words, wordlists.English[(acc<<(11-bits))&0x7ff])
+	}
+	return strings.Join(words, " "), nil
+}
+
+// ParseMnemonic decodes a share from its mnemonic form, verifying its checksum.
+// Words are matched case insensitively and may be separated by any whitespace.
+func ParseMnemonic(mnemonic string) (*Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) == 0 {
+		return nil, ErrInvalidMnemonic
+	}
+	indices := make([]int, len(words))
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("unknown mnemonic word %q", word)
+		}
+		indices[i] = index
+	}
+	length := indices[0]
+	if len(indices)-1 != (length*8+10)/11 {
+		return nil, ErrInvalidMnemonic
+	}
+	var (
+		blob = make([]byte, 0, length)
+		acc  uint32
+		bits uint
+	)
+	for _, index := range indices[1:] {
+		acc, bits = acc<<11|uint32(index), bits+11
+		for bits >= 8 && len(blob) < length {
+			bits -= 8
+			blob = append(blob, byte(acc>>bits))
+		}
+	}
+	if acc&(1<<bits-1) != 0 {
+		return nil, ErrInvalidMnemonic
+	}
+	return ParseShare(blob)
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package shamir implements Shamir's secret sharing over GF(2^8), splitting a
+// secret into shares out of which any threshold number reconstructs it, while
+// fewer reveal nothing about it.
+//
+// Shares can be serialized into a checksummed binary form or into a mnemonic of
+// words from the BIP-39 English wordlist, suitable for writing down on paper.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+)
+
+// MaxShares is the maximum number of shares a secret can be split into, limited
+// by the number of distinct non-zero elements of the field.
+const MaxShares = 255
+
+var (
+	// ErrInvalidThreshold is returned if a secret is split with a threshold that
+	// is zero or larger than the number of shares requested.
+	ErrInvalidThreshold = errors.New("invalid share threshold")
+
+	// ErrInvalidShareCount is returned if a secret is split into more shares than
+	// the field can accommodate.
+	ErrInvalidShareCount = errors.New("invalid share count")
+
+	// ErrEmptySecret is returned if an empty secret is split.
+	ErrEmptySecret = errors.New("empty secret")
+
+	// ErrNotEnoughShares is returned if fewer shares are combined than required
+	// by their threshold.
+	ErrNotEnoughShares = errors.New("not enough shares")
+
+	// ErrShareMismatch is returned if shares are combined which were not split
+	// from the same secret, as far as can be told from their parameters.
+	ErrShareMismatch = errors.New("share parameters mismatch")
+
+	// ErrDuplicateShare is returned if the same share is combined twice.
+	ErrDuplicateShare = errors.New("duplicate share")
+)
+
+// Share is one piece of a split secret.
+type Share struct {
+	Threshold byte   // Number of shares needed to reconstruct the secret
+	Index     byte   // Non-zero point the sharing polynomials are evaluated at
+	Data      []byte // Evaluations of the sharing polynomials, one per secret byte
+}
+
+// Split divides a secret into the given number of shares, any threshold number
+// of which suffice to reconstruct the secret.
+//
+// Each byte of the secret is the constant term of a random polynomial of degree
+// threshold-1, the shares being the evaluations of these polynomials at the
+// points 1..shares.
+func Split(secret []byte, shares, threshold int) ([]*Share, error) {
+	if len(secret) == 0 {
+		return nil, ErrEmptySecret
+	}
+	if shares < 1 || shares > MaxShares {
+		return nil, ErrInvalidShareCount
+	}
+	if threshold < 1 || threshold > shares {
+		return nil, ErrInvalidThreshold
+	}
+	result := make([]*Share, shares)
+	for i := range result {
+		result[i] = &Share{
+			Threshold: byte(threshold),
+			Index:     byte(i + 1),
+			Data:      make([]byte, len(secret)),
+		}
+	}
+	coeffs := make([]byte, threshold)
+	for i, b := range secret {
+		coeffs[0] = b
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, err
+		}
+		for _, share := range result {
+			share.Data[i] = evaluate(coeffs, share.Index)
+		}
+	}
+	for i := range coeffs {
+		coeffs[i] = 0
+	}
+	return result, nil
+}
+
+// Combine reconstructs a secret from the given shares, of which at least as many
+// are needed as their threshold. Only the number of shares required is used.
+//
+// Note, combining shares of different secrets that happen to have the same
+// parameters cannot be detected and results in garbage.
+func Combine(shares []*Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+	threshold, length := int(shares[0].Threshold), len(shares[0].Data)
+	if threshold == 0 || length == 0 {
+		return nil, ErrShareMismatch
+	}
+	seen := make(map[byte]bool)
+	for _, share := range shares {
+		if int(share.Threshold) != threshold || len(share.Data) != length || share.Index == 0 {
+			return nil, ErrShareMismatch
+		}
+		if seen[share.Index] {
+			return nil, ErrDuplicateShare
+		}
+		seen[share.Index] = true
+	}
+	if len(shares) < threshold {
+		return nil, ErrNotEnoughShares
+	}
+	shares = shares[:threshold]
+
+	// Interpolate the polynomials at zero using the Lagrange basis of the points
+	secret := make([]byte, length)
+	for i, share := range shares {
+		basis := byte(1)
+		for j, other := range shares {
+			if i != j {
+				basis = mul(basis, div(other.Index, other.Index^share.Index))
+			}
+		}
+		for k, y := range share.Data {
+			secret[k] ^= mul(y, basis)
+		}
+	}
+	return secret, nil
+}
+
+// evaluate computes the value of a polynomial at the given point using Horner's
+// method, the coefficients being in ascending order of degree.
+func evaluate(coeffs []byte, x byte) byte {
+	var y byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coeffs[i]
+	}
+	return y
+}
+
+// mul multiplies two elements of GF(2^8) modulo the AES polynomial
+// x^8 + x^4 + x^3 + x + 1, without any data dependent branches or lookups.
+func mul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		a = (a << 1) ^ (0x1b & -(a >> 7))
+		b >>= 1
+	}
+	return p
+}
+
+// div divides two elements of GF(2^8), b being non-zero. The inverse of b is
+// computed as b^254, since b^255 = 1 for all non-zero elements.
+func div(a, b byte) byte {
+	inv := b
+	for i := 0; i < 6; i++ {
+		inv = mul(mul(inv, inv), b)
+	}
+	return mul(a, mul(inv, inv))
+}
@@ -0,0 +1,154 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package shamir
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var testSecret = common.Hex2Bytes("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+
+// Tests the field arithmetic against known values and its own inverse.
+func TestFieldArithmetic(t *testing.T) {
+	// Multiplication example from FIPS-197, section 4.2
+	if have := mul(0x57, 0x83); have != 0xc1 {
+		t.Fatalf("product mismatch: have %#x, want %#x", have, 0xc1)
+	}
+	for a := 0; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			if have := mul(div(byte(a), byte(b)), byte(b)); have != byte(a) {
+				t.Fatalf("%#x / %#x * %#x = %#x", a, b, b, have)
+			}
+		}
+	}
+}
+
+// Tests that any threshold sized subset of the shares reconstructs the secret,
+// while smaller ones don't.
+func TestSplitCombine(t *testing.T) {
+	shares, err := Split(testSecret, 5, 3)
+	if err != nil {
+		t.Fatalf("failed to split secret: %v", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("share count mismatch: have %d, want %d", len(shares), 5)
+	}
+	for i := 0; i < len(shares); i++ {
+		for j := i + 1; j < len(shares); j++ {
+			for k := j + 1; k < len(shares); k++ {
+				secret, err := Combine([]*Share{shares[k], shares[i], shares[j]})
+				if err != nil {
+					t.Fatalf("failed to combine shares %d, %d, %d: %v", i, j, k, err)
+				}
+				if !bytes.Equal(secret, testSecret) {
+					t.Fatalf("secret mismatch for shares %d, %d, %d: have %x, want %x", i, j, k, secret, testSecret)
+				}
+			}
+			if _, err := Combine([]*Share{shares[i], shares[j]}); err != ErrNotEnoughShares {
+				t.Fatalf("short combination error mismatch: have %v, want %v", err, ErrNotEnoughShares)
+			}
+		}
+	}
+	if secret, err := Combine(shares); err != nil || !bytes.Equal(secret, testSecret) {
+		t.Fatalf("full combination mismatch: have %x (%v), want %x", secret, err, testSecret)
+	}
+}
+
+// Tests that invalid split parameters and share combinations are rejected.
+func TestInvalidParameters(t *testing.T) {
+	splits := []struct {
+		secret            []byte
+		shares, threshold int
+		err               error
+	}{
+		{nil, 3, 2, ErrEmptySecret},
+		{testSecret, 0, 0, ErrInvalidShareCount},
+		{testSecret, 256, 2, ErrInvalidShareCount},
+		{testSecret, 3, 0, ErrInvalidThreshold},
+		{testSecret, 3, 4, ErrInvalidThreshold},
+	}
+	for i, tt := range splits {
+		if _, err := Split(tt.secret, tt.shares, tt.threshold); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	shares, _ := Split(testSecret, 3, 2)
+	others, _ := Split(testSecret[:16], 3, 2)
+	relaxed, _ := Split(testSecret, 3, 1)
+
+	combines := []struct {
+		shares []*Share
+		err    error
+	}{
+		{nil, ErrNotEnoughShares},
+		{[]*Share{shares[0], shares[0]}, ErrDuplicateShare},
+		{[]*Share{shares[0], others[1]}, ErrShareMismatch},
+		{[]*Share{shares[0], relaxed[1]}, ErrShareMismatch},
+		{[]*Share{{Threshold: 2, Index: 0, Data: testSecret}, shares[1]}, ErrShareMismatch},
+	}
+	for i, tt := range combines {
+		if _, err := Combine(tt.shares); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// Tests that shares round trip through their binary and mnemonic forms, and that
+// corruptions are detected.
+func TestShareEncoding(t *testing.T) {
+	for _, length := range []int{1, 2, 16, 31, 32} {
+		shares, err := Split(testSecret[:length], 2, 2)
+		if err != nil {
+			t.Fatalf("length %d: failed to split secret: %v", length, err)
+		}
+		share := shares[1]
+
+		blob := share.Bytes()
+		if parsed, err := ParseShare(blob); err != nil || !reflect.DeepEqual(parsed, share) {
+			t.Fatalf("length %d: binary round trip mismatch: have %v (%v), want %v", length, parsed, err, share)
+		}
+		blob[2] ^= 0x01
+		if _, err := ParseShare(blob); err != ErrInvalidChecksum {
+			t.Fatalf("length %d: corrupt share error mismatch: have %v, want %v", length, err, ErrInvalidChecksum)
+		}
+		mnemonic, err := share.Mnemonic()
+		if err != nil {
+			t.Fatalf("length %d: failed to encode mnemonic: %v", length, err)
+		}
+		if parsed, err := ParseMnemonic("  " + strings.ToUpper(mnemonic) + "\n"); err != nil || !reflect.DeepEqual(parsed, share) {
+			t.Fatalf("length %d: mnemonic round trip mismatch: have %v (%v), want %v", length, parsed, err, share)
+		}
+		words := strings.Fields(mnemonic)
+		if _, err := ParseMnemonic(strings.Join(words[:len(words)-1], " ")); err != ErrInvalidMnemonic {
+			t.Fatalf("length %d: truncated mnemonic error mismatch: have %v, want %v", length, err, ErrInvalidMnemonic)
+		}
+		words[1], words[2] = words[2], words[1]
+		if words[1] != words[2] {
+			if _, err := ParseMnemonic(strings.Join(words, " ")); err != ErrInvalidChecksum {
+				t.Fatalf("length %d: swapped mnemonic error mismatch: have %v, want %v", length, err, ErrInvalidChecksum)
+			}
+		}
+	}
+	if _, err := ParseMnemonic("abandon notaword"); err == nil {
+		t.Fatalf("unknown word accepted")
+	}
+}
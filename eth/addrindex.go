@@ -0,0 +1,302 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// addrIndexSection is the number of blocks covered by a single section of
+	// the address index. It is kept small as the transactions of the blocks not
+	// yet indexed need to be scanned by the queries.
+	addrIndexSection = 256
+
+	// addrIndexConfirms is the number of confirmation blocks before an address
+	// index section is considered probably final and is generated.
+	addrIndexConfirms = 64
+
+	// addrIndexThrottling is the time to wait between processing two consecutive
+	// address index sections.
+	addrIndexThrottling = 100 * time.Millisecond
+)
+
+// Roles an address may have within an indexed transaction.
+const (
+	addrRoleSender    = 1 << iota // Address signed the transaction
+	addrRoleRecipient             // Address is the recipient or the created contract
+	addrRoleInternal              // Address was the target of an internal call
+)
+
+// addrIndexEntry is a transaction touching an indexed address.
+type addrIndexEntry struct {
+	Number uint64      // Number of the block containing the transaction
+	Index  uint        // Index of the transaction within the block
+	Hash   common.Hash // Hash of the transaction
+	Roles  uint8       // Bitmask of the roles of the address within the transaction
+}
+
+// AddressIndexer implements a core.ChainIndexer, building up an index of the
+// transactions sent from, sent to and optionally internally calling every
+// address of the canonical chain.
+//
+// Blocks whose internal calls can't be traced, e.g. as their state was pruned,
+// are recorded as gaps of their section, so queries can report them.
+type AddressIndexer struct {
+	db       ethdb.Database
+	chain    *core.BlockChain
+	internal bool // Whether to index the targets of internal calls too
+
+	section uint64                               // Section is the section number being processed currently
+	head    common.Hash                          // Head is the hash of the last header processed
+	entries map[common.Address][]*addrIndexEntry // Transactions touching the addresses of the section
+	gaps    []uint64                             // Blocks of the section with their internal calls missing
+	err     error                                // Failure while processing the current section
+}
+
+// NewAddressIndexer returns a chain indexer that maintains the index of the
+// transactions touching every address of the canonical chain. Indexing the
+// targets of internal calls requires the historical state of the chain.
+func NewAddressIndexer(db ethdb.Database, chain *core.BlockChain, size uint64, internal bool) *core.ChainIndexer {
+	backend := &AddressIndexer{
+		db:       db,
+		chain:    chain,
+		internal: internal,
+	}
+	table := ethdb.NewTable(db, string(core.AddressIndexPrefix))
+
+	return core.NewChainIndexer(db, table, backend, size, addrIndexConfirms, addrIndexThrottling, "addresses")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new address index
+// section.
+func (b *AddressIndexer) Reset(section uint64, lastSectionHead common.Hash) error {
+	b.section, b.head = section, common.Hash{}
+	b.entries, b.gaps, b.err = make(map[common.Address][]*addrIndexEntry), nil, nil
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the transactions of a new
+// block into the index.
+func (b *AddressIndexer) Process(header *types.Header) {
+	if b.err != nil {
+		return
+	}
+	hash, number := header.Hash(), header.Number.Uint64()
+
+	body := core.GetBody(b.db, hash, number)
+	if body == nil {
+		b.err = fmt.Errorf("block #%d [%x…] body missing", number, hash[:4])
+		return
+	}
+	block := types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles)
+
+	touched, missing, err := blockAddresses(b.db, b.chain, block, b.internal)
+	if err != nil {
+		b.err = err
+		return
+	}
+	if missing {
+		b.gaps = append(b.gaps, number)
+	}
+	for i, roles := range touched {
+		for addr, role := range roles {
+			b.entries[addr] = append(b.entries[addr], &addrIndexEntry{
+				Number: number,
+				Index:  uint(i),
+				Hash:   block.Transactions()[i].Hash(),
+				Roles:  role,
+			})
+		}
+	}
+	b.head = hash
+}
+
+// Commit implements core.ChainIndexerBackend, writing out the transactions of
+// every address touched within the section.
+func (b *AddressIndexer) Commit() error {
+	if b.err != nil {
+		return b.err
+	}
+	batch := b.db.NewBatch()
+	if len(b.gaps) > 0 {
+		log.Warn("Address index section missing internal calls", "section", b.section, "blocks", len(b.gaps))
+		blob, err := rlp.EncodeToBytes(b.gaps)
+		if err != nil {
+			return err
+		}
+		core.WriteAddressIndexGaps(batch, b.section, b.head, blob)
+	}
+	for addr, entries := range b.entries {
+		blob, err := rlp.EncodeToBytes(entries)
+		if err != nil {
+			return err
+		}
+		core.WriteAddressIndex(batch, addr, b.section, b.head, blob)
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch = b.db.NewBatch()
+		}
+	}
+	return batch.Write()
+}
+
+// readAddressIndex retrieves the indexed transactions touching an address
+// within the given section.
+func readAddressIndex(db ethdb.Database, addr common.Address, section uint64, head common.Hash) ([]*addrIndexEntry, error) {
+	blob, err := core.GetAddressIndex(db, addr, section, head)
+	if err != nil {
+		return nil, nil // No transactions within the section
+	}
+	var entries []*addrIndexEntry
+	if err := rlp.DecodeBytes(blob, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// readAddressIndexGaps retrieves the blocks within the given section whose
+// internal calls are missing from the index.
+func readAddressIndexGaps(db ethdb.Database, section uint64, head common.Hash) ([]uint64, error) {
+	blob, err := core.GetAddressIndexGaps(db, section, head)
+	if err != nil {
+		return nil, nil // No gaps within the section
+	}
+	var gaps []uint64
+	if err := rlp.DecodeBytes(blob, &gaps); err != nil {
+		return nil, err
+	}
+	return gaps, nil
+}
+
+// blockAddresses returns the addresses touched by every transaction of a block
+// along with their roles. If the internal calls were requested but couldn't be
+// traced, the block is reported as missing them.
+func blockAddresses(db ethdb.Database, chain *core.BlockChain, block *types.Block, internal bool) ([]map[common.Address]uint8, bool, error) {
+	var (
+		txs      = block.Transactions()
+		signer   = types.MakeSigner(chain.Config(), block.Number())
+		receipts types.Receipts
+		touched  = make([]map[common.Address]uint8, len(txs))
+	)
+	for i, tx := range txs {
+		touched[i] = make(map[common.Address]uint8)
+
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, false, err
+		}
+		touched[i][from] |= addrRoleSender
+
+		if to := tx.To(); to != nil {
+			touched[i][*to] |= addrRoleRecipient
+			continue
+		}
+		// Contract creation, index the address of the new contract
+		if receipts == nil {
+			if receipts = core.GetBlockReceipts(db, block.Hash(), block.NumberU64()); len(receipts) != len(txs) {
+				return nil, false, fmt.Errorf("block #%d [%x…] receipts missing", block.NumberU64(), block.Hash().Bytes()[:4])
+			}
+		}
+		touched[i][receipts[i].ContractAddress] |= addrRoleRecipient
+	}
+	if internal && len(txs) > 0 {
+		if err := internalCallTargets(chain, block, touched); err != nil {
+			log.Debug("Failed to index internal calls", "number", block.Number(), "hash", block.Hash(), "err", err)
+			return touched, true, nil
+		}
+	}
+	return touched, false, nil
+}
+
+// internalCallTargets replays the transactions of a block on top of the state of
+// its parent, marking the targets of all internal calls.
+func internalCallTargets(chain *core.BlockChain, block *types.Block, touched []map[common.Address]uint8) error {
+	parent := chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return fmt.Errorf("parent %x not found", block.ParentHash())
+	}
+	statedb, err := chain.StateAt(parent.Root())
+	if err != nil {
+		return err
+	}
+	config := chain.Config()
+	if config.DAOForkSupport && config.DAOForkBlock != nil && config.DAOForkBlock.Cmp(block.Number()) == 0 {
+		misc.ApplyDAOHardFork(statedb)
+	}
+	var (
+		header  = block.Header()
+		gp      = new(core.GasPool).AddGas(block.GasLimit())
+		usedGas = new(uint64)
+	)
+	for i, tx := range block.Transactions() {
+		tracer := &callTargetTracer{targets: touched[i]}
+
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		if _, _, err := core.ApplyTransaction(config, chain, nil, gp, statedb, header, tx, usedGas, vm.Config{Debug: true, Tracer: tracer}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// callTargetTracer is a vm.Tracer marking the targets of all message calls made
+// by contracts.
+type callTargetTracer struct {
+	targets map[common.Address]uint8
+}
+
+func (t *callTargetTracer) CaptureStart(from common.Address, to common.Address, call bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+func (t *callTargetTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	switch op {
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		if len(stack.Data()) < 2 {
+			break
+		}
+		// Skip the precompiled contracts, they are called way too often
+		target := common.BigToAddress(stack.Back(1))
+		if _, ok := vm.PrecompiledContractsByzantium[target]; !ok {
+			t.targets[target] |= addrRoleInternal
+		}
+	}
+	return nil
+}
+
+func (t *callTargetTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *callTargetTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
@@ -0,0 +1,272 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Tests that the address index tracks the senders, recipients and internal call
+// targets of transactions, and that the history of an address can be paginated
+// across both indexed and not yet indexed blocks.
+func TestAddressIndex(t *testing.T) {
+	var (
+		userKey, _ = crypto.GenerateKey()
+		user       = crypto.PubkeyToAddress(userKey.PublicKey)
+		callee     = common.Address{0xca, 0x11, 0xee}
+		caller     = common.Address{0xca, 0x11, 0xe4}
+		created    = crypto.CreateAddress(testBank, 0)
+
+		db, _ = ethdb.NewMemDatabase()
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				testBank: {Balance: big.NewInt(1000000000000000000)},
+				callee:   {Balance: new(big.Int), Code: []byte{0x00}},
+				// PUSH1 0 (x5), PUSH20 callee, GAS, CALL, STOP
+				caller: {Balance: new(big.Int), Code: common.FromHex("6000600060006000600073" + common.Bytes2Hex(callee.Bytes()) + "5af100")},
+			},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.HomesteadSigner{}
+		want    = make(map[common.Address][]*AddressTx)
+	)
+	expect := func(addr common.Address, number uint64, index int, hash common.Hash, roles uint8) {
+		want[addr] = append(want[addr], &AddressTx{
+			BlockNumber:      hexutil.Uint64(number),
+			TransactionIndex: hexutil.Uint(index),
+			Hash:             hash,
+			Sender:           roles&addrRoleSender != 0,
+			Recipient:        roles&addrRoleRecipient != 0,
+			Internal:         roles&addrRoleInternal != 0,
+		})
+	}
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 80, func(i int, b *core.BlockGen) {
+		number, index := uint64(i+1), 0
+		add := func(tx *types.Transaction) common.Hash {
+			b.AddTx(tx)
+			index++
+			return tx.Hash()
+		}
+		switch {
+		case i == 0:
+			tx, _ := types.SignTx(types.NewContractCreation(b.TxNonce(testBank), new(big.Int), 100000, nil, []byte{0x00}), signer, testBankKey)
+			hash := add(tx)
+			expect(testBank, number, index-1, hash, addrRoleSender)
+			expect(created, number, index-1, hash, addrRoleRecipient)
+		case i%3 == 0:
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(testBank), user, big.NewInt(1000000000000000), 21000, nil, nil), signer, testBankKey)
+			hash := add(tx)
+			expect(testBank, number, index-1, hash, addrRoleSender)
+			expect(user, number, index-1, hash, addrRoleRecipient)
+		}
+		if i > 3 && i%5 == 0 {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(user), caller, new(big.Int), 100000, nil, nil), signer, userKey)
+			hash := add(tx)
+			expect(user, number, index-1, hash, addrRoleSender)
+			expect(caller, number, index-1, hash, addrRoleRecipient)
+			expect(callee, number, index-1, hash, addrRoleInternal)
+		}
+		if i == 20 {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(user), user, new(big.Int), 21000, nil, nil), signer, userKey)
+			hash := add(tx)
+			expect(user, number, index-1, hash, addrRoleSender|addrRoleRecipient)
+		}
+	})
+	blockchain, _ := core.NewBlockChain(db, gspec.Config, ethash.NewFaker(), vm.Config{})
+	defer blockchain.Stop()
+
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	// Index the chain and wait until all confirmed sections are processed
+	indexer := NewAddressIndexer(db, blockchain, 8, true)
+	indexer.Start(blockchain)
+	defer indexer.Close()
+
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if sections, _, _ := indexer.Sections(); sections == 2 {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("address index not generated")
+		}
+	}
+	api := NewPublicAddressIndexAPI(db, blockchain, indexer, 8, true)
+	api.pageSize = 3
+
+	for addr, txs := range want {
+		var (
+			have   []*AddressTx
+			cursor *AddressTxCursor
+		)
+		for {
+			page, err := api.GetTransactionsByAddress(context.Background(), addr, 0, rpc.LatestBlockNumber, cursor)
+			if err != nil {
+				t.Fatalf("%x: failed to retrieve transactions: %v", addr, err)
+			}
+			if len(page.Transactions) > api.pageSize {
+				t.Fatalf("%x: page size mismatch: have %d, want at most %d", addr, len(page.Transactions), api.pageSize)
+			}
+			have = append(have, page.Transactions...)
+			if cursor = page.Next; cursor == nil {
+				break
+			}
+		}
+		if !reflect.DeepEqual(have, txs) {
+			t.Errorf("%x: transaction mismatch:\nhave %s\nwant %s", addr, dumper.Sdump(have), dumper.Sdump(txs))
+		}
+	}
+	// Check that the block range is honoured across indexed and unindexed blocks
+	page, err := api.GetTransactionsByAddress(context.Background(), caller, 10, 24, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve transactions: %v", err)
+	}
+	var numbers []uint64
+	for _, tx := range page.Transactions {
+		numbers = append(numbers, uint64(tx.BlockNumber))
+	}
+	if page.Next != nil {
+		t.Errorf("unexpected continuation cursor: %v", page.Next)
+	}
+	if !reflect.DeepEqual(numbers, []uint64{11, 16, 21}) {
+		t.Errorf("ranged transaction mismatch: have %v, want %v", numbers, []uint64{11, 16, 21})
+	}
+	// Check that scanning unindexed blocks is cut into pages, resuming correctly
+	api.pageSize, api.scanLimit = addressTxPageSize, 2
+
+	var (
+		pages  int
+		cursor *AddressTxCursor
+	)
+	numbers = nil
+	for {
+		page, err := api.GetTransactionsByAddress(context.Background(), caller, 10, 24, cursor)
+		if err != nil {
+			t.Fatalf("failed to retrieve transactions: %v", err)
+		}
+		for _, tx := range page.Transactions {
+			numbers = append(numbers, uint64(tx.BlockNumber))
+		}
+		if pages++; page.Next == nil {
+			break
+		}
+		cursor = page.Next
+	}
+	if !reflect.DeepEqual(numbers, []uint64{11, 16, 21}) {
+		t.Errorf("scan limited transaction mismatch: have %v, want %v", numbers, []uint64{11, 16, 21})
+	}
+	if pages != 5 {
+		t.Errorf("scan limited page count mismatch: have %d, want %d", pages, 5)
+	}
+}
+
+// Tests that blocks whose internal calls can't be traced due to missing state
+// are recorded by the index and reported to the queries covering them.
+func TestAddressIndexGaps(t *testing.T) {
+	var (
+		callee = common.Address{0xca, 0x11, 0xee}
+		caller = common.Address{0xca, 0x11, 0xe4}
+
+		db, _ = ethdb.NewMemDatabase()
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				testBank: {Balance: big.NewInt(1000000000000000000)},
+				callee:   {Balance: new(big.Int), Code: []byte{0x00}},
+				// PUSH1 0 (x5), PUSH20 callee, GAS, CALL, STOP
+				caller: {Balance: new(big.Int), Code: common.FromHex("6000600060006000600073" + common.Bytes2Hex(callee.Bytes()) + "5af100")},
+			},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.HomesteadSigner{}
+	)
+	// Call the callee internally from blocks 1, 5, 20 and 80
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 80, func(i int, b *core.BlockGen) {
+		if i == 0 || i == 4 || i == 19 || i == 79 {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(testBank), caller, new(big.Int), 100000, nil, nil), signer, testBankKey)
+			b.AddTx(tx)
+		}
+	})
+	blockchain, _ := core.NewBlockChain(db, gspec.Config, ethash.NewFaker(), vm.Config{})
+	defer blockchain.Stop()
+
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	// Drop the parent states of blocks 5 (indexed) and 20 (not indexed)
+	db.Delete(blocks[3].Root().Bytes())
+	db.Delete(blocks[18].Root().Bytes())
+
+	indexer := NewAddressIndexer(db, blockchain, 8, true)
+	indexer.Start(blockchain)
+	defer indexer.Close()
+
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if sections, _, _ := indexer.Sections(); sections == 2 {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("address index not generated")
+		}
+	}
+	api := NewPublicAddressIndexAPI(db, blockchain, indexer, 8, true)
+	api.pageSize = 1
+
+	tests := []struct {
+		from, to   rpc.BlockNumber
+		cursor     *AddressTxCursor
+		blocks     []uint64
+		incomplete []hexutil.Uint64
+	}{
+		{0, rpc.LatestBlockNumber, nil, []uint64{1}, []hexutil.Uint64{5, 20}},
+		{0, rpc.LatestBlockNumber, &AddressTxCursor{Block: 80}, []uint64{80}, nil},
+		{2, 16, nil, nil, []hexutil.Uint64{5}},
+		{6, 19, nil, nil, nil},
+	}
+	for i, tt := range tests {
+		page, err := api.GetTransactionsByAddress(context.Background(), callee, tt.from, tt.to, tt.cursor)
+		if err != nil {
+			t.Fatalf("test %d: failed to retrieve transactions: %v", i, err)
+		}
+		var numbers []uint64
+		for _, tx := range page.Transactions {
+			numbers = append(numbers, uint64(tx.BlockNumber))
+		}
+		if !reflect.DeepEqual(numbers, tt.blocks) {
+			t.Errorf("test %d: transaction mismatch: have %v, want %v", i, numbers, tt.blocks)
+		}
+		if !reflect.DeepEqual(page.Incomplete, tt.incomplete) {
+			t.Errorf("test %d: incomplete blocks mismatch: have %v, want %v", i, page.Incomplete, tt.incomplete)
+		}
+	}
+}
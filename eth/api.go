@@ -19,6 +19,8 @@ package eth
 import (
 	"compress/gzip"
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -30,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/params"
@@ -484,3 +487,197 @@ func (api *PrivateDebugAPI) getModifiedAccounts(startBlock, endBlock *types.Bloc
 	}
 	return dirty, nil
 }
+
+// addressTxPageSize is the maximum number of transactions returned by a single
+// address history query.
+const addressTxPageSize = 1000
+
+// addressScanLimit is the maximum number of blocks not covered by the address
+// index yet, which are scanned (and possibly traced) by a single query.
+const addressScanLimit = 128
+
+// AddressTxCursor is the continuation token of a paginated address history
+// query. It points at the first transaction which has not been returned yet.
+type AddressTxCursor struct {
+	Block uint64 // Number of the block to resume the search at
+	Index uint   // Index of the first transaction to return within the block
+}
+
+// MarshalText encodes the cursor as an opaque hex string.
+func (c AddressTxCursor) MarshalText() ([]byte, error) {
+	var enc [12]byte
+	binary.BigEndian.PutUint64(enc[:8], c.Block)
+	binary.BigEndian.PutUint32(enc[8:], uint32(c.Index))
+	return hexutil.Bytes(enc[:]).MarshalText()
+}
+
+// UnmarshalText decodes a cursor previously returned by MarshalText.
+func (c *AddressTxCursor) UnmarshalText(input []byte) error {
+	var dec hexutil.Bytes
+	if err := dec.UnmarshalText(input); err != nil || len(dec) != 12 {
+		return errors.New("invalid transaction cursor")
+	}
+	c.Block, c.Index = binary.BigEndian.Uint64(dec[:8]), uint(binary.BigEndian.Uint32(dec[8:]))
+	return nil
+}
+
+// AddressTx is a transaction touching a queried address.
+type AddressTx struct {
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex hexutil.Uint   `json:"transactionIndex"`
+	Hash             common.Hash    `json:"hash"`
+	Sender           bool           `json:"sender"`    // Address signed the transaction
+	Recipient        bool           `json:"recipient"` // Address received the transaction or is the created contract
+	Internal         bool           `json:"internal"`  // Address was the target of an internal call
+}
+
+// AddressTxPage is a single page of address history results.
+type AddressTxPage struct {
+	Transactions []*AddressTx     `json:"transactions"`
+	Next         *AddressTxCursor `json:"next"`                 // Cursor of the next page, nil if the whole range was searched
+	Incomplete   []hexutil.Uint64 `json:"incomplete,omitempty"` // Blocks of the page range whose internal calls are unknown (e.g. pruned state)
+}
+
+// PublicAddressIndexAPI provides an API to query the transaction history of
+// accounts using the address index.
+type PublicAddressIndexAPI struct {
+	db        ethdb.Database
+	chain     *core.BlockChain
+	indexer   *core.ChainIndexer
+	size      uint64
+	internal  bool
+	pageSize  int
+	scanLimit int
+}
+
+// NewPublicAddressIndexAPI creates a new API definition for querying the address
+// index maintained by the given chain indexer.
+func NewPublicAddressIndexAPI(db ethdb.Database, chain *core.BlockChain, indexer *core.ChainIndexer, size uint64, internal bool) *PublicAddressIndexAPI {
+	return &PublicAddressIndexAPI{
+		db:        db,
+		chain:     chain,
+		indexer:   indexer,
+		size:      size,
+		internal:  internal,
+		pageSize:  addressTxPageSize,
+		scanLimit: addressScanLimit,
+	}
+}
+
+// GetTransactionsByAddress returns a page of the transactions sent from or to
+// the given address within the given block range, ordered by their position in
+// the chain. The query is continued by passing the cursor of the previous page.
+// Pages end early if too many blocks not yet indexed had to be scanned, so they
+// may be short or even empty while the cursor is set. Blocks whose internal calls
+// couldn't be traced are listed as incomplete.
+func (api *PublicAddressIndexAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, fromBlock, toBlock rpc.BlockNumber, cursor *AddressTxCursor) (*AddressTxPage, error) {
+	head := api.chain.CurrentBlock().NumberU64()
+
+	begin, end := uint64(fromBlock.Int64()), uint64(toBlock.Int64())
+	if fromBlock < 0 {
+		begin = head
+	}
+	if toBlock < 0 || end > head {
+		end = head
+	}
+	if cursor != nil {
+		if cursor.Block < begin || cursor.Block > end {
+			return nil, fmt.Errorf("cursor block #%d outside of the queried range", cursor.Block)
+		}
+		begin = cursor.Block
+	}
+	page := &AddressTxPage{Transactions: []*AddressTx{}}
+
+	// Blocks missing internal calls are reported up to where the page ends
+	var gaps []uint64
+	finish := func() *AddressTxPage {
+		for _, number := range gaps {
+			if page.Next != nil && number >= page.Next.Block {
+				break
+			}
+			page.Incomplete = append(page.Incomplete, hexutil.Uint64(number))
+		}
+		return page
+	}
+
+	// Collects the next transaction, returning true once the page is full
+	add := func(entry *addrIndexEntry) bool {
+		if entry.Number < begin || entry.Number > end {
+			return false
+		}
+		if cursor != nil && entry.Number == cursor.Block && entry.Index < cursor.Index {
+			return false
+		}
+		if len(page.Transactions) == api.pageSize {
+			page.Next = &AddressTxCursor{Block: entry.Number, Index: entry.Index}
+			return true
+		}
+		page.Transactions = append(page.Transactions, &AddressTx{
+			BlockNumber:      hexutil.Uint64(entry.Number),
+			TransactionIndex: hexutil.Uint(entry.Index),
+			Hash:             entry.Hash,
+			Sender:           entry.Roles&addrRoleSender != 0,
+			Recipient:        entry.Roles&addrRoleRecipient != 0,
+			Internal:         entry.Roles&addrRoleInternal != 0,
+		})
+		return false
+	}
+	// Serve the indexed sections from the index, scan the remaining blocks
+	sections, _, _ := api.indexer.Sections()
+	scanned := 0
+	for number := begin; number <= end; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if section := number / api.size; section < sections {
+			entries, err := readAddressIndex(api.db, address, section, api.indexer.SectionHead(section))
+			if err != nil {
+				return nil, err
+			}
+			if api.internal {
+				missing, err := readAddressIndexGaps(api.db, section, api.indexer.SectionHead(section))
+				if err != nil {
+					return nil, err
+				}
+				for _, gap := range missing {
+					if gap >= begin && gap <= end {
+						gaps = append(gaps, gap)
+					}
+				}
+			}
+			for _, entry := range entries {
+				if add(entry) {
+					return finish(), nil
+				}
+			}
+			number = (section + 1) * api.size
+			continue
+		}
+		if scanned == api.scanLimit {
+			page.Next = &AddressTxCursor{Block: number}
+			return finish(), nil
+		}
+		scanned++
+
+		block := api.chain.GetBlockByNumber(number)
+		if block == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		touched, missing, err := blockAddresses(api.db, api.chain, block, api.internal)
+		if err != nil {
+			return nil, err
+		}
+		if missing {
+			gaps = append(gaps, number)
+		}
+		for i, roles := range touched {
+			if role, ok := roles[address]; ok {
+				if add(&addrIndexEntry{Number: number, Index: uint(i), Hash: block.Transactions()[i].Hash(), Roles: role}) {
+					return finish(), nil
+				}
+			}
+		}
+		number++
+	}
+	return finish(), nil
+}
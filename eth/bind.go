@@ -18,17 +18,30 @@ package eth
 
 import (
 	"context"
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+var (
+	// errLogFilteringUnsupported is returned by the log filtering methods of a
+	// native contract backend without access to the filter system.
+	errLogFilteringUnsupported = errors.New("native contract backend doesn't support log filtering")
+
+	// errPastLogsUnsupported is returned if a log subscription is requested to
+	// start at a past block, as the native contract backend only streams new logs.
+	errPastLogsUnsupported = errors.New("native contract backend doesn't support watching past logs")
+)
+
 // ContractBackend implements bind.ContractBackend with direct calls to Ethereum
 // internals to support operating on contracts within subprotocols like eth and
 // swarm.
@@ -40,6 +53,9 @@ type ContractBackend struct {
 	eapi  *ethapi.PublicEthereumAPI        // Wrapper around the Ethereum object to access metadata
 	bcapi *ethapi.PublicBlockChainAPI      // Wrapper around the blockchain to access chain data
 	txapi *ethapi.PublicTransactionPoolAPI // Wrapper around the transaction pool to access transaction data
+
+	filter filters.Backend      // Filter backend to run log queries with, nil if unsupported
+	events *filters.EventSystem // Event system to subscribe to new logs, nil if unsupported
 }
 
 // NewContractBackend creates a new native contract backend using an existing
@@ -52,6 +68,16 @@ func NewContractBackend(apiBackend ethapi.Backend) *ContractBackend {
 	}
 }
 
+// NewFilterContractBackend creates a new native contract backend using an existing
+// Ethereum object, also supporting log filtering through the given filter backend,
+// usually the same object. The lightMode flag denotes a light client backend.
+func NewFilterContractBackend(apiBackend ethapi.Backend, filterBackend filters.Backend, lightMode bool) *ContractBackend {
+	backend := NewContractBackend(apiBackend)
+	backend.filter = filterBackend
+	backend.events = filters.NewEventSystem(filterBackend.EventMux(), filterBackend, lightMode)
+	return backend
+}
+
 // CodeAt retrieves any code associated with the contract from the local API.
 func (b *ContractBackend) CodeAt(ctx context.Context, contract common.Address, blockNum *big.Int) ([]byte, error) {
 	return b.bcapi.GetCode(ctx, contract, toBlockNumber(blockNum))
@@ -134,3 +160,72 @@ func (b *ContractBackend) SendTransaction(ctx context.Context, tx *types.Transac
 	_, err := b.txapi.SendRawTransaction(ctx, raw)
 	return err
 }
+
+// FilterLogs implements bind.ContractFilterer, executing a log filter operation
+// if the backend was created with log filtering support.
+func (b *ContractBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if b.filter == nil {
+		return nil, errLogFilteringUnsupported
+	}
+	// Initialize unset filter boundaries to run from genesis to chain head
+	from := int64(0)
+	if query.FromBlock != nil {
+		from = query.FromBlock.Int64()
+	}
+	to := int64(rpc.LatestBlockNumber)
+	if query.ToBlock != nil {
+		to = query.ToBlock.Int64()
+	}
+	logs, err := filters.New(b.filter, from, to, query.Addresses, query.Topics).Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]types.Log, len(logs))
+	for i, log := range logs {
+		res[i] = *log
+	}
+	return res, nil
+}
+
+// SubscribeFilterLogs implements bind.ContractFilterer, streaming the new logs
+// matching the query if the backend was created with log filtering support.
+func (b *ContractBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	if b.events == nil {
+		return nil, errLogFilteringUnsupported
+	}
+	if query.FromBlock != nil {
+		return nil, errPastLogsUnsupported
+	}
+	sink := make(chan []*types.Log)
+
+	sub, err := b.events.SubscribeLogs(filters.FilterCriteria{
+		ToBlock:   query.ToBlock,
+		Addresses: query.Addresses,
+		Topics:    query.Topics,
+	}, sink)
+	if err != nil {
+		return nil, err
+	}
+	// Logs arrive in batches, flatten them into a plain stream
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case logs := <-sink:
+				for _, log := range logs {
+					select {
+					case ch <- *log:
+					case err := <-sub.Err():
+						return err
+					case <-quit:
+						return nil
+					}
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/params"
 )
@@ -52,6 +53,7 @@ var DefaultConfig = Config{
 		Blocks:     10,
 		Percentile: 50,
 	},
+	Filters: filters.DefaultConfig,
 }
 
 func init() {
@@ -68,6 +70,16 @@ func init() {
 	}
 }
 
+// ULCConfig is the configuration of the ultra light client mode.
+type ULCConfig struct {
+	TrustedServers     []string `toml:",omitempty"` // Enode URLs of the servers whose announcements are trusted
+	MinTrustedFraction int      `toml:",omitempty"` // Percentage of trusted servers that must announce a header
+}
+
+// DefaultULCMinTrustedFraction is the default percentage of trusted servers that
+// must announce a header before an ultra light client accepts it.
+const DefaultULCMinTrustedFraction = 75
+
 //go:generate gencodec -type Config -field-override configMarshaling -formats toml -out gen_config.go
 
 type Config struct {
@@ -79,10 +91,18 @@ type Config struct {
 	NetworkId uint64 // Network ID to use for selecting peers to connect to
 	SyncMode  downloader.SyncMode
 
+	// Checkpoint is a trusted block the synced chain must contain. Headers
+	// below it are not seal-verified during fast and light sync.
+	Checkpoint *downloader.Checkpoint `toml:",omitempty"`
+
 	// Light client options
 	LightServ  int `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
 	LightPeers int `toml:",omitempty"` // Maximum number of LES client peers
 
+	// ULC enables the ultra light client mode, trusting the headers announced
+	// by a quorum of the configured servers instead of verifying them.
+	ULC *ULCConfig `toml:",omitempty"`
+
 	// Database options
 	SkipBcVersionCheck bool `toml:"-"`
 	DatabaseHandles    int  `toml:"-"`
@@ -103,6 +123,13 @@ type Config struct {
 	// Gas Price Oracle options
 	GPO gasprice.Config
 
+	// Log query limits
+	Filters filters.Config
+
+	// Address index options
+	AddressIndex         bool `toml:",omitempty"` // Maintain an index of the transactions of every address
+	AddressIndexInternal bool `toml:",omitempty"` // Index the targets of internal calls too (requires historical state)
+
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
@@ -0,0 +1,166 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Checkpoint is a trusted block of the canonical chain. Headers below the
+// checkpoint which are shown to link up to it are secured by its hash, so their
+// seals are not verified during fast and light sync. Peers whose chain does not
+// contain the checkpoint are refused.
+type Checkpoint struct {
+	Number uint64      // Block number of the checkpoint
+	Hash   common.Hash // Hash of the checkpoint block
+	TD     *big.Int    // Total difficulty of the chain up to and including the checkpoint
+}
+
+// String implements the stringer interface.
+func (cp *Checkpoint) String() string {
+	if cp == nil {
+		return ""
+	}
+	text, _ := cp.MarshalText()
+	return string(text)
+}
+
+// MarshalText encodes the checkpoint as <number>:<hash>:<td>.
+func (cp Checkpoint) MarshalText() ([]byte, error) {
+	td := "0"
+	if cp.TD != nil {
+		td = cp.TD.String()
+	}
+	return []byte(fmt.Sprintf("%d:%s:%s", cp.Number, cp.Hash.Hex(), td)), nil
+}
+
+// UnmarshalText parses a checkpoint in the <number>:<hash>:<td> format. The
+// total difficulty may be omitted.
+func (cp *Checkpoint) UnmarshalText(text []byte) error {
+	parts := strings.Split(string(text), ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return fmt.Errorf("invalid checkpoint %q, want <number>:<hash>:<td>", text)
+	}
+	number, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid checkpoint number %q", parts[0])
+	}
+	hash, err := hexutil.Decode(parts[1])
+	if err != nil || len(hash) != common.HashLength {
+		return fmt.Errorf("invalid checkpoint hash %q", parts[1])
+	}
+	td := new(big.Int)
+	if len(parts) == 3 {
+		if _, ok := td.SetString(parts[2], 10); !ok {
+			return fmt.Errorf("invalid checkpoint total difficulty %q", parts[2])
+		}
+	}
+	*cp = Checkpoint{Number: number, Hash: common.BytesToHash(hash), TD: td}
+	return nil
+}
+
+// verifyCheckpoint ensures that the chain of the remote peer contains the
+// trusted checkpoint. Peers that have not reached the checkpoint yet are
+// rejected without being penalised.
+func (d *Downloader) verifyCheckpoint(p *peerConnection, latest *types.Header, td *big.Int) error {
+	cp := d.checkpoint
+	if cp == nil {
+		return nil
+	}
+	if td != nil && cp.TD != nil && td.Cmp(cp.TD) < 0 {
+		p.log.Debug("Remote chain behind checkpoint", "td", td, "checkpoint", cp.TD)
+		return errBehindCheckpoint
+	}
+	if latest.Number.Uint64() < cp.Number {
+		p.log.Debug("Remote chain behind checkpoint", "number", latest.Number, "checkpoint", cp.Number)
+		return errBehindCheckpoint
+	}
+	p.log.Debug("Retrieving remote checkpoint header", "number", cp.Number)
+	go p.peer.RequestHeadersByNumber(cp.Number, 1, 0, false)
+
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for {
+		select {
+		case <-d.cancelCh:
+			return errCancelBlockFetch
+
+		case packet := <-d.headerCh:
+			// Discard anything not from the origin peer
+			if packet.PeerId() != p.id {
+				log.Debug("Received headers from incorrect peer", "peer", packet.PeerId())
+				break
+			}
+			// Make sure the peer actually gave something valid
+			headers := packet.(*headerPack).headers
+			if len(headers) != 1 {
+				p.log.Debug("Multiple headers for single request", "headers", len(headers))
+				return errBadPeer
+			}
+			if header := headers[0]; header.Number.Uint64() != cp.Number || header.Hash() != cp.Hash {
+				p.log.Warn("Remote chain doesn't contain checkpoint", "number", header.Number, "hash", header.Hash(), "checkpoint", cp.Hash)
+				return errCheckpointMismatch
+			}
+			return nil
+
+		case <-timeout:
+			p.log.Debug("Waiting for checkpoint header timed out", "elapsed", ttl)
+			return errTimeout
+
+		case <-d.bodyCh:
+		case <-d.receiptCh:
+			// Out of bounds delivery, ignore
+		}
+	}
+}
+
+// linkCheckpoint ensures that a contiguous batch of headers reaching up to the
+// checkpoint contains it, and that their parent hashes lead back from it to the
+// first header. It returns the number of the last header thus secured.
+func (d *Downloader) linkCheckpoint(headers []*types.Header) (uint64, error) {
+	cp := d.checkpoint
+
+	index := int(cp.Number - headers[0].Number.Uint64())
+	if index < 0 || index >= len(headers) || headers[index].Number.Uint64() != cp.Number {
+		log.Debug("Non contiguous headers around checkpoint", "first", headers[0].Number, "checkpoint", cp.Number)
+		return 0, errInvalidChain
+	}
+	if header := headers[index]; header.Hash() != cp.Hash {
+		log.Warn("Checkpoint mismatch", "number", header.Number, "remoteHash", header.Hash(), "checkpointHash", cp.Hash)
+		return 0, errCheckpointMismatch
+	}
+	for i := index; i > 0; i-- {
+		if headers[i].Number.Uint64() != headers[i-1].Number.Uint64()+1 {
+			log.Debug("Non contiguous headers below checkpoint", "number", headers[i-1].Number, "next", headers[i].Number)
+			return 0, errInvalidChain
+		}
+		if headers[i].ParentHash != headers[i-1].Hash() {
+			log.Warn("Headers not linked to checkpoint", "number", headers[i-1].Number, "hash", headers[i-1].Hash(), "checkpoint", cp.Number)
+			return 0, errCheckpointMismatch
+		}
+	}
+	return cp.Number, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCheckpointText(t *testing.T) {
+	cp := Checkpoint{Number: 4096, Hash: common.HexToHash("0x01"), TD: big.NewInt(123456)}
+	text, err := cp.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to encode checkpoint: %v", err)
+	}
+	var dec Checkpoint
+	if err := dec.UnmarshalText(text); err != nil {
+		t.Fatalf("failed to decode checkpoint %q: %v", text, err)
+	}
+	if !reflect.DeepEqual(dec, cp) {
+		t.Errorf("checkpoint mismatch: have %v, want %v", &dec, &cp)
+	}
+	for _, invalid := range []string{"", "4096", "x:0x01:1", "4096:0x01:1", "4096:" + cp.Hash.Hex() + ":y"} {
+		if err := dec.UnmarshalText([]byte(invalid)); err == nil {
+			t.Errorf("no error for invalid checkpoint %q", invalid)
+		}
+	}
+}
+
+// Tests that synchronisation only proceeds with peers whose chain contains the
+// trusted checkpoint.
+func TestCheckpointSync63Full(t *testing.T)  { testCheckpointSync(t, 63, FullSync) }
+func TestCheckpointSync63Fast(t *testing.T)  { testCheckpointSync(t, 63, FastSync) }
+func TestCheckpointSync64Full(t *testing.T)  { testCheckpointSync(t, 64, FullSync) }
+func TestCheckpointSync64Fast(t *testing.T)  { testCheckpointSync(t, 64, FastSync) }
+func TestCheckpointSync64Light(t *testing.T) { testCheckpointSync(t, 64, LightSync) }
+
+func testCheckpointSync(t *testing.T, protocol int, mode SyncMode) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	// Create a canonical chain and a fork of it with different history
+	shared, fork := MaxHashFetch, 2*MaxHashFetch
+	hashesA, hashesB, headersA, headersB, blocksA, blocksB, receiptsA, receiptsB := tester.makeChainFork(shared+fork, fork, tester.genesis, nil, true)
+
+	tester.newPeer("fork", protocol, hashesB, headersB, blocksB, receiptsB)
+	tester.newPeer("short", protocol, hashesA[fork:], headersA, blocksA, receiptsA)
+	tester.newPeer("canon", protocol, hashesA, headersA, blocksA, receiptsA)
+
+	// Place the checkpoint after the fork point on the canonical chain
+	number := uint64(shared + fork/2)
+	tester.downloader.checkpoint = &Checkpoint{
+		Number: number,
+		Hash:   hashesA[len(hashesA)-1-int(number)],
+		TD:     tester.peerChainTds["canon"][hashesA[len(hashesA)-1-int(number)]],
+	}
+	// Neither the forked nor the short peer may be synced from
+	if err := tester.sync("fork", nil, mode); err != errCheckpointMismatch {
+		t.Fatalf("fork sync error mismatch: have %v, want %v", err, errCheckpointMismatch)
+	}
+	if err := tester.sync("short", nil, mode); err != errBehindCheckpoint {
+		t.Fatalf("short sync error mismatch: have %v, want %v", err, errBehindCheckpoint)
+	}
+	assertOwnChain(t, tester, 1)
+
+	// The canonical peer contains the checkpoint and sync must succeed
+	if err := tester.sync("canon", nil, mode); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, shared+fork+1)
+}
+
+// Tests that seal checks are only skipped for headers which are shown to link up
+// to the checkpoint, even if the peer reports the checkpoint header itself.
+func TestCheckpointLinkage64Fast(t *testing.T)  { testCheckpointLinkage(t, 64, FastSync) }
+func TestCheckpointLinkage64Light(t *testing.T) { testCheckpointLinkage(t, 64, LightSync) }
+
+func testCheckpointLinkage(t *testing.T, protocol int, mode SyncMode) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	// Create a canonical chain and a fork of it with different history
+	shared, fork := MaxHashFetch, 2*MaxHashFetch
+	hashesA, hashesB, headersA, headersB, blocksA, blocksB, receiptsA, receiptsB := tester.makeChainFork(shared+fork, fork, tester.genesis, nil, true)
+
+	number := uint64(shared + fork/2)
+	checkpoint := hashesA[len(hashesA)-1-int(number)]
+	tester.downloader.checkpoint = &Checkpoint{
+		Number: number,
+		Hash:   checkpoint,
+		TD:     big.NewInt(1),
+	}
+	// Create a forked peer that serves the canonical checkpoint header on its own
+	tester.newPeer("liar", protocol, hashesB, headersB, blocksB, receiptsB)
+	tester.peerHashes["liar"][len(hashesB)-1-int(number)] = checkpoint
+	tester.peerHeaders["liar"][checkpoint] = headersA[checkpoint]
+
+	if err := tester.sync("liar", nil, mode); err == nil {
+		t.Fatalf("succeeded to synchronise unlinked chain")
+	}
+	if len(tester.checkFreqs) > 0 {
+		t.Fatalf("headers of unlinked chain inserted: have %d, want 0", len(tester.checkFreqs))
+	}
+	// The canonical peer links up to the checkpoint, skipping the seals below it
+	tester.newPeer("canon", protocol, hashesA, headersA, blocksA, receiptsA)
+	if err := tester.sync("canon", nil, mode); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	for i := len(hashesA) - 2; i >= 0; i-- {
+		header := headersA[hashesA[i]]
+		if freq := tester.checkFreqs[hashesA[i]]; (freq == 0) != (header.Number.Uint64() <= number) {
+			t.Errorf("header %d: check frequency mismatch: have %d, checkpoint %d", header.Number, freq, number)
+		}
+	}
+}
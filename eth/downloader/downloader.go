@@ -63,8 +63,10 @@ var (
 
 	fsHeaderCheckFrequency = 100        // Verification frequency of the downloaded headers during fast sync
 	fsHeaderSafetyNet      = 2048       // Number of headers to discard in case a chain violation is detected
+	fsCheckpointPending    = 16384      // Number of headers below the checkpoint to hold back until linked to it
 	fsHeaderForceVerify    = 24         // Number of headers to verify before and after the pivot to accept it
 	fsPivotInterval        = 256        // Number of headers out of which to randomize the pivot point
+	fsPivotReuseRange      = 1024       // Maximum distance from the head for a stored pivot to be reused
 	fsMinFullBlocks        = 64         // Number of blocks to retrieve fully even in fast sync
 	fsCriticalTrials       = uint32(32) // Number of times to retry in the cricical section before bailing
 )
@@ -92,12 +94,17 @@ var (
 	errCancelContentProcessing = errors.New("content processing canceled (requested)")
 	errNoSyncActive            = errors.New("no sync active")
 	errTooOld                  = errors.New("peer doesn't speak recent enough protocol version (need version >= 62)")
+	errBehindCheckpoint        = errors.New("remote chain is behind the trusted checkpoint")
+	errCheckpointMismatch      = errors.New("remote chain doesn't contain the trusted checkpoint")
 )
 
 type Downloader struct {
 	mode SyncMode       // Synchronisation mode defining the strategy used (per sync cycle)
 	mux  *event.TypeMux // Event multiplexer to announce sync operation events
 
+	checkpoint *Checkpoint // Trusted checkpoint the synced chain must contain (nil = none)
+	noSeals    bool        // Whether header seals are not verified (headers come from trusted peers)
+
 	queue   *queue   // Scheduler for selecting the hashes to download
 	peers   *peerSet // Set of active peers from which download can proceed
 	stateDB ethdb.Database
@@ -201,13 +208,14 @@ type BlockChain interface {
 }
 
 // New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(mode SyncMode, stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
+func New(mode SyncMode, checkpoint *Checkpoint, stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
 	if lightchain == nil {
 		lightchain = chain
 	}
 
 	dl := &Downloader{
 		mode:           mode,
+		checkpoint:     checkpoint,
 		stateDB:        stateDb,
 		mux:            mux,
 		queue:          newQueue(),
@@ -228,11 +236,21 @@ func New(mode SyncMode, stateDb ethdb.Database, mux *event.TypeMux, chain BlockC
 		stateSyncStart: make(chan *stateSync),
 		trackStateReq:  make(chan *stateReq),
 	}
+	if stateDb != nil {
+		dl.loadStateSyncStats()
+	}
 	go dl.qosTuner()
 	go dl.stateFetcher()
 	return dl
 }
 
+// DisableSealVerification makes the downloader skip verifying the seals of the
+// downloaded headers. It is meant for light clients syncing only from trusted
+// servers and must be called before the first synchronisation.
+func (d *Downloader) DisableSealVerification() {
+	d.noSeals = true
+}
+
 // Progress retrieves the synchronisation boundaries, specifically the origin
 // block where synchronisation started at (may have failed/suspended); the block
 // or header sync is currently at; and the latest known block which the sync targets.
@@ -260,6 +278,9 @@ func (d *Downloader) Progress() ethereum.SyncProgress {
 		HighestBlock:  d.syncStatsChainHeight,
 		PulledStates:  d.syncStatsState.processed,
 		KnownStates:   d.syncStatsState.processed + d.syncStatsState.pending,
+
+		PulledStateBytes: d.syncStatsState.bytes,
+		ResumedStates:    d.syncStatsState.resumed,
 	}
 }
 
@@ -322,9 +343,9 @@ func (d *Downloader) Synchronise(id string, head common.Hash, td *big.Int, mode
 
 	case errTimeout, errBadPeer, errStallingPeer,
 		errEmptyHeaderSet, errPeersUnavailable, errTooOld,
-		errInvalidAncestor, errInvalidChain:
+		errInvalidAncestor, errInvalidChain, errCheckpointMismatch:
 		log.Warn("Synchronisation failed, dropping peer", "peer", id, "err", err)
-		d.dropPeer(id)
+		d.dropPeer(id, err == errTimeout || err == errStallingPeer)
 
 	default:
 		log.Warn("Synchronisation failed, retrying", "err", err)
@@ -423,6 +444,9 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	if err != nil {
 		return err
 	}
+	if err := d.verifyCheckpoint(p, latest, td); err != nil {
+		return err
+	}
 	height := latest.Number.Uint64()
 
 	origin, err := d.findAncestor(p, height)
@@ -442,15 +466,23 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	case LightSync:
 		pivot = height
 	case FastSync:
-		// Calculate the new fast/slow sync pivot point
+		// Calculate the new fast/slow sync pivot point. If an interrupted sync
+		// left a pivot that is still recent enough, continue with it so that
+		// its partially downloaded state can be reused.
 		if d.fsPivotLock == nil {
-			pivotOffset, err := rand.Int(rand.Reader, big.NewInt(int64(fsPivotInterval)))
-			if err != nil {
-				panic(fmt.Sprintf("Failed to access crypto random source: %v", err))
-			}
-			if height > uint64(fsMinFullBlocks)+pivotOffset.Uint64() {
-				pivot = height - uint64(fsMinFullBlocks) - pivotOffset.Uint64()
+			if stored, ok := d.storedFastSyncPivot(); ok && stored <= height && height-stored <= uint64(fsPivotReuseRange) {
+				pivot = stored
+				log.Debug("Reusing stored fast sync pivot", "pivot", pivot)
+			} else {
+				pivotOffset, err := rand.Int(rand.Reader, big.NewInt(int64(fsPivotInterval)))
+				if err != nil {
+					panic(fmt.Sprintf("Failed to access crypto random source: %v", err))
+				}
+				if height > uint64(fsMinFullBlocks)+pivotOffset.Uint64() {
+					pivot = height - uint64(fsMinFullBlocks) - pivotOffset.Uint64()
+				}
 			}
+			d.storeFastSyncPivot(pivot)
 		} else {
 			// Pivot point locked in, use this and do not pick a new one!
 			pivot = d.fsPivotLock.Number.Uint64()
@@ -856,7 +888,7 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64) error {
 			// Header retrieval timed out, consider the peer bad and drop
 			p.log.Debug("Header request timed out", "elapsed", ttl)
 			headerTimeoutMeter.Mark(1)
-			d.dropPeer(p.id)
+			d.dropPeer(p.id, true)
 
 			// Finish the sync gracefully instead of dumping the gathered data though
 			for _, ch := range []chan bool{d.bodyWakeCh, d.receiptWakeCh} {
@@ -1071,7 +1103,7 @@ func (d *Downloader) fetchParts(errCancel error, deliveryCh chan dataPack, deliv
 						setIdle(peer, 0)
 					} else {
 						peer.log.Debug("Stalling delivery, dropping", "type", kind)
-						d.dropPeer(pid)
+						d.dropPeer(pid, true)
 					}
 				}
 			}
@@ -1187,6 +1219,9 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 		}
 	}()
 
+	// Keep the headers below the checkpoint until they are shown to link up to it
+	unlinked := []*types.Header{}
+
 	// Wait for batches of headers to process
 	gotHeaders := false
 
@@ -1198,6 +1233,10 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 		case headers := <-d.headerProcCh:
 			// Terminate header processing if we synced up
 			if len(headers) == 0 {
+				// If headers are still held back, the peer didn't deliver the promised checkpoint
+				if len(unlinked) > 0 {
+					return errStallingPeer
+				}
 				// Notify everyone that headers are fully processed
 				for _, ch := range []chan bool{d.bodyWakeCh, d.receiptWakeCh} {
 					select {
@@ -1241,6 +1280,27 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 			// Otherwise split the chunk of headers into batches and process them
 			gotHeaders = true
 
+			// Headers below the checkpoint need no seal checks, but only once their
+			// parent hashes are shown to lead up to it. Hold them back until then,
+			// releasing the oldest with regular checks if too many accumulate.
+			linked := uint64(0)
+			if cp := d.checkpoint; cp != nil && (d.mode == FastSync || d.mode == LightSync) && !d.noSeals {
+				if len(unlinked) > 0 || headers[0].Number.Uint64() < cp.Number {
+					headers, unlinked = append(unlinked, headers...), nil
+					if headers[len(headers)-1].Number.Uint64() < cp.Number {
+						if len(headers) <= fsCheckpointPending {
+							unlinked = headers
+							continue
+						}
+						headers, unlinked = headers[:len(headers)-fsCheckpointPending], headers[len(headers)-fsCheckpointPending:]
+					} else {
+						var err error
+						if linked, err = d.linkCheckpoint(headers); err != nil {
+							return err
+						}
+					}
+				}
+			}
 			for len(headers) > 0 {
 				// Terminate if something failed in between processing chunks
 				select {
@@ -1253,8 +1313,18 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 				if limit > len(headers) {
 					limit = len(headers)
 				}
+				if first := headers[0].Number.Uint64(); first <= linked && first+uint64(limit) > linked+1 {
+					limit = int(linked - first + 1)
+				}
 				chunk := headers[:limit]
 
+				// Reject the chain outright if it doesn't contain the trusted checkpoint
+				if cp := d.checkpoint; cp != nil && chunk[0].Number.Uint64() <= cp.Number && chunk[len(chunk)-1].Number.Uint64() >= cp.Number {
+					if header := chunk[int(cp.Number-chunk[0].Number.Uint64())]; header.Hash() != cp.Hash {
+						log.Warn("Checkpoint mismatch", "number", header.Number, "remoteHash", header.Hash(), "checkpointHash", cp.Hash)
+						return errCheckpointMismatch
+					}
+				}
 				// In case of header only syncing, validate the chunk immediately
 				if d.mode == FastSync || d.mode == LightSync {
 					// Collect the yet unknown headers to mark them as uncertain
@@ -1269,6 +1339,14 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 					if chunk[len(chunk)-1].Number.Uint64()+uint64(fsHeaderForceVerify) > pivot {
 						frequency = 1
 					}
+					// Headers linked to the checkpoint are secured by its hash
+					if chunk[len(chunk)-1].Number.Uint64() <= linked {
+						frequency = 0
+					}
+					// Headers from trusted peers don't need their seals checked at all
+					if d.noSeals {
+						frequency = 0
+					}
 					if n, err := d.lightchain.InsertHeaderChain(chunk, frequency); err != nil {
 						// If some headers were inserted, add them too to the rollback list
 						if n > 0 {
@@ -1465,7 +1543,11 @@ func (d *Downloader) commitPivotBlock(result *fetchResult) error {
 	if _, err := d.blockchain.InsertReceiptChain([]*types.Block{b}, []types.Receipts{result.Receipts}); err != nil {
 		return err
 	}
-	return d.blockchain.FastSyncCommitHead(b.Hash())
+	if err := d.blockchain.FastSyncCommitHead(b.Hash()); err != nil {
+		return err
+	}
+	d.clearFastSyncProgress()
+	return nil
 }
 
 // DeliverHeaders injects a new batch of block headers received from a remote
@@ -17,6 +17,7 @@
 package downloader
 
 import (
+	"encoding/binary"
 	"fmt"
 	"hash"
 	"sync"
@@ -28,9 +29,31 @@ import (
 	"github.com/ethereum/go-ethereum/crypto/sha3"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 )
 
+var (
+	// stateJournalPrefix + hash -> state entry downloaded but not yet committed.
+	// The journal allows an interrupted or re-rooted state sync to reuse data that
+	// was already retrieved from the network.
+	stateJournalPrefix = []byte("ss")
+
+	// stateSyncStatsKey tracks the progress stats of an unfinished state sync.
+	stateSyncStatsKey = []byte("StateSyncStats")
+
+	// stateJournalIndexPrefix + num (uint64 big endian) -> hashes of a batch of
+	// journalled entries, allowing them to be removed without iterating the
+	// database.
+	stateJournalIndexPrefix = []byte("ssi")
+
+	// stateJournalIndexCountKey tracks the number of journal index batches.
+	stateJournalIndexCountKey = []byte("StateJournalIndexCount")
+
+	// fastSyncPivotKey tracks the pivot block number of an unfinished fast sync.
+	fastSyncPivotKey = []byte("FastSyncPivot")
+)
+
 // stateReq represents a batch of state fetch requests groupped together into
 // a single data retrieval network packet.
 type stateReq struct {
@@ -52,11 +75,134 @@ func (req *stateReq) timedOut() bool {
 // sync to RPC requests as well as to display in user logs.
 type stateSyncStats struct {
 	processed  uint64 // Number of state entries processed
+	bytes      uint64 // Number of state bytes processed
+	resumed    uint64 // Number of state entries restored from the journal
 	duplicate  uint64 // Number of state entries downloaded twice
 	unexpected uint64 // Number of non-requested state entries received
 	pending    uint64 // Number of still pending state entries
 }
 
+// storedStateSyncStats is the persisted form of the stats, used to continue the
+// progress report of an interrupted sync.
+type storedStateSyncStats struct {
+	Processed uint64
+	Bytes     uint64
+}
+
+// stateJournalKey returns the database key of a journalled state entry.
+func stateJournalKey(hash common.Hash) []byte {
+	return append(append([]byte{}, stateJournalPrefix...), hash[:]...)
+}
+
+// loadStateSyncStats restores the progress stats of an interrupted sync.
+func (d *Downloader) loadStateSyncStats() {
+	blob, err := d.stateDB.Get(stateSyncStatsKey)
+	if err != nil {
+		return
+	}
+	var stored storedStateSyncStats
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		log.Warn("Invalid state sync progress in database", "err", err)
+		return
+	}
+	d.syncStatsLock.Lock()
+	d.syncStatsState.processed = stored.Processed
+	d.syncStatsState.bytes = stored.Bytes
+	d.syncStatsLock.Unlock()
+
+	log.Info("Resuming state sync", "processed", stored.Processed, "bytes", common.StorageSize(stored.Bytes))
+}
+
+// stateJournalIndexKey returns the database key of a journal index batch.
+func stateJournalIndexKey(num uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, num)
+	return append(append([]byte{}, stateJournalIndexPrefix...), enc...)
+}
+
+// stateJournalIndexCount returns the number of journal index batches written.
+func (d *Downloader) stateJournalIndexCount() uint64 {
+	blob, err := d.stateDB.Get(stateJournalIndexCountKey)
+	if err != nil || len(blob) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(blob)
+}
+
+// indexJournal adds the hashes of a batch of journalled entries to the journal
+// index, so they can be found again when the sync progress is cleared.
+func (d *Downloader) indexJournal(b ethdb.Putter, hashes []common.Hash) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	blob := make([]byte, 0, len(hashes)*common.HashLength)
+	for _, hash := range hashes {
+		blob = append(blob, hash[:]...)
+	}
+	num := d.stateJournalIndexCount()
+	if err := b.Put(stateJournalIndexKey(num), blob); err != nil {
+		return err
+	}
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, num+1)
+	return b.Put(stateJournalIndexCountKey, enc)
+}
+
+// storedFastSyncPivot returns the pivot block number of an unfinished fast sync.
+func (d *Downloader) storedFastSyncPivot() (uint64, bool) {
+	blob, err := d.stateDB.Get(fastSyncPivotKey)
+	if err != nil || len(blob) != 8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(blob), true
+}
+
+// storeFastSyncPivot persists the pivot block number of the running fast sync.
+func (d *Downloader) storeFastSyncPivot(pivot uint64) {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, pivot)
+	if err := d.stateDB.Put(fastSyncPivotKey, enc); err != nil {
+		log.Warn("Failed to store fast sync pivot", "err", err)
+	}
+}
+
+// clearFastSyncProgress removes all persisted progress of a finished fast sync,
+// including leftover journal entries, in a single batch.
+func (d *Downloader) clearFastSyncProgress() {
+	b := d.stateDB.NewBatch()
+	b.Delete(fastSyncPivotKey)
+	b.Delete(stateSyncStatsKey)
+
+	count := d.stateJournalIndexCount()
+	for num := uint64(0); num < count; num++ {
+		hashes, err := d.stateDB.Get(stateJournalIndexKey(num))
+		if err != nil {
+			continue
+		}
+		for i := 0; i+common.HashLength <= len(hashes); i += common.HashLength {
+			b.Delete(stateJournalKey(common.BytesToHash(hashes[i : i+common.HashLength])))
+		}
+		b.Delete(stateJournalIndexKey(num))
+	}
+	b.Delete(stateJournalIndexCountKey)
+
+	if err := b.Write(); err != nil {
+		log.Warn("Failed to clear fast sync progress", "err", err)
+	}
+}
+
+// journalRecorder forwards trie sync commits to a database batch, recording the
+// keys of all committed entries so they are not journalled needlessly.
+type journalRecorder struct {
+	ethdb.Batch
+	keys [][]byte
+}
+
+func (r *journalRecorder) Put(key, value []byte) error {
+	r.keys = append(r.keys, common.CopyBytes(key))
+	return r.Batch.Put(key, value)
+}
+
 // syncState starts downloading state with the given root hash.
 func (d *Downloader) syncState(root common.Hash) *stateSync {
 	s := newStateSync(d, root)
@@ -220,6 +366,8 @@ type stateSync struct {
 
 	numUncommitted   int
 	bytesUncommitted int
+	journal          map[common.Hash][]byte   // Downloaded entries not yet in the journal
+	journalled       map[common.Hash]struct{} // Uncommitted entries already in the journal
 
 	deliver    chan *stateReq // Delivery channel multiplexing peer responses
 	cancel     chan struct{}  // Channel to signal a termination request
@@ -238,13 +386,15 @@ type stateTask struct {
 // yet start the sync. The user needs to call run to initiate.
 func newStateSync(d *Downloader, root common.Hash) *stateSync {
 	return &stateSync{
-		d:       d,
-		sched:   state.NewStateSync(root, d.stateDB),
-		keccak:  sha3.NewKeccak256(),
-		tasks:   make(map[common.Hash]*stateTask),
-		deliver: make(chan *stateReq),
-		cancel:  make(chan struct{}),
-		done:    make(chan struct{}),
+		d:          d,
+		sched:      state.NewStateSync(root, d.stateDB),
+		keccak:     sha3.NewKeccak256(),
+		tasks:      make(map[common.Hash]*stateTask),
+		journal:    make(map[common.Hash][]byte),
+		journalled: make(map[common.Hash]struct{}),
+		deliver:    make(chan *stateReq),
+		cancel:     make(chan struct{}),
+		done:       make(chan struct{}),
 	}
 }
 
@@ -285,13 +435,24 @@ func (s *stateSync) loop() error {
 		if err := s.commit(false); err != nil {
 			return err
 		}
-		s.assignTasks()
-		// Tasks assigned, wait for something to happen
+		// Tasks assigned, wait for something to happen. If entries were restored
+		// from the journal, more work may be available without waiting.
+		var resume chan struct{}
+		if s.assignTasks() {
+			resume = make(chan struct{})
+			close(resume)
+		}
 		select {
+		case <-resume:
+
 		case <-newPeer:
 			// New peer arrived, try to assign it download tasks
 
 		case <-s.cancel:
+			// Keep everything downloaded so far for the next sync.
+			if err := s.flushJournal(); err != nil {
+				log.Warn("Failed to journal state entries", "err", err)
+			}
 			return errCancelStateFetch
 
 		case req := <-s.deliver:
@@ -301,7 +462,7 @@ func (s *stateSync) loop() error {
 				// 2 items are the minimum requested, if even that times out, we've no use of
 				// this peer at the moment.
 				log.Warn("Stalling state sync, dropping peer", "peer", req.peer.id)
-				s.d.dropPeer(req.peer.id)
+				s.d.dropPeer(req.peer.id, true)
 			}
 			// Process all the received blobs and check for stale delivery
 			stale, err := s.process(req)
@@ -318,32 +479,136 @@ func (s *stateSync) loop() error {
 	return s.commit(true)
 }
 
+// commit writes the finished parts of the trie to the database. Downloaded data
+// which can't be committed yet is written to the journal along with it, so the
+// sync can be resumed if it is interrupted, and journalled entries which were
+// committed are dropped.
 func (s *stateSync) commit(force bool) error {
 	if !force && s.bytesUncommitted < ethdb.IdealBatchSize {
 		return nil
 	}
 	start := time.Now()
-	b := s.d.stateDB.NewBatch()
+	b := &journalRecorder{Batch: s.d.stateDB.NewBatch()}
 	s.sched.Commit(b)
+
+	committed := make(map[common.Hash]struct{}, len(b.keys))
+	for _, key := range b.keys {
+		if len(key) != common.HashLength {
+			continue
+		}
+		hash := common.BytesToHash(key)
+		committed[hash] = struct{}{}
+		if _, ok := s.journalled[hash]; ok {
+			if err := b.Batch.Delete(stateJournalKey(hash)); err != nil {
+				return fmt.Errorf("DB write error: %v", err)
+			}
+		}
+	}
+	hashes, err := s.writeJournal(b.Batch, committed)
+	if err != nil {
+		return fmt.Errorf("DB write error: %v", err)
+	}
+	s.d.syncStatsLock.RLock()
+	stats := storedStateSyncStats{
+		Processed: s.d.syncStatsState.processed + uint64(s.numUncommitted),
+		Bytes:     s.d.syncStatsState.bytes + uint64(s.bytesUncommitted),
+	}
+	s.d.syncStatsLock.RUnlock()
+	enc, _ := rlp.EncodeToBytes(&stats)
+	if err := b.Batch.Put(stateSyncStatsKey, enc); err != nil {
+		return fmt.Errorf("DB write error: %v", err)
+	}
 	if err := b.Write(); err != nil {
 		return fmt.Errorf("DB write error: %v", err)
 	}
-	s.updateStats(s.numUncommitted, 0, 0, time.Since(start))
+	for hash := range committed {
+		delete(s.journalled, hash)
+	}
+	s.markJournalled(hashes)
+
+	s.updateStats(s.numUncommitted, s.bytesUncommitted, 0, 0, time.Since(start))
 	s.numUncommitted = 0
 	s.bytesUncommitted = 0
 	return nil
 }
 
+// flushJournal writes all downloaded but uncommitted entries to the journal.
+func (s *stateSync) flushJournal() error {
+	if len(s.journal) == 0 {
+		return nil
+	}
+	b := s.d.stateDB.NewBatch()
+	hashes, err := s.writeJournal(b, nil)
+	if err != nil {
+		return err
+	}
+	if err := b.Write(); err != nil {
+		return err
+	}
+	s.markJournalled(hashes)
+	return nil
+}
+
+// writeJournal adds the downloaded entries, except the ones being committed, to
+// the journal and its index, returning the hashes of the journalled entries.
+func (s *stateSync) writeJournal(b ethdb.Batch, committed map[common.Hash]struct{}) ([]common.Hash, error) {
+	hashes := make([]common.Hash, 0, len(s.journal))
+	for hash, blob := range s.journal {
+		if _, ok := committed[hash]; ok {
+			continue
+		}
+		if err := b.Put(stateJournalKey(hash), blob); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := s.d.indexJournal(b, hashes); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// markJournalled records that the given entries were written to the journal and
+// resets the set of entries still waiting for it.
+func (s *stateSync) markJournalled(hashes []common.Hash) {
+	for _, hash := range hashes {
+		s.journalled[hash] = struct{}{}
+	}
+	s.journal = make(map[common.Hash][]byte)
+}
+
+// restore feeds the entry with the given hash into the trie sync if it is found
+// in the journal, returning whether it was restored.
+func (s *stateSync) restore(hash common.Hash) bool {
+	blob, err := s.d.stateDB.Get(stateJournalKey(hash))
+	if err != nil {
+		return false
+	}
+	if _, _, err := s.processNodeData(blob); err != nil {
+		// Corrupt journal entry, fetch it from the network instead.
+		s.d.stateDB.Delete(stateJournalKey(hash))
+		return false
+	}
+	s.journalled[hash] = struct{}{}
+	s.numUncommitted++
+	s.bytesUncommitted += len(blob)
+	return true
+}
+
 // assignTasks attempts to assing new tasks to all idle peers, either from the
 // batch currently being retried, or fetching new data from the trie sync itself.
-func (s *stateSync) assignTasks() {
+// It returns whether any entries were restored from the journal.
+func (s *stateSync) assignTasks() bool {
+	restored := false
 	// Iterate over all idle peers and try to assign them state fetches
 	peers, _ := s.d.peers.NodeDataIdlePeers()
 	for _, p := range peers {
 		// Assign a batch of fetches proportional to the estimated latency/bandwidth
 		cap := p.NodeDataCapacity(s.d.requestRTT())
 		req := &stateReq{peer: p, timeout: s.d.requestTTL()}
-		s.fillTasks(cap, req)
+		if s.fillTasks(cap, req) {
+			restored = true
+		}
 
 		// If the peer was assigned tasks to fetch, send the network request
 		if len(req.items) > 0 {
@@ -355,18 +620,34 @@ func (s *stateSync) assignTasks() {
 			}
 		}
 	}
+	return restored
 }
 
 // fillTasks fills the given request object with a maximum of n state download
-// tasks to send to the remote peer.
-func (s *stateSync) fillTasks(n int, req *stateReq) {
-	// Refill available tasks from the scheduler.
-	if len(s.tasks) < n {
+// tasks to send to the remote peer. It returns whether any entries were restored
+// from the journal instead.
+func (s *stateSync) fillTasks(n int, req *stateReq) bool {
+	// Refill available tasks from the scheduler. Entries found in the journal
+	// are restored locally, which may schedule further entries.
+	restored := 0
+	for len(s.tasks) < n && s.bytesUncommitted < ethdb.IdealBatchSize {
 		new := s.sched.Missing(n - len(s.tasks))
+		if len(new) == 0 {
+			break
+		}
 		for _, hash := range new {
+			if s.restore(hash) {
+				restored++
+				continue
+			}
 			s.tasks[hash] = &stateTask{make(map[string]struct{})}
 		}
 	}
+	if restored > 0 {
+		s.d.syncStatsLock.Lock()
+		s.d.syncStatsState.resumed += uint64(restored)
+		s.d.syncStatsLock.Unlock()
+	}
 	// Find tasks that haven't been tried with the request's peer.
 	req.items = make([]common.Hash, 0, n)
 	req.tasks = make(map[common.Hash]*stateTask, n)
@@ -385,6 +666,7 @@ func (s *stateSync) fillTasks(n int, req *stateReq) {
 		req.tasks[hash] = t
 		delete(s.tasks, hash)
 	}
+	return restored > 0
 }
 
 // process iterates over a batch of delivered state data, injecting each item
@@ -396,7 +678,7 @@ func (s *stateSync) process(req *stateReq) (bool, error) {
 
 	defer func(start time.Time) {
 		if duplicate > 0 || unexpected > 0 {
-			s.updateStats(0, duplicate, unexpected, time.Since(start))
+			s.updateStats(0, 0, duplicate, unexpected, time.Since(start))
 		}
 	}(time.Now())
 
@@ -409,6 +691,7 @@ func (s *stateSync) process(req *stateReq) (bool, error) {
 		case nil:
 			s.numUncommitted++
 			s.bytesUncommitted += len(blob)
+			s.journal[hash] = blob
 			progress = progress || prog
 		case trie.ErrNotRequested:
 			unexpected++
@@ -463,16 +746,17 @@ func (s *stateSync) processNodeData(blob []byte) (bool, common.Hash, error) {
 
 // updateStats bumps the various state sync progress counters and displays a log
 // message for the user to see.
-func (s *stateSync) updateStats(written, duplicate, unexpected int, duration time.Duration) {
+func (s *stateSync) updateStats(written, bytes, duplicate, unexpected int, duration time.Duration) {
 	s.d.syncStatsLock.Lock()
 	defer s.d.syncStatsLock.Unlock()
 
 	s.d.syncStatsState.pending = uint64(s.sched.Pending())
 	s.d.syncStatsState.processed += uint64(written)
+	s.d.syncStatsState.bytes += uint64(bytes)
 	s.d.syncStatsState.duplicate += uint64(duplicate)
 	s.d.syncStatsState.unexpected += uint64(unexpected)
 
 	if written > 0 || duplicate > 0 || unexpected > 0 {
-		log.Info("Imported new state entries", "count", written, "elapsed", common.PrettyDuration(duration), "processed", s.d.syncStatsState.processed, "pending", s.d.syncStatsState.pending, "retry", len(s.tasks), "duplicate", s.d.syncStatsState.duplicate, "unexpected", s.d.syncStatsState.unexpected)
+		log.Info("Imported new state entries", "count", written, "elapsed", common.PrettyDuration(duration), "processed", s.d.syncStatsState.processed, "bytes", common.StorageSize(s.d.syncStatsState.bytes), "resumed", s.d.syncStatsState.resumed, "pending", s.d.syncStatsState.pending, "retry", len(s.tasks), "duplicate", s.d.syncStatsState.duplicate, "unexpected", s.d.syncStatsState.unexpected)
 	}
 }
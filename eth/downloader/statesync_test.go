@@ -0,0 +1,116 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// Tests that a state sync restores entries left in the journal by an earlier
+// sync instead of requesting them from the network.
+func TestStateSyncJournal(t *testing.T) {
+	// Create a state to sync and move all of it into the journal.
+	srcdb, _ := ethdb.NewMemDatabase()
+	src, _ := state.New(common.Hash{}, state.NewDatabase(srcdb))
+	for i := byte(0); i < 100; i++ {
+		src.AddBalance(common.Address{i}, big.NewInt(int64(i)+1))
+	}
+	root, err := src.CommitTo(srcdb, false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	var (
+		db, _ = ethdb.NewMemDatabase()
+		nodes []common.Hash
+	)
+	for _, key := range srcdb.Keys() {
+		if len(key) != common.HashLength {
+			continue // skip preimages
+		}
+		blob, _ := srcdb.Get(key)
+		db.Put(stateJournalKey(common.BytesToHash(key)), blob)
+		nodes = append(nodes, common.BytesToHash(key))
+	}
+	entries := len(nodes)
+
+	// Sync the state without any network activity.
+	d := &Downloader{stateDB: db}
+	s := newStateSync(d, root)
+	for s.sched.Pending() > 0 {
+		req := &stateReq{peer: &peerConnection{id: "peer"}}
+		if !s.fillTasks(16, req) {
+			t.Fatalf("nothing restored, %d entries pending", s.sched.Pending())
+		}
+		if len(req.items) > 0 {
+			t.Fatalf("journalled entries requested from network: %x", req.items)
+		}
+	}
+	if err := s.commit(true); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	if d.syncStatsState.resumed != uint64(entries) {
+		t.Errorf("resumed count mismatch: have %d, want %d", d.syncStatsState.resumed, entries)
+	}
+	// Check that the state is complete and the journal was cleaned up.
+	dst, err := state.New(root, state.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("failed to open synced state: %v", err)
+	}
+	for i := byte(0); i < 100; i++ {
+		if balance := dst.GetBalance(common.Address{i}); balance.Int64() != int64(i)+1 {
+			t.Errorf("account %d: balance mismatch: have %v, want %d", i, balance, i+1)
+		}
+	}
+	for _, hash := range nodes {
+		if _, err := db.Get(stateJournalKey(hash)); err == nil {
+			t.Errorf("journal entry %x not removed", hash)
+		}
+	}
+	if count := d.stateJournalIndexCount(); count != 0 {
+		t.Errorf("committed entries journalled: have %d index batches, want 0", count)
+	}
+	// Check that the progress survives a restart.
+	d2 := &Downloader{stateDB: db}
+	d2.loadStateSyncStats()
+	if d2.syncStatsState.processed != uint64(entries) {
+		t.Errorf("stored progress mismatch: have %d, want %d", d2.syncStatsState.processed, entries)
+	}
+	// Check that clearing the progress also drops the pivot and leftover journal entries.
+	d2.storeFastSyncPivot(1)
+	s.journal[common.Hash{0x01}] = []byte{0x01}
+	if err := s.flushJournal(); err != nil {
+		t.Fatalf("journal flush failed: %v", err)
+	}
+	d2.clearFastSyncProgress()
+	if _, err := db.Get(stateSyncStatsKey); err == nil {
+		t.Error("progress not removed")
+	}
+	if _, ok := d2.storedFastSyncPivot(); ok {
+		t.Error("pivot not removed")
+	}
+	if _, err := db.Get(stateJournalKey(common.Hash{0x01})); err == nil {
+		t.Error("leftover journal entry not removed")
+	}
+	if _, err := db.Get(stateJournalIndexCountKey); err == nil {
+		t.Error("journal index not removed")
+	}
+}
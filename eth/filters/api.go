@@ -18,6 +18,7 @@ package filters
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,13 +31,32 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var (
 	deadline = 5 * time.Minute // consider a filter inactive if it has not been polled for within deadline
+
+	// errReplayBacklogExceeded is returned to log subscribers whose subscription
+	// is dropped because too many live logs arrived while replaying the past.
+	errReplayBacklogExceeded = errors.New("too many live logs while replaying historical logs")
 )
 
+// defaultPageSize is the number of logs returned per page if the result count
+// is not limited by the configuration.
+const defaultPageSize = 1000
+
+// Config contains the limits of historical log queries.
+type Config struct {
+	MaxBlockRange uint64 // Maximum number of blocks searched by a single query (0 = unlimited)
+	MaxResults    int    // Maximum number of logs returned by a single query (0 = unlimited)
+}
+
+// DefaultConfig contains the default log query limits. Queries are unlimited by
+// default, operators opt in to limits to protect their nodes.
+var DefaultConfig = Config{}
+
 // filter is a helper struct that holds meta information over the filter type
 // and associated subscription in the event system.
 type filter struct {
@@ -58,11 +78,13 @@ type PublicFilterAPI struct {
 	events    *EventSystem
 	filtersMu sync.Mutex
 	filters   map[rpc.ID]*filter
+	config    Config
 }
 
 // NewPublicFilterAPI returns a new PublicFilterAPI instance.
-func NewPublicFilterAPI(backend Backend, lightMode bool) *PublicFilterAPI {
+func NewPublicFilterAPI(backend Backend, lightMode bool, config Config) *PublicFilterAPI {
 	api := &PublicFilterAPI{
+		config:  config,
 		backend: backend,
 		mux:     backend.EventMux(),
 		chainDb: backend.ChainDb(),
@@ -229,6 +251,10 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
+//
+// If the criteria start at a past block, the matching historical logs are
+// delivered first. Reorgs happening meanwhile are reported by sending the undone
+// logs again with the removed flag set.
 func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -246,12 +272,62 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 	}
 
 	go func() {
-
+		var (
+			replaying = crit.FromBlock != nil && crit.FromBlock.Sign() >= 0
+			replayed  = make(chan []*types.Log)
+			replayErr = make(chan error, 1)
+			quit      = make(chan struct{})
+			tracker   *replayTracker
+			buffered  [][]*types.Log // Live logs held back until the replay finishes
+			backlog   int            // Number of live logs held back
+
+			// The replay is only consumed once notifications reach the client
+			activated  = rpcSub.Activated()
+			replayCh   <-chan []*types.Log
+			replayDone <-chan error
+		)
+		defer close(quit)
+
+		if replaying {
+			tracker = newReplayTracker()
+			go func() { replayErr <- api.replayLogs(crit, replayed, quit) }()
+		}
 		for {
 			select {
+			case <-activated:
+				activated, replayCh, replayDone = nil, replayed, replayErr
+
+			case logs := <-replayCh:
+				tracker.replayed(logs)
+				for _, log := range logs {
+					notifier.Notify(rpcSub.ID, log)
+				}
+			case err := <-replayDone:
+				if err != nil {
+					log.Warn("Failed to replay historical logs", "from", crit.FromBlock, "err", err)
+				}
+				// Deliver the held back live logs, skipping the ones already replayed
+				for _, logs := range buffered {
+					for _, log := range tracker.live(logs) {
+						notifier.Notify(rpcSub.ID, log)
+					}
+				}
+				replaying, buffered, backlog, tracker = false, nil, 0, nil
+				replayCh, replayDone = nil, nil
+
 			case logs := <-matchedLogs:
+				if replaying {
+					if backlog += len(logs); backlog > replayBacklogLimit {
+						log.Warn("Dropping log subscription, too many live logs during replay", "from", crit.FromBlock, "limit", replayBacklogLimit)
+						notifier.Fail(rpcSub.ID, errReplayBacklogExceeded)
+						logsSub.Unsubscribe()
+						return
+					}
+					buffered = append(buffered, logs)
+					break
+				}
 				for _, log := range logs {
-					notifier.Notify(rpcSub.ID, &log)
+					notifier.Notify(rpcSub.ID, log)
 				}
 			case <-rpcSub.Err(): // client send an unsubscribe request
 				logsSub.Unsubscribe()
@@ -266,12 +342,106 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 	return rpcSub, nil
 }
 
+const (
+	// replayBatchSize is the number of blocks searched at once while replaying
+	// the historical logs of a subscription.
+	replayBatchSize = 1024
+
+	// replayBacklogLimit is the maximum number of live logs held back while the
+	// historical logs of a subscription are replayed. Subscriptions exceeding
+	// it are ended with errReplayBacklogExceeded.
+	replayBacklogLimit = 10000
+)
+
+// replayLogs delivers the historical logs matching the given criteria up to the
+// current head, in batches of blocks.
+func (api *PublicFilterAPI) replayLogs(crit FilterCriteria, ch chan<- []*types.Log, quit <-chan struct{}) error {
+	ctx := context.Background()
+
+	begin, end, err := api.resolveRange(ctx, crit)
+	if err != nil {
+		return err
+	}
+	for begin <= end {
+		last := end
+		if last-begin >= replayBatchSize {
+			last = begin + replayBatchSize - 1
+		}
+		filter := New(api.backend, int64(begin), int64(last), crit.Addresses, crit.Topics)
+		filter.tx = crit.txFilter()
+
+		logs, err := filter.Logs(ctx)
+		if err != nil {
+			return err
+		}
+		if len(logs) > 0 {
+			select {
+			case ch <- logs:
+			case <-quit:
+				return nil
+			}
+		}
+		begin = last + 1
+	}
+	return nil
+}
+
+// replayTracker tracks the blocks whose logs were delivered to a subscriber
+// during a replay, so that live logs arriving meanwhile can be reconciled.
+type replayTracker struct {
+	delivered map[common.Hash]bool
+}
+
+func newReplayTracker() *replayTracker {
+	return &replayTracker{delivered: make(map[common.Hash]bool)}
+}
+
+// replayed marks the blocks of the given historical logs as delivered.
+func (t *replayTracker) replayed(logs []*types.Log) {
+	for _, log := range logs {
+		t.delivered[log.BlockHash] = true
+	}
+}
+
+// live filters a batch of live logs. New logs of already delivered blocks are
+// dropped, just like removals of logs which were never delivered.
+func (t *replayTracker) live(logs []*types.Log) []*types.Log {
+	var (
+		ret    []*types.Log
+		update = make(map[common.Hash]bool)
+	)
+	for _, log := range logs {
+		if log.Removed == t.delivered[log.BlockHash] {
+			ret = append(ret, log)
+			update[log.BlockHash] = !log.Removed
+		}
+	}
+	for hash, delivered := range update {
+		if delivered {
+			t.delivered[hash] = true
+		} else {
+			delete(t.delivered, hash)
+		}
+	}
+	return ret
+}
+
 // FilterCriteria represents a request to create a new filter.
 type FilterCriteria struct {
 	FromBlock *big.Int
 	ToBlock   *big.Int
 	Addresses []common.Address
 	Topics    [][]common.Hash
+
+	// Optional restrictions on the transactions that created the logs
+	TxFrom   []common.Address
+	TxTo     []common.Address
+	TxHashes []common.Hash
+}
+
+// txFilter returns the transaction restrictions of the criteria.
+func (crit *FilterCriteria) txFilter() txFilter {
+	return txFilter{from: crit.TxFrom, to: crit.TxTo, hashes: crit.TxHashes}
 }
 
 // NewFilter creates a new filter and returns the filter id. It can be
@@ -323,21 +493,135 @@ func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getlogs
 func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
-	// Convert the RPC block numbers into internal representations
-	if crit.FromBlock == nil {
-		crit.FromBlock = big.NewInt(rpc.LatestBlockNumber.Int64())
+	logs, err := api.logs(ctx, crit)
+	if err != nil {
+		return nil, err
 	}
-	if crit.ToBlock == nil {
-		crit.ToBlock = big.NewInt(rpc.LatestBlockNumber.Int64())
+	return returnLogs(logs), err
+}
+
+// LogCursor is the continuation token of a paginated log query. It points at
+// the first log which has not been returned yet.
+type LogCursor struct {
+	Block uint64 // Number of the block to resume the search at
+	Index uint   // Index of the first log to return within the block
+}
+
+// MarshalText encodes the cursor as an opaque hex string.
+func (c LogCursor) MarshalText() ([]byte, error) {
+	var enc [12]byte
+	binary.BigEndian.PutUint64(enc[:8], c.Block)
+	binary.BigEndian.PutUint32(enc[8:], uint32(c.Index))
+	return hexutil.Bytes(enc[:]).MarshalText()
+}
+
+// UnmarshalText decodes a cursor created by MarshalText.
+func (c *LogCursor) UnmarshalText(input []byte) error {
+	var dec hexutil.Bytes
+	if err := dec.UnmarshalText(input); err != nil || len(dec) != 12 {
+		return errors.New("invalid log cursor")
 	}
-	// Create and run the filter to get all the logs
-	filter := New(api.backend, crit.FromBlock.Int64(), crit.ToBlock.Int64(), crit.Addresses, crit.Topics)
+	c.Block = binary.BigEndian.Uint64(dec[:8])
+	c.Index = uint(binary.BigEndian.Uint32(dec[8:]))
+	return nil
+}
+
+// LogsPage is a single page of log query results.
+type LogsPage struct {
+	Logs []*types.Log `json:"logs"`
+	Next *LogCursor   `json:"next"` // Cursor of the next page, nil if all logs were returned
+}
+
+// GetLogsPage returns a page of the logs matching the given argument. Every page
+// searches at most the configured maximum block range and contains at most the
+// configured maximum number of logs. The query is continued by passing the
+// cursor of the previous page.
+func (api *PublicFilterAPI) GetLogsPage(ctx context.Context, crit FilterCriteria, cursor *LogCursor) (*LogsPage, error) {
+	begin, end, err := api.resolveRange(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil {
+		if cursor.Block < begin {
+			return nil, errors.New("log cursor outside of the queried range")
+		}
+		begin = cursor.Block
+	}
+	page := &LogsPage{Logs: []*types.Log{}}
+	if begin > end {
+		return page, nil
+	}
+	// Limit the searched range and the number of results of this page
+	last := end
+	if max := api.config.MaxBlockRange; max > 0 && last-begin >= max {
+		last = begin + max - 1
+	}
+	limit := api.config.MaxResults
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	filter := New(api.backend, int64(begin), int64(last), crit.Addresses, crit.Topics)
+	filter.limit, filter.cursor, filter.tx = limit, cursor, crit.txFilter()
 
 	logs, err := filter.Logs(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return returnLogs(logs), err
+	switch {
+	case len(logs) >= limit:
+		logs = logs[:limit]
+		page.Next = &LogCursor{Block: logs[limit-1].BlockNumber, Index: logs[limit-1].Index + 1}
+	case last < end:
+		page.Next = &LogCursor{Block: last + 1}
+	}
+	page.Logs = returnLogs(logs)
+	return page, nil
+}
+
+// logs runs a non-paginated log query, enforcing the configured limits.
+func (api *PublicFilterAPI) logs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
+	begin, end, err := api.resolveRange(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	if max := api.config.MaxBlockRange; max > 0 && end >= begin && end-begin >= max {
+		return nil, fmt.Errorf("query spans %d blocks, more than the limit of %d, use eth_getLogsPage to paginate", end-begin+1, max)
+	}
+	// Create and run the filter to get all the logs
+	filter := New(api.backend, int64(begin), int64(end), crit.Addresses, crit.Topics)
+	filter.tx = crit.txFilter()
+	if max := api.config.MaxResults; max > 0 {
+		filter.limit = max + 1
+	}
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if max := api.config.MaxResults; max > 0 && len(logs) > max {
+		return nil, fmt.Errorf("query returned more than %d results, use eth_getLogsPage to paginate", max)
+	}
+	return logs, nil
+}
+
+// resolveRange converts the block range of the given criteria into absolute
+// block numbers. Missing and negative (latest/pending) bounds refer to the
+// current head.
+func (api *PublicFilterAPI) resolveRange(ctx context.Context, crit FilterCriteria) (uint64, uint64, error) {
+	header, err := api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if header == nil {
+		if err == nil {
+			err = errors.New("current header not found")
+		}
+		return 0, 0, err
+	}
+	begin, end := header.Number.Uint64(), header.Number.Uint64()
+	if crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 {
+		begin = crit.FromBlock.Uint64()
+	}
+	if crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 {
+		end = crit.ToBlock.Uint64()
+	}
+	return begin, end, nil
 }
 
 // UninstallFilter removes the filter with the given filter id.
@@ -369,19 +653,7 @@ func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*ty
 	if !found || f.typ != LogsSubscription {
 		return nil, fmt.Errorf("filter not found")
 	}
-
-	begin := rpc.LatestBlockNumber.Int64()
-	if f.crit.FromBlock != nil {
-		begin = f.crit.FromBlock.Int64()
-	}
-	end := rpc.LatestBlockNumber.Int64()
-	if f.crit.ToBlock != nil {
-		end = f.crit.ToBlock.Int64()
-	}
-	// Create and run the filter to get all the logs
-	filter := New(api.backend, begin, end, f.crit.Addresses, f.crit.Topics)
-
-	logs, err := filter.Logs(ctx)
+	logs, err := api.logs(ctx, f.crit)
 	if err != nil {
 		return nil, err
 	}
@@ -447,6 +719,9 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		ToBlock   *rpc.BlockNumber `json:"toBlock"`
 		Addresses interface{}      `json:"address"`
 		Topics    []interface{}    `json:"topics"`
+		TxFrom    interface{}      `json:"txFrom"`
+		TxTo      interface{}      `json:"txTo"`
+		TxHashes  []common.Hash    `json:"txHashes"`
 	}
 
 	var raw input
@@ -462,33 +737,21 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		args.ToBlock = big.NewInt(raw.ToBlock.Int64())
 	}
 
-	args.Addresses = []common.Address{}
-
-	if raw.Addresses != nil {
-		// raw.Address can contain a single address or an array of addresses
-		switch rawAddr := raw.Addresses.(type) {
-		case []interface{}:
-			for i, addr := range rawAddr {
-				if strAddr, ok := addr.(string); ok {
-					addr, err := decodeAddress(strAddr)
-					if err != nil {
-						return fmt.Errorf("invalid address at index %d: %v", i, err)
-					}
-					args.Addresses = append(args.Addresses, addr)
-				} else {
-					return fmt.Errorf("non-string address at index %d", i)
-				}
-			}
-		case string:
-			addr, err := decodeAddress(rawAddr)
-			if err != nil {
-				return fmt.Errorf("invalid address: %v", err)
-			}
-			args.Addresses = []common.Address{addr}
-		default:
-			return errors.New("invalid addresses in query")
+	var err error
+	if args.Addresses, err = decodeAddresses(raw.Addresses); err != nil {
+		return err
+	}
+	if raw.TxFrom != nil {
+		if args.TxFrom, err = decodeAddresses(raw.TxFrom); err != nil {
+			return fmt.Errorf("txFrom: %v", err)
 		}
 	}
+	if raw.TxTo != nil {
+		if args.TxTo, err = decodeAddresses(raw.TxTo); err != nil {
+			return fmt.Errorf("txTo: %v", err)
+		}
+	}
+	args.TxHashes = raw.TxHashes
 
 	// topics is an array consisting of strings and/or arrays of strings.
 	// JSON null values are converted to common.Hash{} and ignored by the filter manager.
@@ -534,6 +797,36 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// decodeAddresses decodes a single address or an array of addresses.
+func decodeAddresses(raw interface{}) ([]common.Address, error) {
+	addresses := []common.Address{}
+
+	switch rawAddr := raw.(type) {
+	case nil:
+	case []interface{}:
+		for i, addr := range rawAddr {
+			if strAddr, ok := addr.(string); ok {
+				addr, err := decodeAddress(strAddr)
+				if err != nil {
+					return nil, fmt.Errorf("invalid address at index %d: %v", i, err)
+				}
+				addresses = append(addresses, addr)
+			} else {
+				return nil, fmt.Errorf("non-string address at index %d", i)
+			}
+		}
+	case string:
+		addr, err := decodeAddress(rawAddr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address: %v", err)
+		}
+		addresses = []common.Address{addr}
+	default:
+		return nil, errors.New("invalid addresses in query")
+	}
+	return addresses, nil
+}
+
 func decodeAddress(s string) (common.Address, error) {
 	b, err := hexutil.Decode(s)
 	if err == nil && len(b) != common.AddressLength {
@@ -182,4 +182,20 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 	if len(test7.Topics[2]) != 0 {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
+
+	// test transaction criteria
+	var test8 FilterCriteria
+	vector = fmt.Sprintf(`{"txFrom": "%s", "txTo": ["%s", "%s"], "txHashes": ["%s"]}`, address0.Hex(), address0.Hex(), address1.Hex(), topic0.Hex())
+	if err := json.Unmarshal([]byte(vector), &test8); err != nil {
+		t.Fatal(err)
+	}
+	if len(test8.TxFrom) != 1 || test8.TxFrom[0] != address0 {
+		t.Fatalf("invalid txFrom, got %x", test8.TxFrom)
+	}
+	if len(test8.TxTo) != 2 || test8.TxTo[0] != address0 || test8.TxTo[1] != address1 {
+		t.Fatalf("invalid txTo, got %x", test8.TxTo)
+	}
+	if len(test8.TxHashes) != 1 || test8.TxHashes[0] != topic0 {
+		t.Fatalf("invalid txHashes, got %x", test8.TxHashes)
+	}
 }
@@ -26,14 +26,19 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type Backend interface {
 	ChainDb() ethdb.Database
+	ChainConfig() *params.ChainConfig
 	EventMux() *event.TypeMux
 	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error)
 	GetReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error)
+	GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error)
+	GetPoolTransaction(txHash common.Hash) *types.Transaction
 
 	SubscribeTxPreEvent(chan<- core.TxPreEvent) event.Subscription
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
@@ -44,6 +49,30 @@ type Backend interface {
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
 }
 
+// RemoteFilterBackend is implemented by backends able to filter the logs of blocks
+// remotely, e.g. light clients letting their servers prove the matching receipts
+// instead of retrieving all the receipts of the blocks.
+type RemoteFilterBackend interface {
+	Backend
+
+	// CanFilterLogs reports whether remote filtering is currently available.
+	CanFilterLogs() bool
+
+	// FilterLogs returns the logs of every given block matching the criteria,
+	// with all their metadata filled in.
+	FilterLogs(ctx context.Context, headers []*types.Header, addresses []common.Address, topics [][]common.Hash) ([][]*types.Log, error)
+}
+
+const (
+	// remoteFilterBatch is the maximum number of blocks filtered remotely at once.
+	remoteFilterBatch = 16
+
+	// remoteFilterLookahead is the maximum number of locally available headers
+	// scanned ahead for ones with matching blooms to filter remotely in the same
+	// batch.
+	remoteFilterLookahead = 1024
+)
+
 // Filter can be used to retrieve and filter logs.
 type Filter struct {
 	backend Backend
@@ -52,8 +81,14 @@ type Filter struct {
 	begin, end int64
 	addresses  []common.Address
 	topics     [][]common.Hash
+	tx         txFilter // Restrictions on the transactions creating the logs
+
+	limit  int        // Number of logs after which the search stops (0 = unlimited)
+	cursor *LogCursor // Position of the first log to return, if resuming a paginated query
 
 	matcher *bloombits.Matcher
+
+	prefetched map[common.Hash][]*types.Log // Logs of the blocks filtered remotely in the last batch
 }
 
 // New creates a new filter which uses a bloom filter on blocks to figure out whether
@@ -114,24 +149,33 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		err  error
 	)
 	size, sections := f.backend.BloomStatus()
-	if indexed := sections * size; indexed > uint64(f.begin) {
+	if indexed := sections * size; indexed > uint64(f.begin) && !f.localBlooms() {
 		if indexed > end {
-			logs, err = f.indexedLogs(ctx, end)
+			logs, err = f.indexedLogs(ctx, end, f.limit)
 		} else {
-			logs, err = f.indexedLogs(ctx, indexed-1)
+			logs, err = f.indexedLogs(ctx, indexed-1, f.limit)
 		}
-		if err != nil {
+		if err != nil || f.reached(f.limit, logs) {
 			return logs, err
 		}
 	}
-	rest, err := f.unindexedLogs(ctx, end)
+	limit := f.limit
+	if limit > 0 {
+		limit -= len(logs)
+	}
+	rest, err := f.unindexedLogs(ctx, end, limit)
 	logs = append(logs, rest...)
 	return logs, err
 }
 
+// reached reports whether the given logs fill up the limit.
+func (f *Filter) reached(limit int, logs []*types.Log) bool {
+	return limit > 0 && len(logs) >= limit
+}
+
 // indexedLogs returns the logs matching the filter criteria based on the bloom
 // bits indexed available locally or via the network.
-func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
+func (f *Filter) indexedLogs(ctx context.Context, end uint64, limit int) ([]*types.Log, error) {
 	// Create a matcher session and request servicing from the backend
 	matches := make(chan uint64, 64)
 
@@ -144,49 +188,90 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, err
 	f.backend.ServiceFilter(ctx, session)
 
 	// Iterate over the matches until exhausted or context closed
-	var logs []*types.Log
-
+	var (
+		logs    []*types.Log
+		pending []uint64 // Matches received but not yet checked
+		done    bool     // Whether the matcher delivered all its matches
+	)
 	for {
-		select {
-		case number, ok := <-matches:
-			// Abort if all matches have been fulfilled
-			if !ok {
+		// Wait for the next match, unless some are queued up already
+		if len(pending) == 0 {
+			if done {
 				err := session.Error()
 				if err == nil {
 					f.begin = int64(end) + 1
 				}
 				return logs, err
 			}
-			f.begin = int64(number) + 1
+			select {
+			case number, ok := <-matches:
+				if !ok {
+					done = true
+					continue
+				}
+				pending = append(pending, number)
 
-			// Retrieve the suggested block and pull any truly matching logs
-			header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
-			if header == nil || err != nil {
-				return logs, err
+			case <-ctx.Done():
+				return logs, ctx.Err()
 			}
-			found, err := f.checkMatches(ctx, header)
-			if err != nil {
-				return logs, err
+			// Filter the matches delivered so far remotely in a single batch
+			if f.remoteFilter() != nil {
+				pending, done = gatherMatches(matches, pending, remoteFilterBatch)
+				f.prefetch(ctx, f.localHeaders(pending))
 			}
-			logs = append(logs, found...)
+		}
+		number := pending[0]
+		pending = pending[1:]
+		f.begin = int64(number) + 1
 
-		case <-ctx.Done():
-			return logs, ctx.Err()
+		// Retrieve the suggested block and pull any truly matching logs
+		header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if header == nil || err != nil {
+			return logs, err
+		}
+		found, err := f.checkMatches(ctx, header)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, found...)
+		if f.reached(limit, logs) {
+			return logs, nil
+		}
+	}
+}
+
+// gatherMatches appends the matches already delivered by a matcher session to
+// pending, without waiting for new ones, until it holds limit entries. It also
+// returns whether the session delivered all its matches.
+func gatherMatches(matches chan uint64, pending []uint64, limit int) ([]uint64, bool) {
+	for len(pending) < limit {
+		select {
+		case number, ok := <-matches:
+			if !ok {
+				return pending, true
+			}
+			pending = append(pending, number)
+		default:
+			return pending, false
 		}
 	}
+	return pending, false
 }
 
-// indexedLogs returns the logs matching the filter criteria based on raw block
+// unindexedLogs returns the logs matching the filter criteria based on raw block
 // iteration and bloom matching.
-func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
+func (f *Filter) unindexedLogs(ctx context.Context, end uint64, limit int) ([]*types.Log, error) {
 	var logs []*types.Log
 
-	for ; f.begin <= int64(end); f.begin++ {
+	for ; f.begin <= int64(end) && !f.reached(limit, logs); f.begin++ {
 		header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(f.begin))
 		if header == nil || err != nil {
 			return logs, err
 		}
 		if bloomFilter(header.Bloom, f.addresses, f.topics) {
+			if _, ok := f.prefetched[header.Hash()]; !ok && f.remoteFilter() != nil {
+				f.prefetch(ctx, append([]*types.Header{header}, f.localMatches(header.Number.Uint64()+1, end)...))
+			}
 			found, err := f.checkMatches(ctx, header)
 			if err != nil {
 				return logs, err
@@ -200,22 +285,121 @@ func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, e
 // checkMatches checks if the receipts belonging to the given header contain any log events that
 // match the filter criteria. This function is called when the bloom filter signals a potential match.
 func (f *Filter) checkMatches(ctx context.Context, header *types.Header) (logs []*types.Log, err error) {
-	// Get the logs of the block
-	receipts, err := f.backend.GetReceipts(ctx, header.Hash())
-	if err != nil {
-		return nil, err
+	// Get the logs of the block, unless already filtered remotely
+	unfiltered, ok := f.prefetched[header.Hash()]
+	if !ok {
+		receipts, err := f.backend.GetReceipts(ctx, header.Hash())
+		if err != nil {
+			return nil, err
+		}
+		for _, receipt := range receipts {
+			unfiltered = append(unfiltered, receipt.Logs...)
+		}
+	}
+	// Receipts retrieved by light clients lack the log metadata
+	for _, log := range unfiltered {
+		if log.BlockHash == (common.Hash{}) {
+			log.BlockHash, log.BlockNumber = header.Hash(), header.Number.Uint64()
+		}
 	}
-	var unfiltered []*types.Log
-	for _, receipt := range receipts {
-		unfiltered = append(unfiltered, receipt.Logs...)
+	// Drop the logs already returned by a previous page
+	if c := f.cursor; c != nil && header.Number.Uint64() == c.Block {
+		for len(unfiltered) > 0 && unfiltered[0].Index < c.Index {
+			unfiltered = unfiltered[1:]
+		}
 	}
 	logs = filterLogs(unfiltered, nil, nil, f.addresses, f.topics)
+	if len(logs) > 0 && f.tx.active() {
+		cache := newTxCache(f.backend)
+		if f.tx.needsTx() {
+			if err := cache.loadBlock(ctx, header.Hash()); err != nil {
+				return nil, err
+			}
+		}
+		if logs, err = f.tx.filter(ctx, cache, logs); err != nil {
+			return nil, err
+		}
+	}
 	if len(logs) > 0 {
 		return logs, nil
 	}
 	return nil, nil
 }
 
+// prefetch filters the logs of the given blocks, whose blooms signal a potential
+// match, remotely. On failure the receipts of the blocks are retrieved and
+// filtered locally instead.
+func (f *Filter) prefetch(ctx context.Context, headers []*types.Header) {
+	if len(headers) == 0 {
+		return
+	}
+	logs, err := f.remoteFilter().FilterLogs(ctx, headers, f.addresses, f.topics)
+	if err != nil {
+		log.Debug("Failed to filter logs remotely", "from", headers[0].Number, "blocks", len(headers), "err", err)
+		return
+	}
+	f.prefetched = make(map[common.Hash][]*types.Log, len(headers))
+	for i, header := range headers {
+		f.prefetched[header.Hash()] = logs[i]
+	}
+}
+
+// remoteFilter returns the backend if it is able to filter logs remotely at the
+// moment, or nil otherwise.
+func (f *Filter) remoteFilter() RemoteFilterBackend {
+	if backend, ok := f.backend.(RemoteFilterBackend); ok && backend.CanFilterLogs() {
+		return backend
+	}
+	return nil
+}
+
+// localBlooms reports whether the blocks to search should be matched against
+// the blooms of the headers available locally instead of the bloombits. This is
+// the case for backends filtering logs remotely, which would otherwise need to
+// retrieve the bloombits from the network too, once the headers are synced.
+func (f *Filter) localBlooms() bool {
+	return f.remoteFilter() != nil && f.localHeader(uint64(f.begin)) != nil
+}
+
+// localHeader retrieves a canonical header from the local database, without
+// falling back to the network.
+func (f *Filter) localHeader(number uint64) *types.Header {
+	hash := core.GetCanonicalHash(f.db, number)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	return core.GetHeader(f.db, hash, number)
+}
+
+// localHeaders retrieves the locally available canonical headers of the given
+// blocks.
+func (f *Filter) localHeaders(numbers []uint64) []*types.Header {
+	var headers []*types.Header
+	for _, number := range numbers {
+		if header := f.localHeader(number); header != nil {
+			headers = append(headers, header)
+		}
+	}
+	return headers
+}
+
+// localMatches returns the locally available headers from begin up to end whose
+// blooms signal a potential match, enough to fill up a remote filter batch with
+// the block preceding begin.
+func (f *Filter) localMatches(begin, end uint64) []*types.Header {
+	var headers []*types.Header
+	for number := begin; number <= end && number < begin+remoteFilterLookahead && len(headers) < remoteFilterBatch-1; number++ {
+		header := f.localHeader(number)
+		if header == nil {
+			break
+		}
+		if bloomFilter(header.Bloom, f.addresses, f.topics) {
+			headers = append(headers, header)
+		}
+	}
+	return headers
+}
+
 func includes(addresses []common.Address, a common.Address) bool {
 	for _, addr := range addresses {
 		if addr == a {
@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -65,6 +66,9 @@ const (
 	logsChanSize = 10
 	// chainEvChanSize is the size of channel listening to ChainEvent.
 	chainEvChanSize = 10
+	// txLogsChanSize is the size of channel queueing logs of a subscription for
+	// the lookup of their transactions.
+	txLogsChanSize = 64
 )
 
 var (
@@ -77,6 +81,7 @@ type subscription struct {
 	created   time.Time
 	logsCrit  FilterCriteria
 	logs      chan []*types.Log
+	txLogs    chan []*types.Log // logs awaiting the lookup of their transactions (nil = no lookups)
 	hashes    chan common.Hash
 	headers   chan *types.Header
 	installed chan struct{} // closed when the filter is installed
@@ -92,6 +97,7 @@ type EventSystem struct {
 	lastHead  *types.Header
 	install   chan *subscription // install filter for event notification
 	uninstall chan *subscription // remove filter for event notification
+	stopped   chan struct{}      // closed when the event loop terminates
 }
 
 // NewEventSystem creates a new manager that listens for event on the given mux,
@@ -99,7 +105,7 @@ type EventSystem struct {
 // work loop holds its own index that is used to forward events to filters.
 //
 // The returned manager has a loop that needs to be stopped with the Stop function
-// or by stopping the given mux.
+// or by stopping the given mux. All subscriptions end when the loop stops.
 func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventSystem {
 	m := &EventSystem{
 		mux:       mux,
@@ -107,6 +113,7 @@ func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventS
 		lightMode: lightMode,
 		install:   make(chan *subscription),
 		uninstall: make(chan *subscription),
+		stopped:   make(chan struct{}),
 	}
 
 	go m.eventLoop()
@@ -139,6 +146,8 @@ func (sub *Subscription) Unsubscribe() {
 			select {
 			case sub.es.uninstall <- sub.f:
 				break uninstallLoop
+			case <-sub.es.stopped:
+				break uninstallLoop
 			case <-sub.f.logs:
 			case <-sub.f.hashes:
 			case <-sub.f.headers:
@@ -154,8 +163,12 @@ func (sub *Subscription) Unsubscribe() {
 
 // subscribe installs the subscription in the event broadcast loop.
 func (es *EventSystem) subscribe(sub *subscription) *Subscription {
-	es.install <- sub
-	<-sub.installed
+	select {
+	case es.install <- sub:
+		<-sub.installed
+	case <-es.stopped:
+		close(sub.err) // System stopped, end the subscription right away
+	}
 	return &Subscription{ID: sub.id, f: sub, es: es}
 }
 
@@ -294,14 +307,14 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 		if len(e) > 0 {
 			for _, f := range filters[LogsSubscription] {
 				if matchedLogs := filterLogs(e, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
-					f.logs <- matchedLogs
+					es.deliverLogs(f, matchedLogs)
 				}
 			}
 		}
 	case core.RemovedLogsEvent:
 		for _, f := range filters[LogsSubscription] {
 			if matchedLogs := filterLogs(e.Logs, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
-				f.logs <- matchedLogs
+				es.deliverLogs(f, matchedLogs)
 			}
 		}
 	case *event.TypeMuxEvent:
@@ -310,7 +323,7 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 			for _, f := range filters[PendingLogsSubscription] {
 				if e.Time.After(f.created) {
 					if matchedLogs := filterLogs(muxe.Logs, nil, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
-						f.logs <- matchedLogs
+						es.deliverLogs(f, matchedLogs)
 					}
 				}
 			}
@@ -326,8 +339,9 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 		if es.lightMode && len(filters[LogsSubscription]) > 0 {
 			es.lightFilterNewHead(e.Block.Header(), func(header *types.Header, remove bool) {
 				for _, f := range filters[LogsSubscription] {
-					if matchedLogs := es.lightFilterLogs(header, f.logsCrit.Addresses, f.logsCrit.Topics, remove); len(matchedLogs) > 0 {
-						f.logs <- matchedLogs
+					matchedLogs := es.lightFilterLogs(header, f.logsCrit.Addresses, f.logsCrit.Topics, remove)
+					if len(matchedLogs) > 0 {
+						es.deliverLogs(f, matchedLogs)
 					}
 				}
 			})
@@ -335,6 +349,57 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 	}
 }
 
+// deliverLogs forwards logs matching the address and topic criteria of the given
+// subscription. If their transactions need to be looked up, the logs are queued
+// for the transaction matcher of the subscription instead of blocking the event
+// loop.
+func (es *EventSystem) deliverLogs(f *subscription, logs []*types.Log) {
+	if f.txLogs != nil {
+		f.txLogs <- logs
+		return
+	}
+	if matched := es.matchTxs(f, logs); len(matched) > 0 {
+		f.logs <- matched
+	}
+}
+
+// txMatchLoop filters the queued logs of a subscription by the transactions that
+// created them, until the subscription is uninstalled.
+func (es *EventSystem) txMatchLoop(f *subscription) {
+	for {
+		select {
+		case logs := <-f.txLogs:
+			if matched := es.matchTxs(f, logs); len(matched) > 0 {
+				select {
+				case f.logs <- matched:
+				case <-f.err:
+					return
+				}
+			}
+		case <-f.err:
+			return
+		}
+	}
+}
+
+// matchTxs returns the logs created by transactions matching the criteria of
+// the given subscription.
+func (es *EventSystem) matchTxs(f *subscription, logs []*types.Log) []*types.Log {
+	txf := f.logsCrit.txFilter()
+	if !txf.active() {
+		return logs
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	matched, err := txf.filter(ctx, newTxCache(es.backend), logs)
+	if err != nil {
+		log.Debug("Failed to look up log transactions", "err", err)
+		return nil
+	}
+	return matched
+}
+
 func (es *EventSystem) lightFilterNewHead(newHeader *types.Header, callBack func(*types.Header, bool)) {
 	oldh := es.lastHead
 	es.lastHead = newHeader
@@ -418,6 +483,20 @@ func (es *EventSystem) eventLoop() {
 	defer logsSub.Unsubscribe()
 	defer chainEvSub.Unsubscribe()
 
+	// End all installed subscriptions once the system stops
+	defer func() {
+		ended := make(map[rpc.ID]bool)
+		for _, filters := range index {
+			for id, f := range filters {
+				if !ended[id] {
+					close(f.err)
+					ended[id] = true
+				}
+			}
+		}
+		close(es.stopped)
+	}()
+
 	for i := UnknownSubscription; i < LastIndexSubscription; i++ {
 		index[i] = make(map[rpc.ID]*subscription)
 	}
@@ -441,6 +520,10 @@ func (es *EventSystem) eventLoop() {
 			es.broadcast(index, ev)
 
 		case f := <-es.install:
+			if txf := f.logsCrit.txFilter(); txf.needsTx() {
+				f.txLogs = make(chan []*types.Log, txLogsChanSize)
+				go es.txMatchLoop(f)
+			}
 			if f.typ == MinedAndPendingLogsSubscription {
 				// the type are logs and pending logs subscriptions
 				index[LogsSubscription][f.id] = f
@@ -50,6 +50,10 @@ func (b *testBackend) ChainDb() ethdb.Database {
 	return b.db
 }
 
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return params.TestChainConfig
+}
+
 func (b *testBackend) EventMux() *event.TypeMux {
 	return b.mux
 }
@@ -72,6 +76,15 @@ func (b *testBackend) GetReceipts(ctx context.Context, blockHash common.Hash) (t
 	return core.GetBlockReceipts(b.db, blockHash, num), nil
 }
 
+func (b *testBackend) GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error) {
+	num := core.GetBlockNumber(b.db, blockHash)
+	return core.GetBlock(b.db, blockHash, num), nil
+}
+
+func (b *testBackend) GetPoolTransaction(txHash common.Hash) *types.Transaction {
+	return nil
+}
+
 func (b *testBackend) SubscribeTxPreEvent(ch chan<- core.TxPreEvent) event.Subscription {
 	return b.txFeed.Subscribe(ch)
 }
@@ -135,7 +148,7 @@ func TestBlockSubscription(t *testing.T) {
 		logsFeed    = new(event.Feed)
 		chainFeed   = new(event.Feed)
 		backend     = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
-		api         = NewPublicFilterAPI(backend, false)
+		api         = NewPublicFilterAPI(backend, false, Config{})
 		genesis     = new(core.Genesis).MustCommit(db)
 		chain, _    = core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {})
 		chainEvents = []core.ChainEvent{}
@@ -192,7 +205,7 @@ func TestPendingTxFilter(t *testing.T) {
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
 		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
-		api        = NewPublicFilterAPI(backend, false)
+		api        = NewPublicFilterAPI(backend, false, Config{})
 
 		transactions = []*types.Transaction{
 			types.NewTransaction(0, common.HexToAddress("0xb794f5ea0ba39494ce83a213fffba74279579268"), new(big.Int), 0, new(big.Int), nil),
@@ -255,7 +268,7 @@ func TestLogFilterCreation(t *testing.T) {
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
 		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
-		api        = NewPublicFilterAPI(backend, false)
+		api        = NewPublicFilterAPI(backend, false, Config{})
 
 		testCases = []struct {
 			crit    FilterCriteria
@@ -304,7 +317,7 @@ func TestInvalidLogFilterCreation(t *testing.T) {
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
 		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
-		api        = NewPublicFilterAPI(backend, false)
+		api        = NewPublicFilterAPI(backend, false, Config{})
 	)
 
 	// different situations where log filter creation should fail.
@@ -334,7 +347,7 @@ func TestLogFilter(t *testing.T) {
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
 		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
-		api        = NewPublicFilterAPI(backend, false)
+		api        = NewPublicFilterAPI(backend, false, Config{})
 
 		firstAddr      = common.HexToAddress("0x1111111111111111111111111111111111111111")
 		secondAddr     = common.HexToAddress("0x2222222222222222222222222222222222222222")
@@ -453,7 +466,7 @@ func TestPendingLogsSubscription(t *testing.T) {
 		logsFeed   = new(event.Feed)
 		chainFeed  = new(event.Feed)
 		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
-		api        = NewPublicFilterAPI(backend, false)
+		api        = NewPublicFilterAPI(backend, false, Config{})
 
 		firstAddr      = common.HexToAddress("0x1111111111111111111111111111111111111111")
 		secondAddr     = common.HexToAddress("0x2222222222222222222222222222222222222222")
@@ -558,3 +571,105 @@ func TestPendingLogsSubscription(t *testing.T) {
 		}
 	}
 }
+
+// TestLogsSubscriptionReplay tests that log subscriptions starting at a past
+// block first deliver the historical logs and then switch to live logs.
+func TestLogsSubscriptionReplay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux        = new(event.TypeMux)
+		db, _      = ethdb.NewMemDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false, Config{})
+		contract   = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		topic      = common.HexToHash("0x2222222222222222222222222222222222222222222222222222222222222222")
+		genesis    = new(core.Genesis).MustCommit(db)
+	)
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 3, func(i int, gen *core.BlockGen) {
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{{Address: contract, Topics: []common.Hash{topic}, Data: []byte{byte(i)}, BlockNumber: uint64(i + 1)}}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		gen.AddUncheckedReceipt(receipt)
+	})
+	for i, block := range chain {
+		core.WriteBlock(db, block)
+		core.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		core.WriteHeadBlockHash(db, block.Hash())
+		core.WriteBlockReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	ch := make(chan types.Log)
+	sub, err := client.EthSubscribe(context.Background(), ch, "logs", map[string]interface{}{"fromBlock": "0x1", "address": contract})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	// The historical logs must be delivered first, followed by the live ones
+	live := &types.Log{Address: contract, Topics: []common.Hash{topic}, Data: []byte{3}, BlockNumber: 4, BlockHash: common.HexToHash("0x04")}
+	for i := 0; i < 4; i++ {
+		if i == 3 {
+			logsFeed.Send([]*types.Log{live})
+		}
+		select {
+		case log := <-ch:
+			if log.BlockNumber != uint64(i+1) || log.Data[0] != byte(i) {
+				t.Fatalf("log %d: mismatch: have block %d data %x", i, log.BlockNumber, log.Data)
+			}
+			if i < 3 && log.BlockHash != chain[i].Hash() {
+				t.Errorf("log %d: block hash mismatch: have %x, want %x", i, log.BlockHash, chain[i].Hash())
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(time.Second):
+			t.Fatalf("log %d: timeout", i)
+		}
+	}
+}
+
+// TestReplayTracker tests the reconciliation of live logs arriving while the
+// historical logs of a subscription are replayed.
+func TestReplayTracker(t *testing.T) {
+	var (
+		blockA  = common.HexToHash("0x0a")
+		blockB  = common.HexToHash("0x0b")
+		blockC  = common.HexToHash("0x0c")
+		tracker = newReplayTracker()
+	)
+	tracker.replayed([]*types.Log{{BlockHash: blockA, Index: 0}, {BlockHash: blockA, Index: 1}, {BlockHash: blockC}})
+
+	tests := []struct {
+		logs []*types.Log
+		want int
+	}{
+		// Logs of replayed blocks must not be delivered twice
+		{[]*types.Log{{BlockHash: blockA, Index: 0}, {BlockHash: blockA, Index: 1}}, 0},
+		// Removals of blocks which were never delivered must be dropped
+		{[]*types.Log{{BlockHash: blockB, Removed: true}}, 0},
+		// Removals of replayed blocks must be delivered, all of them
+		{[]*types.Log{{BlockHash: blockA, Index: 0, Removed: true}, {BlockHash: blockA, Index: 1, Removed: true}}, 2},
+		// New logs of unknown blocks must be delivered
+		{[]*types.Log{{BlockHash: blockB}}, 1},
+		// Removed blocks reappearing must be delivered again
+		{[]*types.Log{{BlockHash: blockA, Index: 0}, {BlockHash: blockC}}, 1},
+		// Live blocks removed later must be reported
+		{[]*types.Log{{BlockHash: blockB, Removed: true}}, 1},
+	}
+	for i, tt := range tests {
+		if have := tracker.live(tt.logs); len(have) != tt.want {
+			t.Errorf("test %d: delivered log count mismatch: have %d, want %d", i, len(have), tt.want)
+		}
+	}
+}
@@ -18,19 +18,24 @@ package filters
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"io/ioutil"
 	"math/big"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 func makeReceipt(addr common.Address) *types.Receipt {
@@ -241,3 +246,334 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 }
+
+func TestGetLogsPage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filtertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		db, _      = ethdb.NewLDBDatabase(dir, 0, 0)
+		mux        = new(event.TypeMux)
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		key1, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr       = crypto.PubkeyToAddress(key1.PublicKey)
+		api        = NewPublicFilterAPI(backend, false, Config{MaxBlockRange: 5, MaxResults: 4})
+	)
+	defer db.Close()
+
+	// Create a chain with three logs in each block
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 20, func(i int, gen *core.BlockGen) {
+		receipt := types.NewReceipt(nil, false, 0)
+		for j := 0; j < 3; j++ {
+			receipt.Logs = append(receipt.Logs, &types.Log{Address: addr, BlockNumber: uint64(i + 1), Index: uint(j)})
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		gen.AddUncheckedReceipt(receipt)
+	})
+	for i, block := range chain {
+		core.WriteBlock(db, block)
+		if err := core.WriteCanonicalHash(db, block.Hash(), block.NumberU64()); err != nil {
+			t.Fatalf("failed to insert block number: %v", err)
+		}
+		if err := core.WriteHeadBlockHash(db, block.Hash()); err != nil {
+			t.Fatalf("failed to insert block number: %v", err)
+		}
+		if err := core.WriteBlockReceipts(db, block.Hash(), block.NumberU64(), receipts[i]); err != nil {
+			t.Fatal("error writing block receipts:", err)
+		}
+	}
+	// Non-paginated queries must respect the configured limits
+	crit := FilterCriteria{FromBlock: big.NewInt(0), Addresses: []common.Address{addr}}
+	if _, err := api.GetLogs(context.Background(), crit); err == nil {
+		t.Errorf("no error for query exceeding the block range")
+	}
+	if _, err := api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(1), ToBlock: big.NewInt(2)}); err == nil {
+		t.Errorf("no error for query exceeding the result count")
+	}
+	if logs, err := api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(20)}); err != nil || len(logs) != 3 {
+		t.Errorf("small query failed: %d logs, error %v", len(logs), err)
+	}
+	// Paginated queries must return every log exactly once
+	var (
+		cursor *LogCursor
+		logs   []*types.Log
+	)
+	for pages := 0; ; pages++ {
+		if pages > 20 {
+			t.Fatalf("pagination doesn't terminate")
+		}
+		page, err := api.GetLogsPage(context.Background(), crit, cursor)
+		if err != nil {
+			t.Fatalf("failed to retrieve page %d: %v", pages, err)
+		}
+		if len(page.Logs) > 4 {
+			t.Fatalf("page %d exceeds the result limit: %d logs", pages, len(page.Logs))
+		}
+		logs = append(logs, page.Logs...)
+		if page.Next == nil {
+			break
+		}
+		// Round trip the cursor through its textual representation
+		text, err := page.Next.MarshalText()
+		if err != nil {
+			t.Fatalf("failed to encode cursor: %v", err)
+		}
+		cursor = new(LogCursor)
+		if err := cursor.UnmarshalText(text); err != nil {
+			t.Fatalf("failed to decode cursor %q: %v", text, err)
+		}
+	}
+	if len(logs) != 60 {
+		t.Fatalf("log count mismatch: have %d, want %d", len(logs), 60)
+	}
+	for i, log := range logs {
+		if log.BlockNumber != uint64(i/3+1) || log.Index != uint(i%3) {
+			t.Errorf("log %d: position mismatch: have %d/%d, want %d/%d", i, log.BlockNumber, log.Index, i/3+1, i%3)
+		}
+	}
+}
+
+func TestFilterTransactions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filtertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		db, _      = ethdb.NewLDBDatabase(dir, 0, 0)
+		mux        = new(event.TypeMux)
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false, Config{})
+		key1, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		key2, _    = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		addr1      = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2      = crypto.PubkeyToAddress(key2.PublicKey)
+		recipient1 = common.BytesToAddress([]byte("recipient1"))
+		recipient2 = common.BytesToAddress([]byte("recipient2"))
+		contract   = common.BytesToAddress([]byte("contract"))
+	)
+	defer db.Close()
+
+	// Create a chain where every block contains one transaction from each sender,
+	// to a different recipient each, and every transaction creates a log
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			addr1: {Balance: big.NewInt(1000000)},
+			addr2: {Balance: big.NewInt(1000000)},
+		},
+	}
+	genesis := gspec.MustCommit(db)
+
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 4, func(i int, gen *core.BlockGen) {
+		signer := types.MakeSigner(params.TestChainConfig, gen.Number())
+		for j, key := range []*ecdsa.PrivateKey{key1, key2} {
+			to := recipient1
+			if (i+j)%2 == 1 {
+				to = recipient2
+			}
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(crypto.PubkeyToAddress(key.PublicKey)), to, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+			gen.AddTx(tx)
+
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = []*types.Log{{Address: contract, TxHash: tx.Hash(), BlockNumber: uint64(i + 1)}}
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			gen.AddUncheckedReceipt(receipt)
+		}
+	})
+	var logs []*types.Log
+	for i, block := range chain {
+		core.WriteBlock(db, block)
+		if err := core.WriteCanonicalHash(db, block.Hash(), block.NumberU64()); err != nil {
+			t.Fatalf("failed to insert block number: %v", err)
+		}
+		if err := core.WriteHeadBlockHash(db, block.Hash()); err != nil {
+			t.Fatalf("failed to insert block number: %v", err)
+		}
+		if err := core.WriteBlockReceipts(db, block.Hash(), block.NumberU64(), receipts[i]); err != nil {
+			t.Fatal("error writing block receipts:", err)
+		}
+		for _, receipt := range receipts[i] {
+			for _, log := range receipt.Logs {
+				logcopy := *log
+				logcopy.BlockHash = block.Hash()
+				logs = append(logs, &logcopy)
+			}
+		}
+	}
+	txHash := chain[2].Transactions()[1].Hash()
+
+	testCases := []struct {
+		crit FilterCriteria
+		want []common.Hash
+	}{
+		{
+			FilterCriteria{TxFrom: []common.Address{addr1}},
+			[]common.Hash{chain[0].Transactions()[0].Hash(), chain[1].Transactions()[0].Hash(), chain[2].Transactions()[0].Hash(), chain[3].Transactions()[0].Hash()},
+		},
+		{
+			FilterCriteria{TxTo: []common.Address{recipient2}},
+			[]common.Hash{chain[0].Transactions()[1].Hash(), chain[1].Transactions()[0].Hash(), chain[2].Transactions()[1].Hash(), chain[3].Transactions()[0].Hash()},
+		},
+		{
+			FilterCriteria{TxFrom: []common.Address{addr2}, TxTo: []common.Address{recipient1}},
+			[]common.Hash{chain[1].Transactions()[1].Hash(), chain[3].Transactions()[1].Hash()},
+		},
+		{
+			FilterCriteria{TxHashes: []common.Hash{txHash}},
+			[]common.Hash{txHash},
+		},
+		{
+			FilterCriteria{TxFrom: []common.Address{addr1}, TxHashes: []common.Hash{txHash}},
+			nil,
+		},
+	}
+	for i, tc := range testCases {
+		// Check historical queries
+		tc.crit.FromBlock, tc.crit.ToBlock = big.NewInt(0), big.NewInt(-1)
+		found, err := api.GetLogs(context.Background(), tc.crit)
+		if err != nil {
+			t.Fatalf("test %d: failed to retrieve logs: %v", i, err)
+		}
+		if have := logTxHashes(found); !reflect.DeepEqual(have, tc.want) {
+			t.Errorf("test %d: historical log mismatch: have %x, want %x", i, have, tc.want)
+		}
+		// Check live subscriptions
+		ch := make(chan []*types.Log, 1)
+		tc.crit.FromBlock, tc.crit.ToBlock = nil, nil
+		sub, err := api.events.SubscribeLogs(tc.crit, ch)
+		if err != nil {
+			t.Fatalf("test %d: failed to subscribe: %v", i, err)
+		}
+		logsFeed.Send(logs)
+
+		var live []*types.Log
+		select {
+		case live = <-ch:
+		case <-time.After(100 * time.Millisecond):
+		}
+		sub.Unsubscribe()
+		if have := logTxHashes(live); !reflect.DeepEqual(have, tc.want) {
+			t.Errorf("test %d: live log mismatch: have %x, want %x", i, have, tc.want)
+		}
+	}
+}
+
+func logTxHashes(logs []*types.Log) []common.Hash {
+	var hashes []common.Hash
+	for _, log := range logs {
+		hashes = append(hashes, log.TxHash)
+	}
+	return hashes
+}
+
+// remoteTestBackend is a test backend filtering logs "remotely", counting the
+// number of remote requests, of the blocks whose headers or receipts were
+// retrieved and of the bloombits retrievals.
+type remoteTestBackend struct {
+	*testBackend
+	available bool
+
+	requests  int
+	headers   int
+	receipts  int
+	bloombits int
+}
+
+func (b *remoteTestBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
+	b.headers++
+	return b.testBackend.HeaderByNumber(ctx, blockNr)
+}
+
+func (b *remoteTestBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
+	b.bloombits++
+	b.testBackend.ServiceFilter(ctx, session)
+}
+
+func (b *remoteTestBackend) GetReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error) {
+	b.receipts++
+	return b.testBackend.GetReceipts(ctx, blockHash)
+}
+
+func (b *remoteTestBackend) CanFilterLogs() bool {
+	return b.available
+}
+
+func (b *remoteTestBackend) FilterLogs(ctx context.Context, headers []*types.Header, addresses []common.Address, topics [][]common.Hash) ([][]*types.Log, error) {
+	b.requests++
+
+	logs := make([][]*types.Log, len(headers))
+	for i, header := range headers {
+		receipts := core.GetBlockReceipts(b.db, header.Hash(), header.Number.Uint64())
+		for _, receipt := range receipts {
+			logs[i] = append(logs[i], filterLogs(receipt.Logs, nil, nil, addresses, topics)...)
+		}
+	}
+	return logs, nil
+}
+
+// Tests that backends able to filter logs remotely are asked to filter the blocks
+// with matching blooms in batches instead of retrieving their receipts.
+func TestRemoteFilter(t *testing.T) {
+	var (
+		db, _   = ethdb.NewMemDatabase()
+		backend = &remoteTestBackend{testBackend: &testBackend{new(event.TypeMux), db, 0, new(event.Feed), new(event.Feed), new(event.Feed), new(event.Feed)}}
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		topic   = common.BytesToHash([]byte("topic"))
+	)
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 100, func(i int, gen *core.BlockGen) {
+		if i%10 == 9 {
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = []*types.Log{{Address: addr, Topics: []common.Hash{topic}, BlockNumber: uint64(i + 1)}}
+			gen.AddUncheckedReceipt(receipt)
+		}
+	})
+	for i, block := range chain {
+		core.WriteBlock(db, block)
+		core.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		core.WriteHeadBlockHash(db, block.Hash())
+		core.WriteBlockReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	// Without remote filtering available the receipts are retrieved
+	logs, err := New(backend, 0, -1, []common.Address{addr}, nil).Logs(context.Background())
+	if err != nil {
+		t.Fatalf("failed to filter logs: %v", err)
+	}
+	if len(logs) != 10 || backend.requests != 0 || backend.receipts != 10 {
+		t.Errorf("local filtering mismatch: have %d logs, %d requests, %d receipts, want 10, 0, 10", len(logs), backend.requests, backend.receipts)
+	}
+	// With remote filtering the blocks are filtered in batches, matching the
+	// blooms of the local headers even if bloombits are available
+	backend.available, backend.sections, backend.receipts, backend.headers = true, 1, 0, 0
+
+	logs, err = New(backend, 0, -1, []common.Address{addr}, [][]common.Hash{{topic}}).Logs(context.Background())
+	if err != nil {
+		t.Fatalf("failed to filter logs: %v", err)
+	}
+	if len(logs) != 10 || backend.requests != 1 || backend.receipts != 0 || backend.bloombits != 0 {
+		t.Errorf("remote filtering mismatch: have %d logs, %d requests, %d receipts, %d bloombits retrievals, want 10, 1, 0, 0", len(logs), backend.requests, backend.receipts, backend.bloombits)
+	}
+	if backend.headers > 102 {
+		t.Errorf("too many headers retrieved: have %d, want at most %d", backend.headers, 102)
+	}
+	for i, log := range logs {
+		if log.BlockNumber != uint64(i*10+10) {
+			t.Errorf("log %d: block number mismatch: have %d, want %d", i, log.BlockNumber, i*10+10)
+		}
+	}
+}
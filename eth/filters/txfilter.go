@@ -0,0 +1,140 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// txFilter restricts logs to the ones created by particular transactions.
+type txFilter struct {
+	from   []common.Address // Accepted transaction senders (empty = any)
+	to     []common.Address // Accepted transaction recipients (empty = any)
+	hashes []common.Hash    // Accepted transaction hashes (empty = any)
+}
+
+// active reports whether the filter restricts anything at all.
+func (f *txFilter) active() bool {
+	return len(f.from) > 0 || len(f.to) > 0 || len(f.hashes) > 0
+}
+
+// needsTx reports whether the transactions of the logs must be retrieved.
+func (f *txFilter) needsTx() bool {
+	return len(f.from) > 0 || len(f.to) > 0
+}
+
+// filter returns the logs created by transactions matching the filter.
+func (f *txFilter) filter(ctx context.Context, cache *txCache, logs []*types.Log) ([]*types.Log, error) {
+	if !f.active() {
+		return logs, nil
+	}
+	var ret []*types.Log
+	for _, log := range logs {
+		if len(f.hashes) > 0 && !includesHash(f.hashes, log.TxHash) {
+			continue
+		}
+		if f.needsTx() {
+			tx, err := cache.transaction(ctx, log)
+			if err != nil {
+				return nil, err
+			}
+			if tx == nil {
+				continue
+			}
+			if len(f.to) > 0 && (tx.To() == nil || !includes(f.to, *tx.To())) {
+				continue
+			}
+			if len(f.from) > 0 {
+				sender, err := cache.sender(tx, log.BlockNumber)
+				if err != nil || !includes(f.from, sender) {
+					continue
+				}
+			}
+		}
+		ret = append(ret, log)
+	}
+	return ret, nil
+}
+
+func includesHash(hashes []common.Hash, h common.Hash) bool {
+	for _, hash := range hashes {
+		if hash == h {
+			return true
+		}
+	}
+	return false
+}
+
+// txCache retrieves the transactions which created logs, fetching the body of
+// every block at most once. The caches are only allocated once used.
+type txCache struct {
+	backend Backend
+	txs     map[common.Hash]*types.Transaction
+	blocks  map[common.Hash]bool
+}
+
+func newTxCache(backend Backend) *txCache {
+	return &txCache{backend: backend}
+}
+
+// loadBlock caches all transactions of the given block.
+func (c *txCache) loadBlock(ctx context.Context, hash common.Hash) error {
+	if c.blocks[hash] {
+		return nil
+	}
+	block, err := c.backend.GetBlock(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if c.blocks == nil {
+		c.blocks = make(map[common.Hash]bool)
+		c.txs = make(map[common.Hash]*types.Transaction)
+	}
+	c.blocks[hash] = true
+	if block != nil {
+		for _, tx := range block.Transactions() {
+			c.txs[tx.Hash()] = tx
+		}
+	}
+	return nil
+}
+
+// transaction returns the transaction which created the given log, or nil if
+// it cannot be found. Logs of pending blocks are resolved via the pool.
+func (c *txCache) transaction(ctx context.Context, log *types.Log) (*types.Transaction, error) {
+	if tx, ok := c.txs[log.TxHash]; ok {
+		return tx, nil
+	}
+	if log.BlockHash == (common.Hash{}) {
+		return c.backend.GetPoolTransaction(log.TxHash), nil
+	}
+	if err := c.loadBlock(ctx, log.BlockHash); err != nil {
+		return nil, err
+	}
+	return c.txs[log.TxHash], nil
+}
+
+// sender recovers the sender of a transaction with the signer of the block it
+// was included in.
+func (c *txCache) sender(tx *types.Transaction, number uint64) (common.Address, error) {
+	signer := types.MakeSigner(c.backend.ChainConfig(), new(big.Int).SetUint64(number))
+	return types.Sender(signer, tx)
+}
@@ -99,7 +99,7 @@ type ProtocolManager struct {
 
 // NewProtocolManager returns a new ethereum sub protocol manager. The Ethereum sub protocol manages peers capable
 // with the ethereum network.
-func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, networkId uint64, mux *event.TypeMux, txpool txPool, engine consensus.Engine, blockchain *core.BlockChain, chaindb ethdb.Database) (*ProtocolManager, error) {
+func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, checkpoint *downloader.Checkpoint, networkId uint64, mux *event.TypeMux, txpool txPool, engine consensus.Engine, blockchain *core.BlockChain, chaindb ethdb.Database) (*ProtocolManager, error) {
 	// Create the protocol manager with the base fields
 	manager := &ProtocolManager{
 		networkId:   networkId,
@@ -161,7 +161,7 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		return nil, errIncompatibleConfig
 	}
 	// Construct the different synchronisation mechanisms
-	manager.downloader = downloader.New(mode, chaindb, manager.eventMux, blockchain, nil, manager.removePeer)
+	manager.downloader = downloader.New(mode, checkpoint, chaindb, manager.eventMux, blockchain, nil, manager.dropSyncPeer)
 
 	validator := func(header *types.Header) error {
 		return engine.VerifyHeader(blockchain, header, true)
@@ -178,7 +178,7 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		atomic.StoreUint32(&manager.acceptTxs, 1) // Mark initial sync done on any fetcher import
 		return manager.blockchain.InsertChain(blocks)
 	}
-	manager.fetcher = fetcher.New(blockchain.GetBlockByHash, validator, manager.BroadcastBlock, heighter, inserter, manager.removePeer)
+	manager.fetcher = fetcher.New(blockchain.GetBlockByHash, validator, manager.BroadcastBlock, heighter, inserter, manager.penalisePeer)
 
 	return manager, nil
 }
@@ -202,6 +202,30 @@ func (pm *ProtocolManager) removePeer(id string) {
 	}
 }
 
+// penalisePeer lowers the reputation of a peer caught misbehaving (e.g. by
+// delivering an invalid chain) before dropping it.
+func (pm *ProtocolManager) penalisePeer(id string) {
+	pm.scoreAndRemovePeer(id, p2p.ScoreProtocolViolation)
+}
+
+// dropSyncPeer drops a peer on behalf of the downloader, lowering its reputation
+// less if it only timed out or stalled than if it delivered invalid data.
+func (pm *ProtocolManager) dropSyncPeer(id string, timeout bool) {
+	if timeout {
+		pm.scoreAndRemovePeer(id, p2p.ScoreTimeout)
+		return
+	}
+	pm.penalisePeer(id)
+}
+
+// scoreAndRemovePeer adjusts the reputation of a peer before dropping it.
+func (pm *ProtocolManager) scoreAndRemovePeer(id string, delta int) {
+	if peer := pm.peers.Peer(id); peer != nil {
+		peer.Peer.AdjustScore(delta)
+	}
+	pm.removePeer(id)
+}
+
 func (pm *ProtocolManager) Start(maxPeers int) {
 	pm.maxPeers = maxPeers
 
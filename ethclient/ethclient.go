@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -35,6 +36,8 @@ import (
 // Client defines typed wrappers for the Ethereum RPC API.
 type Client struct {
 	c *rpc.Client
+
+	noLogsPage uint32 // Flag whether the server lacks eth_getLogsPage (atomic)
 }
 
 // Dial connects a client to the given URL.
@@ -48,7 +51,7 @@ func Dial(rawurl string) (*Client, error) {
 
 // NewClient creates a client that uses the given RPC client.
 func NewClient(c *rpc.Client) *Client {
-	return &Client{c}
+	return &Client{c: c}
 }
 
 // Blockchain Access
@@ -266,6 +269,9 @@ type rpcProgress struct {
 	HighestBlock  hexutil.Uint64
 	PulledStates  hexutil.Uint64
 	KnownStates   hexutil.Uint64
+
+	PulledStateBytes hexutil.Uint64
+	ResumedStates    hexutil.Uint64
 }
 
 // SyncProgress retrieves the current progress of the sync algorithm. If there's
@@ -290,6 +296,9 @@ func (ec *Client) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, err
 		HighestBlock:  uint64(progress.HighestBlock),
 		PulledStates:  uint64(progress.PulledStates),
 		KnownStates:   uint64(progress.KnownStates),
+
+		PulledStateBytes: uint64(progress.PulledStateBytes),
+		ResumedStates:    uint64(progress.ResumedStates),
 	}, nil
 }
 
@@ -348,11 +357,60 @@ func (ec *Client) NonceAt(ctx context.Context, account common.Address, blockNumb
 
 // Filters
 
-// FilterLogs executes a filter query.
+// FilterLogs executes a filter query. The results are retrieved page by page, so
+// queries exceeding the block range or result limits of the server still succeed.
+// Servers without pagination support are queried in a single request, which is
+// remembered so later queries don't try paginating again.
 func (ec *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
-	var result []types.Log
-	err := ec.c.CallContext(ctx, &result, "eth_getLogs", toFilterArg(q))
-	return result, err
+	var (
+		result []types.Log
+		cursor string
+	)
+	if atomic.LoadUint32(&ec.noLogsPage) == 1 {
+		err := ec.c.CallContext(ctx, &result, "eth_getLogs", toFilterArg(q))
+		return result, err
+	}
+	for {
+		logs, next, err := ec.FilterLogsPage(ctx, q, cursor)
+		if cursor == "" && isMethodNotFound(err) {
+			atomic.StoreUint32(&ec.noLogsPage, 1)
+			err = ec.c.CallContext(ctx, &result, "eth_getLogs", toFilterArg(q))
+			return result, err
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, logs...)
+		if next == "" {
+			return result, nil
+		}
+		cursor = next
+	}
+}
+
+// FilterLogsPage retrieves a single page of the results of a filter query. The
+// query is continued by passing the returned cursor to the next call, an empty
+// cursor starts the query and is returned after the last page.
+func (ec *Client) FilterLogsPage(ctx context.Context, q ethereum.FilterQuery, cursor string) ([]types.Log, string, error) {
+	var (
+		page struct {
+			Logs []types.Log `json:"logs"`
+			Next *string     `json:"next"`
+		}
+		err error
+	)
+	if cursor == "" {
+		err = ec.c.CallContext(ctx, &page, "eth_getLogsPage", toFilterArg(q))
+	} else {
+		err = ec.c.CallContext(ctx, &page, "eth_getLogsPage", toFilterArg(q), cursor)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if page.Next == nil {
+		return page.Logs, "", nil
+	}
+	return page.Logs, *page.Next, nil
 }
 
 // SubscribeFilterLogs subscribes to the results of a streaming filter query.
@@ -360,6 +418,14 @@ func (ec *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuer
 	return ec.c.EthSubscribe(ctx, ch, "logs", toFilterArg(q))
 }
 
+// errcodeMethodNotFound is the JSON-RPC error code of calls to unknown methods.
+const errcodeMethodNotFound = -32601
+
+func isMethodNotFound(err error) bool {
+	rpcErr, ok := err.(rpc.Error)
+	return ok && rpcErr.ErrorCode() == errcodeMethodNotFound
+}
+
 func toFilterArg(q ethereum.FilterQuery) interface{} {
 	arg := map[string]interface{}{
 		"fromBlock": toBlockNumArg(q.FromBlock),
@@ -103,6 +103,9 @@ type SyncProgress struct {
 	HighestBlock  uint64 // Highest alleged block number in the chain
 	PulledStates  uint64 // Number of state trie entries already downloaded
 	KnownStates   uint64 // Total number of state trie entries known about
+
+	PulledStateBytes uint64 // Number of state trie bytes already downloaded
+	ResumedStates    uint64 // Number of state trie entries reused from an interrupted sync
 }
 
 // ChainSyncReader wraps access to the node's current sync status. If there's no
@@ -0,0 +1,58 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// PrivateLightServerAPI provides an API to manage the capacity assigned to the
+// light clients of a LES server.
+type PrivateLightServerAPI struct {
+	server *LesServer
+}
+
+// NewPrivateLightServerAPI creates a new API definition for managing the light
+// clients of the given LES server.
+func NewPrivateLightServerAPI(server *LesServer) *PrivateLightServerAPI {
+	return &PrivateLightServerAPI{server: server}
+}
+
+// TotalCapacity returns the total capacity shared among all clients.
+func (api *PrivateLightServerAPI) TotalCapacity() hexutil.Uint64 {
+	return hexutil.Uint64(api.server.clientPool.total)
+}
+
+// FreeClientCapacity returns the minimum capacity guaranteed to every free
+// client. The capacity left by priority clients is split evenly among them.
+func (api *PrivateLightServerAPI) FreeClientCapacity() hexutil.Uint64 {
+	return hexutil.Uint64(api.server.clientPool.freeParams.MinRecharge)
+}
+
+// SetClientCapacity makes the client with the given node ID a priority client
+// with the given guaranteed capacity, or a free client if the capacity is zero.
+// The new capacity takes effect when the client connects next.
+func (api *PrivateLightServerAPI) SetClientCapacity(id discover.NodeID, capacity uint64) error {
+	return api.server.clientPool.setPriority(id, capacity)
+}
+
+// ClientInfo returns the capacity and the usage statistics of the connected and
+// the priority clients.
+func (api *PrivateLightServerAPI) ClientInfo() map[discover.NodeID]*ClientInfo {
+	return api.server.clientPool.clientInfo()
+}
@@ -87,6 +87,14 @@ func (b *LesApiBackend) GetReceipts(ctx context.Context, blockHash common.Hash)
 	return light.GetBlockReceipts(ctx, b.eth.odr, blockHash, core.GetBlockNumber(b.eth.chainDb, blockHash))
 }
 
+func (b *LesApiBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
+	return light.GetTransaction(ctx, b.eth.odr, txHash)
+}
+
+func (b *LesApiBackend) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return light.GetTransactionReceipt(ctx, b.eth.odr, txHash)
+}
+
 func (b *LesApiBackend) GetTd(blockHash common.Hash) *big.Int {
 	return b.eth.blockchain.GetTdByHash(blockHash)
 }
@@ -186,3 +194,20 @@ func (b *LesApiBackend) ServiceFilter(ctx context.Context, session *bloombits.Ma
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.eth.bloomRequests)
 	}
 }
+
+// CanFilterLogs reports whether any of the connected servers is able to filter
+// logs remotely.
+func (b *LesApiBackend) CanFilterLogs() bool {
+	for _, p := range b.eth.peers.AllPeers() {
+		if p.version >= lpv3 {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterLogs retrieves the logs of the given blocks matching the address and
+// topic criteria, proven by the servers against the headers.
+func (b *LesApiBackend) FilterLogs(ctx context.Context, headers []*types.Header, addresses []common.Address, topics [][]common.Hash) ([][]*types.Log, error) {
+	return light.GetFilteredLogs(ctx, b.eth.odr, headers, addresses, topics)
+}
@@ -0,0 +1,362 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/les/flowcontrol"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var priorityClientsKey = []byte("_priorityClients")
+
+var errNoPriorityCapacity = errors.New("no capacity left for priority client")
+
+// clientPool assigns the flow control capacity of the server to the connected
+// light clients. The capacity of a client is its minimum recharge rate. Priority
+// clients are guaranteed their configured capacity while connected, the rest is
+// split evenly among free clients, which are kicked out when a priority client
+// needs their capacity.
+type clientPool struct {
+	lock sync.Mutex
+	db   ethdb.Database // Database to persist the priority clients into (nil = don't persist)
+
+	total        uint64                    // Total capacity of the server
+	priorityUsed uint64                    // Capacity assigned to the connected priority clients
+	freeClients  int                       // Number of connected free clients
+	freeParams   *flowcontrol.ServerParams // Flow control parameters announced to free clients
+
+	priority  map[discover.NodeID]uint64       // Capacities of the priority clients
+	connected map[discover.NodeID]*poolClient  // Clients currently connected
+	usage     map[discover.NodeID]*clientUsage // Accumulated usage of priority clients from past sessions
+}
+
+// poolClient is a light client connected to the pool.
+type poolClient struct {
+	peer      *peer
+	capacity  uint64
+	priority  bool
+	connected mclock.AbsTime
+	node      *flowcontrol.ClientNode // Flow control node of the client, nil until the handshake is done
+}
+
+// clientsByAge implements sort.Interface, ordering clients from the most recently
+// connected one to the oldest.
+type clientsByAge []*poolClient
+
+func (s clientsByAge) Len() int           { return len(s) }
+func (s clientsByAge) Less(i, j int) bool { return s[i].connected > s[j].connected }
+func (s clientsByAge) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// clientUsage is the accumulated usage of a client over its sessions.
+type clientUsage struct {
+	sessions uint64
+	duration time.Duration
+	requests uint64
+	cost     uint64
+}
+
+// priorityClientRLP is the persisted form of a priority client and its usage.
+type priorityClientRLP struct {
+	ID       discover.NodeID
+	Capacity uint64
+	Sessions uint64
+	Duration uint64 // Time spent connected in nanoseconds
+	Requests uint64
+	Cost     uint64
+}
+
+// newClientPool creates a client pool sharing the given total capacity. Free
+// clients are announced the given flow control parameters, their recharge rate
+// being the minimum capacity each of them is guaranteed.
+func newClientPool(db ethdb.Database, total uint64, freeParams *flowcontrol.ServerParams) *clientPool {
+	pool := &clientPool{
+		db:         db,
+		total:      total,
+		freeParams: freeParams,
+		priority:   make(map[discover.NodeID]uint64),
+		connected:  make(map[discover.NodeID]*poolClient),
+		usage:      make(map[discover.NodeID]*clientUsage),
+	}
+	if db != nil {
+		var clients []priorityClientRLP
+		if data, err := db.Get(priorityClientsKey); err == nil {
+			if err := rlp.DecodeBytes(data, &clients); err != nil {
+				log.Error("Failed to decode priority clients", "err", err)
+			}
+		}
+		for _, client := range clients {
+			pool.priority[client.ID] = client.Capacity
+			if client.Sessions > 0 {
+				pool.usage[client.ID] = &clientUsage{
+					sessions: client.Sessions,
+					duration: time.Duration(client.Duration),
+					requests: client.Requests,
+					cost:     client.Cost,
+				}
+			}
+		}
+	}
+	return pool
+}
+
+// params returns the flow control parameters belonging to a capacity. The buffer
+// limit is scaled to allow the same bursts (in time) as free clients have.
+func (cp *clientPool) params(capacity uint64) *flowcontrol.ServerParams {
+	if capacity == cp.freeParams.MinRecharge {
+		return cp.freeParams
+	}
+	return &flowcontrol.ServerParams{
+		BufLimit:    cp.freeParams.BufLimit / cp.freeParams.MinRecharge * capacity,
+		MinRecharge: capacity,
+	}
+}
+
+// connect assigns capacity to a newly connected client, returning its flow
+// control parameters. Free clients are only accepted if each of them is still
+// left its minimum capacity, priority clients kick out free ones if needed.
+func (cp *clientPool) connect(p *peer) (*flowcontrol.ServerParams, error) {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	id := p.ID()
+	if _, ok := cp.connected[id]; ok {
+		return nil, errAlreadyRegistered
+	}
+	var (
+		freeCap            = cp.freeParams.MinRecharge
+		capacity, priority = cp.priority[id]
+	)
+	if !priority {
+		if cp.priorityUsed+uint64(cp.freeClients+1)*freeCap > cp.total {
+			return nil, p2p.DiscTooManyPeers
+		}
+	} else if cp.priorityUsed+capacity+uint64(cp.freeClients)*freeCap > cp.total {
+		// Priority client without room, kick the most recent free clients
+		if cp.priorityUsed+capacity > cp.total {
+			return nil, errNoPriorityCapacity
+		}
+		var free []*poolClient
+		for _, c := range cp.connected {
+			if !c.priority {
+				free = append(free, c)
+			}
+		}
+		sort.Sort(clientsByAge(free))
+
+		for _, c := range free {
+			if cp.priorityUsed+capacity+uint64(cp.freeClients)*freeCap <= cp.total {
+				break
+			}
+			c.peer.Log().Debug("Kicking free client for priority client", "priority", id)
+			cp.remove(c)
+			go c.peer.Disconnect(p2p.DiscTooManyPeers)
+		}
+	}
+	cp.connected[id] = &poolClient{
+		peer:      p,
+		capacity:  capacity,
+		priority:  priority,
+		connected: mclock.Now(),
+	}
+	if priority {
+		cp.priorityUsed += capacity
+	} else {
+		cp.freeClients++
+	}
+	cp.rebalance()
+
+	if priority {
+		return cp.params(capacity), nil
+	}
+	return cp.freeParams, nil
+}
+
+// activate records the flow control node of a client after a successful
+// handshake, allowing its usage to be tracked and its capacity to be updated.
+func (cp *clientPool) activate(p *peer) {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	if c, ok := cp.connected[p.ID()]; ok && c.peer == p {
+		c.node = p.fcClient
+		if !c.priority {
+			c.node.SetMinRecharge(c.capacity)
+		}
+	}
+}
+
+// disconnect releases the capacity of a disconnected client.
+func (cp *clientPool) disconnect(p *peer) {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	if c, ok := cp.connected[p.ID()]; ok && c.peer == p {
+		cp.remove(c)
+		cp.rebalance()
+	}
+}
+
+// remove releases the capacity of a client, accumulating and persisting the usage
+// of priority clients. The caller must hold the lock.
+func (cp *clientPool) remove(c *poolClient) {
+	id := c.peer.ID()
+	delete(cp.connected, id)
+
+	if !c.priority {
+		cp.freeClients--
+		return
+	}
+	cp.priorityUsed -= c.capacity
+
+	if _, ok := cp.priority[id]; !ok {
+		return // Demoted while connected, usage is not tracked any more
+	}
+	usage := cp.usage[id]
+	if usage == nil {
+		usage = new(clientUsage)
+		cp.usage[id] = usage
+	}
+	usage.sessions++
+	usage.duration += time.Duration(mclock.Now() - c.connected)
+	if c.node != nil {
+		requests, cost := c.node.Usage()
+		usage.requests += requests
+		usage.cost += cost
+	}
+	cp.store()
+}
+
+// rebalance splits the capacity left by the priority clients evenly among the
+// connected free clients. The caller must hold the lock.
+func (cp *clientPool) rebalance() {
+	if cp.freeClients == 0 {
+		return
+	}
+	share := (cp.total - cp.priorityUsed) / uint64(cp.freeClients)
+	for _, c := range cp.connected {
+		if !c.priority && c.capacity != share {
+			c.capacity = share
+			if c.node != nil {
+				c.node.SetMinRecharge(share)
+			}
+		}
+	}
+}
+
+// setPriority sets the capacity of a priority client, or demotes it to a free
+// client if the capacity is zero. The new capacity takes effect when the client
+// connects next.
+func (cp *clientPool) setPriority(id discover.NodeID, capacity uint64) error {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	if capacity > cp.total {
+		return fmt.Errorf("capacity %d exceeds the total capacity %d", capacity, cp.total)
+	}
+	if capacity == 0 {
+		delete(cp.priority, id)
+		delete(cp.usage, id)
+	} else {
+		cp.priority[id] = capacity
+	}
+	cp.store()
+	return nil
+}
+
+// store persists the priority clients and their usage into the database. The
+// caller must hold the lock.
+func (cp *clientPool) store() {
+	if cp.db == nil {
+		return
+	}
+	clients := make([]priorityClientRLP, 0, len(cp.priority))
+	for id, capacity := range cp.priority {
+		client := priorityClientRLP{ID: id, Capacity: capacity}
+		if usage := cp.usage[id]; usage != nil {
+			client.Sessions, client.Duration = usage.sessions, uint64(usage.duration)
+			client.Requests, client.Cost = usage.requests, usage.cost
+		}
+		clients = append(clients, client)
+	}
+	data, err := rlp.EncodeToBytes(clients)
+	if err != nil {
+		log.Error("Failed to encode priority clients", "err", err)
+		return
+	}
+	if err := cp.db.Put(priorityClientsKey, data); err != nil {
+		log.Error("Failed to store priority clients", "err", err)
+	}
+}
+
+// ClientInfo contains the capacity and the usage statistics of a light client.
+// The usage of priority clients is accumulated over all their sessions, the one
+// of free clients covers their current session only.
+type ClientInfo struct {
+	Priority      bool    `json:"priority"`
+	Capacity      uint64  `json:"capacity"`
+	Connected     bool    `json:"connected"`
+	Sessions      uint64  `json:"sessions"`
+	ConnectedTime float64 `json:"connectedTime"` // Time spent connected in seconds
+	Requests      uint64  `json:"requests"`
+	Cost          uint64  `json:"cost"`
+}
+
+// clientInfo returns the capacity and the usage statistics of all connected and
+// priority clients.
+func (cp *clientPool) clientInfo() map[discover.NodeID]*ClientInfo {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	infos := make(map[discover.NodeID]*ClientInfo)
+	for id, capacity := range cp.priority {
+		info := &ClientInfo{Priority: true, Capacity: capacity}
+		if usage := cp.usage[id]; usage != nil {
+			info.Sessions = usage.sessions
+			info.ConnectedTime = usage.duration.Seconds()
+			info.Requests, info.Cost = usage.requests, usage.cost
+		}
+		infos[id] = info
+	}
+	now := mclock.Now()
+	for id, c := range cp.connected {
+		info := infos[id]
+		if info == nil {
+			info = &ClientInfo{Capacity: c.capacity}
+			infos[id] = info
+		}
+		info.Connected = true
+		info.Sessions++
+		info.ConnectedTime += time.Duration(now - c.connected).Seconds()
+		if c.node != nil {
+			requests, cost := c.node.Usage()
+			info.Requests += requests
+			info.Cost += cost
+		}
+	}
+	return infos
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/les/flowcontrol"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+func newPoolTestPeer(id byte) *peer {
+	return newPeer(lpv2, NetworkId, p2p.NewPeer(discover.NodeID{id}, "test", nil), nil)
+}
+
+// Tests that free clients share the capacity left by priority clients, and that
+// they are kicked out when a priority client needs their capacity.
+func TestClientPoolPriority(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	pool := newClientPool(db, 3, &flowcontrol.ServerParams{BufLimit: 300, MinRecharge: 1})
+
+	// Fill up the pool with free clients and ensure no more are accepted
+	free := []*peer{newPoolTestPeer(1), newPoolTestPeer(2), newPoolTestPeer(3)}
+	for i, p := range free {
+		params, err := pool.connect(p)
+		if err != nil {
+			t.Fatalf("free client %d rejected: %v", i, err)
+		}
+		if params.MinRecharge != 1 || params.BufLimit != 300 {
+			t.Errorf("free client %d parameters mismatch: have %+v, want {BufLimit:300 MinRecharge:1}", i, params)
+		}
+		if have, want := pool.connected[p.ID()].capacity, uint64(3/(i+1)); have != want {
+			t.Errorf("free client %d capacity mismatch: have %d, want %d", i, have, want)
+		}
+		time.Sleep(time.Millisecond) // Ensure a strict connection order
+	}
+	if _, err := pool.connect(newPoolTestPeer(4)); err != p2p.DiscTooManyPeers {
+		t.Fatalf("excess free client error mismatch: have %v, want %v", err, p2p.DiscTooManyPeers)
+	}
+	// Connect a priority client and ensure the most recent free clients are kicked
+	if err := pool.setPriority(discover.NodeID{5}, 2); err != nil {
+		t.Fatalf("failed to set priority capacity: %v", err)
+	}
+	if err := pool.setPriority(discover.NodeID{6}, 4); err == nil {
+		t.Fatalf("capacity above the total accepted")
+	}
+	params, err := pool.connect(newPoolTestPeer(5))
+	if err != nil {
+		t.Fatalf("priority client rejected: %v", err)
+	}
+	if params.MinRecharge != 2 || params.BufLimit != 600 {
+		t.Errorf("priority client parameters mismatch: have %+v, want {BufLimit:600 MinRecharge:2}", params)
+	}
+	if _, ok := pool.connected[free[0].ID()]; !ok {
+		t.Errorf("oldest free client kicked")
+	}
+	for i, p := range free[1:] {
+		if _, ok := pool.connected[p.ID()]; ok {
+			t.Errorf("free client %d not kicked", i+1)
+		}
+	}
+	// Ensure priority clients can't kick each other
+	if err := pool.setPriority(discover.NodeID{7}, 2); err != nil {
+		t.Fatalf("failed to set priority capacity: %v", err)
+	}
+	if _, err := pool.connect(newPoolTestPeer(7)); err != errNoPriorityCapacity {
+		t.Fatalf("excess priority client error mismatch: have %v, want %v", err, errNoPriorityCapacity)
+	}
+	if _, ok := pool.connected[free[0].ID()]; !ok {
+		t.Errorf("free client kicked for rejected priority client")
+	}
+	// Disconnect the priority client, check its accumulated usage and that the
+	// remaining free client takes over the whole capacity
+	pool.disconnect(pool.connected[discover.NodeID{5}].peer)
+	if pool.priorityUsed != 0 || pool.freeClients != 1 {
+		t.Errorf("pool usage mismatch: have %d priority capacity, %d free clients, want 0, 1", pool.priorityUsed, pool.freeClients)
+	}
+	info := pool.clientInfo()
+	if have := info[discover.NodeID{5}]; have == nil || !have.Priority || have.Connected || have.Sessions != 1 || have.Capacity != 2 {
+		t.Errorf("priority client info mismatch: have %+v", have)
+	}
+	if have := info[free[0].ID()]; have == nil || have.Priority || !have.Connected || have.Capacity != 3 {
+		t.Errorf("free client info mismatch: have %+v", have)
+	}
+	// Ensure the priority clients are persisted and demotion is supported
+	if err := pool.setPriority(discover.NodeID{7}, 0); err != nil {
+		t.Fatalf("failed to remove priority client: %v", err)
+	}
+	pool = newClientPool(db, 3, &flowcontrol.ServerParams{BufLimit: 300, MinRecharge: 1})
+	if len(pool.priority) != 1 || pool.priority[discover.NodeID{5}] != 2 {
+		t.Errorf("persisted priority clients mismatch: have %v", pool.priority)
+	}
+	if have := pool.usage[discover.NodeID{5}]; have == nil || have.sessions != 1 || have.duration == 0 {
+		t.Errorf("persisted usage mismatch: have %+v", have)
+	}
+}
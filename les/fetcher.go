@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
 )
 
 const (
@@ -179,6 +180,7 @@ func (f *lightFetcher) syncLoop() {
 			if ok {
 				f.pm.serverPool.adjustResponseTime(req.peer.poolEntry, time.Duration(mclock.Now()-req.sent), true)
 				req.peer.Log().Debug("Fetching data timed out hard")
+				req.peer.AdjustScore(p2p.ScoreTimeout)
 				go f.pm.removePeer(req.peer.id)
 			}
 		case resp := <-f.deliverChn:
@@ -197,7 +199,10 @@ func (f *lightFetcher) syncLoop() {
 			f.lock.Lock()
 			if !ok || !(f.syncing || f.processResponse(req, resp)) {
 				resp.peer.Log().Debug("Failed processing response")
+				resp.peer.AdjustScore(p2p.ScoreProtocolViolation)
 				go f.pm.removePeer(resp.peer.id)
+			} else {
+				resp.peer.AdjustScore(p2p.ScoreUsefulResponse)
 			}
 			f.lock.Unlock()
 		case p := <-f.syncDone:
@@ -254,6 +259,7 @@ func (f *lightFetcher) announce(p *peer, head *announceData) {
 	if fp.lastAnnounced != nil && head.Td.Cmp(fp.lastAnnounced.td) <= 0 {
 		// announced tds should be strictly monotonic
 		p.Log().Debug("Received non-monotonic td", "current", head.Td, "previous", fp.lastAnnounced.td)
+		p.AdjustScore(p2p.ScoreProtocolViolation)
 		go f.pm.removePeer(p.id)
 		return
 	}
@@ -399,7 +405,7 @@ func (f *lightFetcher) nextRequest() (*distReq, uint64) {
 
 	for p, fp := range f.peers {
 		for hash, n := range fp.nodeByHash {
-			if !f.checkKnownNode(p, n) && !n.requested && (bestTd == nil || n.td.Cmp(bestTd) >= 0) {
+			if !f.checkKnownNode(p, n) && !n.requested && (bestTd == nil || n.td.Cmp(bestTd) >= 0) && f.trustedHead(hash) {
 				amount := f.requestAmount(p, n)
 				if bestTd == nil || n.td.Cmp(bestTd) > 0 || amount < bestAmount {
 					bestHash = hash
@@ -426,6 +432,10 @@ func (f *lightFetcher) nextRequest() (*distReq, uint64) {
 			canSend: func(dp distPeer) bool {
 				p := dp.(*peer)
 				fp := f.peers[p]
+				// Ultra light clients only sync from trusted servers, their headers are not verified
+				if f.pm.ulc != nil && !p.trusted {
+					return false
+				}
 				return fp != nil && fp.nodeByHash[bestHash] != nil
 			},
 			request: func(dp distPeer) func() {
@@ -484,6 +494,23 @@ func (f *lightFetcher) nextRequest() (*distReq, uint64) {
 	return rq, reqID
 }
 
+// trustedHead reports whether an announced head can be requested. In ultra light
+// client mode it needs to be announced by enough trusted servers, otherwise all
+// heads are accepted (and verified after downloading). The caller must hold the
+// fetcher lock.
+func (f *lightFetcher) trustedHead(hash common.Hash) bool {
+	if f.pm.ulc == nil {
+		return true
+	}
+	count := 0
+	for p, fp := range f.peers {
+		if p.trusted && fp.nodeByHash[hash] != nil {
+			count++
+		}
+	}
+	return count >= f.pm.ulc.quorum
+}
+
 // deliverHeaders delivers header download request responses for processing
 func (f *lightFetcher) deliverHeaders(peer *peer, reqID uint64, headers []*types.Header) {
 	f.deliverChn <- fetchResponse{reqID: reqID, headers: headers, peer: peer}
@@ -499,7 +526,12 @@ func (f *lightFetcher) processResponse(req fetchRequest, resp fetchResponse) boo
 	for i, header := range resp.headers {
 		headers[int(req.amount)-1-i] = header
 	}
-	if _, err := f.chain.InsertHeaderChain(headers, 1); err != nil {
+	// Headers of ultra light clients were announced by trusted servers, skip their seals
+	checkFreq := 1
+	if f.pm.ulc != nil {
+		checkFreq = 0
+	}
+	if _, err := f.chain.InsertHeaderChain(headers, checkFreq); err != nil {
 		if err == consensus.ErrFutureBlock {
 			return true
 		}
@@ -526,6 +558,7 @@ func (f *lightFetcher) newHeaders(headers []*types.Header, tds []*big.Int) {
 	for p, fp := range f.peers {
 		if !f.checkAnnouncedHeaders(fp, headers, tds) {
 			p.Log().Debug("Inconsistent announcement")
+			p.AdjustScore(p2p.ScoreProtocolViolation)
 			go f.pm.removePeer(p.id)
 		}
 		if fp.confirmedTd != nil && (maxTd == nil || maxTd.Cmp(fp.confirmedTd) > 0) {
@@ -625,6 +658,7 @@ func (f *lightFetcher) checkSyncedHeaders(p *peer) {
 	// now n is the latest downloaded header after syncing
 	if n == nil {
 		p.Log().Debug("Synchronisation failed")
+		p.AdjustScore(p2p.ScoreTimeout)
 		go f.pm.removePeer(p.id)
 	} else {
 		header := f.chain.GetHeader(n.hash, n.number)
@@ -651,6 +685,7 @@ func (f *lightFetcher) checkKnownNode(p *peer, n *fetcherTreeNode) bool {
 	header := f.chain.GetHeader(n.hash, n.number)
 	if !f.checkAnnouncedHeaders(fp, []*types.Header{header}, []*big.Int{td}) {
 		p.Log().Debug("Inconsistent announcement")
+		p.AdjustScore(p2p.ScoreProtocolViolation)
 		go f.pm.removePeer(p.id)
 	}
 	if fp.confirmedTd != nil {
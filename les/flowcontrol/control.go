@@ -37,6 +37,8 @@ type ClientNode struct {
 	lock     sync.Mutex
 	cm       *ClientManager
 	cmNode   *cmNode
+
+	reqCount, sumCost uint64 // number and total cost of the served requests
 }
 
 func NewClientNode(cm *ClientManager, params *ServerParams) *ClientNode {
@@ -82,6 +84,8 @@ func (peer *ClientNode) RequestProcessed(cost uint64) (bv, realCost uint64) {
 	time := mclock.Now()
 	peer.recalcBV(time)
 	peer.bufValue -= cost
+	peer.reqCount++
+	peer.sumCost += cost
 	peer.recalcBV(time)
 	rcValue, rcost := peer.cm.processed(peer.cmNode, time)
 	if rcValue < peer.params.BufLimit {
@@ -93,6 +97,24 @@ func (peer *ClientNode) RequestProcessed(cost uint64) (bv, realCost uint64) {
 	return peer.bufValue, rcost
 }
 
+// SetMinRecharge changes the rate the buffer of the client recharges at. Raising
+// it above the announced rate is safe, the client merely underestimates its buffer.
+func (peer *ClientNode) SetMinRecharge(rate uint64) {
+	peer.lock.Lock()
+	defer peer.lock.Unlock()
+
+	peer.recalcBV(mclock.Now())
+	peer.params = &ServerParams{BufLimit: peer.params.BufLimit, MinRecharge: rate}
+}
+
+// Usage returns the number and the total cost of the requests served so far.
+func (peer *ClientNode) Usage() (requests, cost uint64) {
+	peer.lock.Lock()
+	defer peer.lock.Unlock()
+
+	return peer.reqCount, peer.sumCost
+}
+
 type ServerNode struct {
 	bufEstimate uint64
 	lastTime    mclock.AbsTime
@@ -32,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
@@ -59,6 +60,8 @@ const (
 	MaxHelperTrieProofsFetch = 64  // Amount of merkle proofs to be fetched per retrieval request
 	MaxTxSend                = 64  // Amount of transactions to be send per request
 	MaxTxStatus              = 256 // Amount of transactions to queried per request
+	MaxLogsFetch             = 32  // Amount of blocks to be filtered per logs request
+	MaxTxLookupFetch         = 64  // Amount of transactions to be looked up per request
 
 	disableClientRemovePeer = false
 )
@@ -107,6 +110,7 @@ type ProtocolManager struct {
 	lesTopic    discv5.Topic
 	reqDist     *requestDistributor
 	retriever   *retrieveManager
+	ulc         *ulc // Ultra light client settings (nil = verify all headers)
 
 	downloader *downloader.Downloader
 	fetcher    *lightFetcher
@@ -128,7 +132,7 @@ type ProtocolManager struct {
 
 // NewProtocolManager returns a new ethereum sub protocol manager. The Ethereum sub protocol manages peers capable
 // with the ethereum network.
-func NewProtocolManager(chainConfig *params.ChainConfig, lightSync bool, protocolVersions []uint, networkId uint64, mux *event.TypeMux, engine consensus.Engine, peers *peerSet, blockchain BlockChain, txpool txPool, chainDb ethdb.Database, odr *LesOdr, txrelay *LesTxRelay, quitSync chan struct{}, wg *sync.WaitGroup) (*ProtocolManager, error) {
+func NewProtocolManager(chainConfig *params.ChainConfig, lightSync bool, checkpoint *downloader.Checkpoint, ulcConfig *eth.ULCConfig, protocolVersions []uint, networkId uint64, mux *event.TypeMux, engine consensus.Engine, peers *peerSet, blockchain BlockChain, txpool txPool, chainDb ethdb.Database, odr *LesOdr, txrelay *LesTxRelay, quitSync chan struct{}, wg *sync.WaitGroup) (*ProtocolManager, error) {
 	// Create the protocol manager with the base fields
 	manager := &ProtocolManager{
 		lightSync:   lightSync,
@@ -150,6 +154,13 @@ func NewProtocolManager(chainConfig *params.ChainConfig, lightSync bool, protoco
 		manager.retriever = odr.retriever
 		manager.reqDist = odr.retriever.dist
 	}
+	if ulcConfig != nil {
+		ulc, err := newULC(ulcConfig)
+		if err != nil {
+			return nil, err
+		}
+		manager.ulc = ulc
+	}
 
 	// Initiate a sub-protocol for every implemented version we can handle
 	manager.SubProtocols = make([]p2p.Protocol, 0, len(protocolVersions))
@@ -205,7 +216,10 @@ func NewProtocolManager(chainConfig *params.ChainConfig, lightSync bool, protoco
 	}
 
 	if lightSync {
-		manager.downloader = downloader.New(downloader.LightSync, chainDb, manager.eventMux, nil, blockchain, removePeer)
+		manager.downloader = downloader.New(downloader.LightSync, checkpoint, chainDb, manager.eventMux, nil, blockchain, func(id string, timeout bool) { removePeer(id) })
+		if manager.ulc != nil {
+			manager.downloader.DisableSealVerification()
+		}
 		manager.peers.notify((*downloaderPeerNotify)(manager))
 		manager.fetcher = newLightFetcher(manager)
 	}
@@ -253,7 +267,11 @@ func (pm *ProtocolManager) Stop() {
 }
 
 func (pm *ProtocolManager) newPeer(pv int, nv uint64, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
-	return newPeer(pv, nv, p, newMeteredMsgWriter(rw))
+	peer := newPeer(pv, nv, p, newMeteredMsgWriter(rw))
+	if pm.ulc != nil {
+		peer.trusted = pm.ulc.isTrusted(p.ID())
+	}
+	return peer
 }
 
 // handle is the callback invoked to manage the life cycle of a les peer. When
@@ -261,6 +279,16 @@ func (pm *ProtocolManager) newPeer(pv int, nv uint64, p *p2p.Peer, rw p2p.MsgRea
 func (pm *ProtocolManager) handle(p *peer) error {
 	p.Log().Debug("Light Ethereum peer connected", "name", p.Name())
 
+	// Assign the flow control capacity of light clients, kicking free ones if needed
+	if pm.server != nil {
+		params, err := pm.server.clientPool.connect(p)
+		if err != nil {
+			p.Log().Debug("Light Ethereum client rejected", "err", err)
+			return err
+		}
+		defer pm.server.clientPool.disconnect(p)
+		p.fcParams = params
+	}
 	// Execute the LES handshake
 	td, head, genesis := pm.blockchain.Status()
 	headNum := core.GetBlockNumber(pm.chainDb, head)
@@ -271,6 +299,9 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	if rw, ok := p.rw.(*meteredMsgReadWriter); ok {
 		rw.Init(p.version)
 	}
+	if pm.server != nil {
+		pm.server.clientPool.activate(p)
+	}
 	// Register the peer locally
 	if err := pm.peers.Register(p); err != nil {
 		p.Log().Error("Light Ethereum peer registration failed", "err", err)
@@ -319,7 +350,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	}
 }
 
-var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg}
+var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg, GetLogsMsg, GetTxLookupMsg}
 
 // handleMsg is invoked whenever an inbound message is received from a remote
 // peer. The remote connection is torn down upon returning any error.
@@ -338,11 +369,11 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bufValue, _ := p.fcClient.AcceptRequest()
 		cost := costs.baseCost + reqCnt*costs.reqCost
-		if cost > pm.server.defParams.BufLimit {
-			cost = pm.server.defParams.BufLimit
+		if cost > p.fcParams.BufLimit {
+			cost = p.fcParams.BufLimit
 		}
 		if cost > bufValue {
-			recharge := time.Duration((cost - bufValue) * 1000000 / pm.server.defParams.MinRecharge)
+			recharge := time.Duration((cost - bufValue) * 1000000 / p.fcParams.MinRecharge)
 			p.Log().Error("Request came too early", "recharge", common.PrettyDuration(recharge))
 			return true
 		}
@@ -1063,6 +1094,124 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		p.fcServer.GotReply(resp.ReqID, resp.BV)
 
+	case GetLogsMsg:
+		p.Log().Trace("Received logs request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID uint64
+			Query logsQuery
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := len(req.Query.Hashes)
+		if reject(uint64(reqCnt), MaxLogsFetch) {
+			return errResp(ErrRequestRejected, "")
+		}
+		// Prove the receipts of the blocks with matching blooms until an unknown
+		// block or the network limit is reached, the client accepts a prefix
+		var (
+			nodes = light.NewNodeSet()
+			resp  = new(logsResponse)
+		)
+		for _, hash := range req.Query.Hashes {
+			if nodes.DataSize() >= softResponseLimit {
+				break
+			}
+			number := core.GetBlockNumber(pm.chainDb, hash)
+			header := core.GetHeader(pm.chainDb, hash, number)
+			if header == nil {
+				break
+			}
+			proven := uint64(0)
+			if matchBloom(header.Bloom, req.Query.Addresses, req.Query.Topics) {
+				body := core.GetBody(pm.chainDb, hash, number)
+				receipts := core.GetBlockReceipts(pm.chainDb, hash, number)
+				if body == nil || len(receipts) != len(body.Transactions) {
+					break
+				}
+				proven = proveLogs(receipts, body.Transactions, &req.Query, nodes)
+			}
+			resp.Receipts = append(resp.Receipts, proven)
+		}
+		resp.Proof = nodes.NodeList()
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		return p.SendLogs(req.ReqID, bv, resp)
+
+	case LogsMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received logs response")
+		// A batch of proven receipts arrived to one of our previous requests
+		var resp struct {
+			ReqID, BV uint64
+			Data      logsResponse
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgLogs,
+			ReqID:   resp.ReqID,
+			Obj:     &resp.Data,
+		}
+
+	case GetTxLookupMsg:
+		p.Log().Trace("Received transaction lookup request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID  uint64
+			Hashes []common.Hash
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqCnt := len(req.Hashes)
+		if reject(uint64(reqCnt), MaxTxLookupFetch) {
+			return errResp(ErrRequestRejected, "")
+		}
+		// Prove the position and the receipt of the transactions until reaching
+		// the network limit, unknown transactions are answered with empty lookups
+		var (
+			nodes = light.NewNodeSet()
+			resp  = new(txLookupResponse)
+		)
+		for _, hash := range req.Hashes {
+			if nodes.DataSize() >= softResponseLimit {
+				break
+			}
+			resp.Lookups = append(resp.Lookups, proveTxLookup(pm.chainDb, hash, nodes))
+		}
+		resp.Proof = nodes.NodeList()
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		return p.SendTxLookups(req.ReqID, bv, resp)
+
+	case TxLookupMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received transaction lookup response")
+		// A batch of proven transaction positions arrived to one of our previous requests
+		var resp struct {
+			ReqID, BV uint64
+			Data      txLookupResponse
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgTxLookups,
+			ReqID:   resp.ReqID,
+			Obj:     &resp.Data,
+		}
+
 	default:
 		p.Log().Trace("Received unknown message", "code", msg.Code)
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
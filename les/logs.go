@@ -0,0 +1,211 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// logsQuery is the network packet requesting the logs of a batch of blocks which
+// match a log filter.
+type logsQuery struct {
+	Hashes    []common.Hash    // Hashes of the blocks to filter
+	Addresses []common.Address // Accepted log origins (empty = any)
+	Topics    [][]common.Hash  // Accepted topics per position (empty = any)
+}
+
+// logsResponse is the network packet answering a logs query, covering a prefix
+// of the queried blocks. All receipts of the blocks whose header bloom matches
+// the query are proven against the receipt roots, along with the absence of any
+// further receipt, showing that no matching log was left out and fixing the index
+// of every log. The transactions of the receipts with matching logs are proven
+// against the transaction roots. Blocks whose bloom doesn't match need no proof.
+type logsResponse struct {
+	Receipts []uint64 // Number of receipts proven per block (0 if the bloom doesn't match)
+	Proof    light.NodeList
+}
+
+// matchLog reports whether a log matches the given address and topic criteria.
+func matchLog(log *types.Log, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		found := false
+		for _, addr := range addresses {
+			if log.Address == addr {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(topics) > len(log.Topics) {
+		return false
+	}
+	for i, sub := range topics {
+		match := len(sub) == 0 // empty rule set == wildcard
+		for _, topic := range sub {
+			if log.Topics[i] == topic {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+// matchBloom reports whether a bloom filter may contain logs matching the given
+// address and topic criteria.
+func matchBloom(bloom types.Bloom, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		found := false
+		for _, addr := range addresses {
+			if types.BloomLookup(bloom, addr) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, sub := range topics {
+		match := len(sub) == 0 // empty rule set == wildcard
+		for _, topic := range sub {
+			if types.BloomLookup(bloom, topic) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+// deriveTrie builds the in-memory trie a block header commits to by the root
+// hash of a list (see types.DeriveSha).
+func deriveTrie(list types.DerivableList) *trie.Trie {
+	keybuf := new(bytes.Buffer)
+	tr := new(trie.Trie)
+	for i := 0; i < list.Len(); i++ {
+		keybuf.Reset()
+		rlp.Encode(keybuf, uint(i))
+		tr.Update(keybuf.Bytes(), list.GetRlp(i))
+	}
+	return tr
+}
+
+// proveLogs adds the merkle proofs of all the receipts of a block whose bloom
+// matches a query to the given node set, along with the proof that there are no
+// more receipts and the proofs of the transactions creating matching logs. It
+// returns the number of receipts proven.
+func proveLogs(receipts types.Receipts, txs types.Transactions, query *logsQuery, nodes *light.NodeSet) uint64 {
+	if len(receipts) == 0 {
+		return 0
+	}
+	rtrie, txtrie := deriveTrie(receipts), deriveTrie(txs)
+	for i, receipt := range receipts {
+		key, _ := rlp.EncodeToBytes(uint(i))
+		rtrie.Prove(key, 0, nodes)
+
+		for _, log := range receipt.Logs {
+			if matchLog(log, query.Addresses, query.Topics) {
+				txtrie.Prove(key, 0, nodes)
+				break
+			}
+		}
+	}
+	key, _ := rlp.EncodeToBytes(uint(len(receipts)))
+	rtrie.Prove(key, 0, nodes)
+
+	return uint64(len(receipts))
+}
+
+// verifyLogs checks the proven receipts of a block against its header, returning
+// the matching logs with all their metadata filled in.
+func verifyLogs(header *types.Header, receipts uint64, query *logsQuery, proof trie.DatabaseReader) ([]*types.Log, error) {
+	// Blocks without potentially matching logs must come without receipts
+	if !matchBloom(header.Bloom, query.Addresses, query.Topics) || header.ReceiptHash == types.EmptyRootHash {
+		if receipts != 0 {
+			return nil, errUselessReceipts
+		}
+		return nil, nil
+	}
+	var (
+		logs     []*types.Log
+		hash     = header.Hash()
+		logIndex uint
+	)
+	for i := uint64(0); ; i++ {
+		key, _ := rlp.EncodeToBytes(uint(i))
+
+		blob, err, _ := trie.VerifyProof(header.ReceiptHash, key, proof)
+		if err != nil {
+			return nil, err
+		}
+		if i == receipts {
+			// All receipts verified, ensure there are no more
+			if blob != nil {
+				return nil, errIncompleteReceipts
+			}
+			return logs, nil
+		}
+		if blob == nil {
+			return nil, errReceiptHashMismatch
+		}
+		receipt := new(types.Receipt)
+		if err := rlp.DecodeBytes(blob, receipt); err != nil {
+			return nil, err
+		}
+		// Fill in the metadata of the logs and keep the matching ones
+		var txHash *common.Hash
+		for _, log := range receipt.Logs {
+			log.BlockNumber, log.BlockHash = header.Number.Uint64(), hash
+			log.TxIndex, log.Index = uint(i), logIndex
+			logIndex++
+
+			if !matchLog(log, query.Addresses, query.Topics) {
+				continue
+			}
+			if txHash == nil {
+				blob, err, _ := trie.VerifyProof(header.TxHash, key, proof)
+				if err != nil {
+					return nil, err
+				}
+				if blob == nil {
+					return nil, errTxHashMismatch
+				}
+				txHash = new(common.Hash)
+				*txHash = crypto.Keccak256Hash(blob)
+			}
+			log.TxHash = *txHash
+			logs = append(logs, log)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Tests that the logs proven by a server are verified against the block header,
+// with their metadata restored, and that incomplete or tampered responses are
+// rejected.
+func TestProveLogs(t *testing.T) {
+	var (
+		addr1, addr2 = common.Address{0x01}, common.Address{0x02}
+		topic        = common.Hash{0xaa}
+
+		txs      types.Transactions
+		receipts types.Receipts
+	)
+	// Assemble a block with receipts containing various logs
+	logs := [][]*types.Log{
+		{{Address: addr1}, {Address: addr2, Topics: []common.Hash{topic}}},
+		{},
+		{{Address: addr2}},
+		{{Address: addr1, Topics: []common.Hash{topic}}, {Address: addr1}},
+	}
+	for i, l := range logs {
+		txs = append(txs, types.NewTransaction(uint64(i), addr1, big.NewInt(1), 21000, nil, nil))
+		receipt := types.NewReceipt(nil, false, uint64(21000*(i+1)))
+		receipt.Logs = l
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		receipts = append(receipts, receipt)
+	}
+	header := &types.Header{
+		Number:      big.NewInt(10),
+		TxHash:      types.DeriveSha(txs),
+		ReceiptHash: types.DeriveSha(receipts),
+		Bloom:       types.CreateBloom(receipts),
+	}
+	// Prove and verify the logs of addr1 with the topic at any position
+	query := &logsQuery{Addresses: []common.Address{addr1}, Topics: [][]common.Hash{{topic}}}
+
+	nodes := light.NewNodeSet()
+	if proven := proveLogs(receipts, txs, query, nodes); proven != 4 {
+		t.Fatalf("proven receipt count mismatch: have %d, want 4", proven)
+	}
+	found, err := verifyLogs(header, 4, query, nodes.NodeList().NodeSet())
+	if err != nil {
+		t.Fatalf("failed to verify logs: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("verified log count mismatch: have %d, want 1", len(found))
+	}
+	if l := found[0]; l.Address != addr1 || l.BlockHash != header.Hash() || l.BlockNumber != 10 || l.TxHash != txs[3].Hash() || l.TxIndex != 3 || l.Index != 3 {
+		t.Errorf("verified log mismatch: have %+v", l)
+	}
+	// Prove the logs of addr2 without topics, spanning multiple receipts
+	query = &logsQuery{Addresses: []common.Address{addr2}}
+
+	nodes = light.NewNodeSet()
+	proveLogs(receipts, txs, query, nodes)
+	proof := nodes.NodeList().NodeSet()
+	if found, err = verifyLogs(header, 4, query, proof); err != nil {
+		t.Fatalf("failed to verify logs: %v", err)
+	}
+	if len(found) != 2 || found[0].Index != 1 || found[0].TxHash != txs[0].Hash() || found[1].Index != 2 || found[1].TxHash != txs[2].Hash() {
+		t.Errorf("verified logs mismatch: have %v", found)
+	}
+	// Ensure incomplete or excess receipts are rejected
+	if _, err := verifyLogs(header, 3, query, proof); err != errIncompleteReceipts {
+		t.Errorf("incomplete receipts error mismatch: have %v, want %v", err, errIncompleteReceipts)
+	}
+	if _, err := verifyLogs(header, 5, query, proof); err != errReceiptHashMismatch {
+		t.Errorf("excess receipts error mismatch: have %v, want %v", err, errReceiptHashMismatch)
+	}
+	// Ensure blocks without matching bloom need no proofs, but don't accept any
+	other := &logsQuery{Addresses: []common.Address{{0x03}}}
+	if matchBloom(header.Bloom, other.Addresses, other.Topics) {
+		t.Fatalf("unexpected bloom match")
+	}
+	if found, err := verifyLogs(header, 0, other, light.NewNodeSet()); err != nil || len(found) != 0 {
+		t.Errorf("non-matching block mismatch: have %v (%v), want none", found, err)
+	}
+	if _, err := verifyLogs(header, 4, other, proof); err != errUselessReceipts {
+		t.Errorf("non-matching receipts error mismatch: have %v, want %v", err, errUselessReceipts)
+	}
+	// Ensure missing transaction proofs are detected
+	nodes = light.NewNodeSet()
+	proveLogs(receipts, make(types.Transactions, 0), query, nodes)
+	if _, err := verifyLogs(header, 4, query, nodes.NodeList().NodeSet()); err == nil {
+		t.Errorf("unproven transactions accepted")
+	}
+}
+
+// Tests that a light client retrieves the filtered logs of a batch of blocks from
+// a server, matching the logs of the server's receipts.
+func TestFilteredLogsLes3(t *testing.T) {
+	// Assemble the test environment
+	peers := newPeerSet()
+	dist := newRequestDistributor(peers, make(chan struct{}))
+	rm := newRetrieveManager(peers, dist, nil)
+	db, _ := ethdb.NewMemDatabase()
+	ldb, _ := ethdb.NewMemDatabase()
+	odr := NewLesOdr(ldb, light.NewChtIndexer(db, true), light.NewBloomTrieIndexer(db, true), eth.NewBloomIndexer(db, light.BloomTrieFrequency), rm)
+	pm := newTestProtocolManagerMust(t, false, 4, testChainGen, nil, nil, db)
+	lpm := newTestProtocolManagerMust(t, true, 0, nil, peers, odr, ldb)
+	_, err1, lpeer, err2 := newTestPeerPair("peer", lpv3, pm, lpm)
+	select {
+	case <-time.After(time.Millisecond * 100):
+	case err := <-err1:
+		t.Fatalf("peer 1 handshake error: %v", err)
+	case err := <-err2:
+		t.Fatalf("peer 1 handshake error: %v", err)
+	}
+	lpm.synchronise(lpeer)
+
+	lpeer.lock.Lock()
+	lpeer.hasBlock = func(common.Hash, uint64) bool { return true }
+	lpeer.lock.Unlock()
+
+	// Filter the logs of all blocks at once and compare them to the server's
+	var headers []*types.Header
+	for i := uint64(0); i <= pm.blockchain.CurrentHeader().Number.Uint64(); i++ {
+		headers = append(headers, pm.blockchain.GetHeaderByNumber(i))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	logs, err := light.GetFilteredLogs(ctx, odr, headers, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to filter logs: %v", err)
+	}
+	if len(logs) != len(headers) {
+		t.Fatalf("filtered block count mismatch: have %d, want %d", len(logs), len(headers))
+	}
+	encode := func(logs []*types.Log) []byte {
+		enc := make([]*types.LogForStorage, len(logs))
+		for i, log := range logs {
+			enc[i] = (*types.LogForStorage)(log)
+		}
+		blob, _ := rlp.EncodeToBytes(enc)
+		return blob
+	}
+	for i, header := range headers {
+		var want []*types.Log
+		for _, receipt := range core.GetBlockReceipts(db, header.Hash(), header.Number.Uint64()) {
+			want = append(want, receipt.Logs...)
+		}
+		if !bytes.Equal(encode(logs[i]), encode(want)) {
+			t.Errorf("block %d: logs mismatch: have %v, want %v", i, logs[i], want)
+		}
+	}
+}
@@ -77,6 +77,8 @@ const (
 	MsgProofsV2
 	MsgHeaderProofs
 	MsgHelperTrieProofs
+	MsgLogs
+	MsgTxLookups
 )
 
 // Msg encodes a LES message that delivers reply data for a request
@@ -45,6 +45,9 @@ var (
 	errCHTHashMismatch     = errors.New("cht hash mismatch")
 	errCHTNumberMismatch   = errors.New("cht number mismatch")
 	errUselessNodes        = errors.New("useless nodes in merkle proof nodeset")
+	errUselessReceipts     = errors.New("receipts proven for block without matching bloom")
+	errIncompleteReceipts  = errors.New("proven receipts incomplete")
+	errTxNotCanonical      = errors.New("transaction lookup not in canonical chain")
 )
 
 type LesOdrRequest interface {
@@ -68,6 +71,10 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*ChtRequest)(r)
 	case *light.BloomRequest:
 		return (*BloomRequest)(r)
+	case *light.LogsRequest:
+		return (*LogsRequest)(r)
+	case *light.TxLookupRequest:
+		return (*TxLookupRequest)(r)
 	default:
 		return nil
 	}
@@ -193,7 +200,7 @@ func (r *TrieRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetProofsV1Msg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetProofsV2Msg, 1)
 	default:
 		panic(nil)
@@ -353,7 +360,7 @@ func (r *ChtRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetHeaderProofsMsg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetHelperTrieProofsMsg, 1)
 	default:
 		panic(nil)
@@ -543,6 +550,152 @@ func (r *BloomRequest) Validate(db ethdb.Database, msg *Msg) error {
 	return nil
 }
 
+// LogsRequest is the ODR request type for the logs of a batch of blocks matching
+// a filter, see LesOdrRequest interface
+type LogsRequest light.LogsRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *LogsRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetLogsMsg, len(r.Headers))
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *LogsRequest) CanSend(peer *peer) bool {
+	if peer.version < lpv3 {
+		return false
+	}
+	for _, header := range r.Headers {
+		if !peer.HasBlock(header.Hash(), header.Number.Uint64()) {
+			return false
+		}
+	}
+	return true
+}
+
+// query assembles the network packet of the request.
+func (r *LogsRequest) query() *logsQuery {
+	query := &logsQuery{
+		Hashes:    make([]common.Hash, len(r.Headers)),
+		Addresses: r.Addresses,
+		Topics:    r.Topics,
+	}
+	for i, header := range r.Headers {
+		query.Hashes[i] = header.Hash()
+	}
+	return query
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *LogsRequest) Request(reqID uint64, peer *peer) error {
+	return peer.RequestLogs(reqID, r.GetCost(peer), r.query())
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *LogsRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating logs", "count", len(r.Headers))
+
+	if msg.MsgType != MsgLogs {
+		return errInvalidMessageType
+	}
+	// Servers may answer a prefix of the blocks, the rest is requested again
+	resp := msg.Obj.(*logsResponse)
+	if len(resp.Receipts) == 0 || len(resp.Receipts) > len(r.Headers) {
+		return errInvalidEntryCount
+	}
+	var (
+		nodeSet = resp.Proof.NodeSet()
+		reads   = &readTraceDB{db: nodeSet}
+		query   = r.query()
+		logs    = make([][]*types.Log, len(resp.Receipts))
+	)
+	for i, receipts := range resp.Receipts {
+		found, err := verifyLogs(r.Headers[i], receipts, query, reads)
+		if err != nil {
+			return fmt.Errorf("logs verification failed: %v", err)
+		}
+		logs[i] = found
+	}
+	// check if all nodes have been read by VerifyProof
+	if len(reads.reads) != nodeSet.KeyCount() {
+		return errUselessNodes
+	}
+	r.Logs = logs
+	return nil
+}
+
+// TxLookupRequest is the ODR request type for the proven position and receipt of
+// a transaction by its hash
+type TxLookupRequest light.TxLookupRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *TxLookupRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetTxLookupMsg, 1)
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *TxLookupRequest) CanSend(peer *peer) bool {
+	return peer.version >= lpv3
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *TxLookupRequest) Request(reqID uint64, peer *peer) error {
+	return peer.RequestTxLookups(reqID, r.GetCost(peer), []common.Hash{r.Hash})
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *TxLookupRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating transaction lookup", "hash", r.Hash)
+
+	if msg.MsgType != MsgTxLookups {
+		return errInvalidMessageType
+	}
+	resp := msg.Obj.(*txLookupResponse)
+	if len(resp.Lookups) != 1 {
+		return errInvalidEntryCount
+	}
+	lookup := resp.Lookups[0]
+
+	// An unknown transaction doesn't come with any proofs
+	if lookup.Header == nil {
+		if len(resp.Proof) != 0 {
+			return errUselessNodes
+		}
+		return nil
+	}
+	// Reject lookups pointing outside the local canonical chain before anything
+	// gets cached, so that another peer is asked instead. Positions below the
+	// local head without a local header are checked by the caller via the CHT.
+	number := lookup.Header.Number.Uint64()
+	if hash := core.GetCanonicalHash(db, number); hash != (common.Hash{}) {
+		if hash != lookup.Header.Hash() {
+			return errTxNotCanonical
+		}
+	} else if head := core.GetHeadHeaderHash(db); number > core.GetBlockNumber(db, head) {
+		return errTxNotCanonical
+	}
+	var (
+		nodeSet = resp.Proof.NodeSet()
+		reads   = &readTraceDB{db: nodeSet}
+	)
+	tx, receipt, err := verifyTxLookup(r.Hash, lookup, reads)
+	if err != nil {
+		return fmt.Errorf("transaction lookup verification failed: %v", err)
+	}
+	// check if all nodes have been read by VerifyProof
+	if len(reads.reads) != nodeSet.KeyCount() {
+		return errUselessNodes
+	}
+	r.Tx, r.Receipt = tx, receipt
+	r.BlockHash, r.BlockNumber, r.Index = lookup.Header.Hash(), lookup.Header.Number.Uint64(), lookup.Index
+	return nil
+}
+
 // readTraceDB stores the keys of database reads. We use this to check that received node
 // sets contain only the trie nodes necessary to make proofs pass.
 type readTraceDB struct {
@@ -74,6 +74,42 @@ func odrGetReceipts(ctx context.Context, db ethdb.Database, config *params.Chain
 	return rlp
 }
 
+func TestOdrTxLookupLes3(t *testing.T) { testOdr(t, 3, 1, odrTxLookup) }
+
+func odrTxLookup(ctx context.Context, db ethdb.Database, config *params.ChainConfig, bc *core.BlockChain, lc *light.LightChain, bhash common.Hash) []byte {
+	var block *types.Block
+	if bc != nil {
+		block = bc.GetBlockByHash(bhash)
+	} else {
+		block, _ = lc.GetBlockByHash(ctx, bhash)
+	}
+	if block == nil {
+		return nil
+	}
+	var res []interface{}
+	for _, tx := range block.Transactions() {
+		var (
+			ltx                *types.Transaction
+			receipt            *types.Receipt
+			blockHash          common.Hash
+			blockNumber, index uint64
+		)
+		if bc != nil {
+			ltx, blockHash, blockNumber, index = core.GetTransaction(db, tx.Hash())
+			receipt, _, _, _ = core.GetReceipt(db, tx.Hash())
+		} else {
+			ltx, blockHash, blockNumber, index, _ = light.GetTransaction(ctx, lc.Odr(), tx.Hash())
+			receipt, _ = light.GetTransactionReceipt(ctx, lc.Odr(), tx.Hash())
+		}
+		if ltx == nil || receipt == nil {
+			return nil
+		}
+		res = append(res, ltx, blockHash, blockNumber, index, (*types.ReceiptForStorage)(receipt))
+	}
+	rlp, _ := rlp.EncodeToBytes(res)
+	return rlp
+}
+
 func TestOdrAccountsLes1(t *testing.T) { testOdr(t, 1, 1, odrAccounts) }
 
 func TestOdrAccountsLes2(t *testing.T) { testOdr(t, 2, 1, odrAccounts) }
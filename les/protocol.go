@@ -37,16 +37,17 @@ import (
 const (
 	lpv1 = 1
 	lpv2 = 2
+	lpv3 = 3
 )
 
 // Supported versions of the les protocol (first is primary)
 var (
-	ClientProtocolVersions = []uint{lpv2, lpv1}
-	ServerProtocolVersions = []uint{lpv2, lpv1}
+	ClientProtocolVersions = []uint{lpv3, lpv2, lpv1}
+	ServerProtocolVersions = []uint{lpv3, lpv2, lpv1}
 )
 
 // Number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = map[uint]uint64{lpv1: 15, lpv2: 22}
+var ProtocolLengths = map[uint]uint64{lpv1: 15, lpv2: 22, lpv3: 26}
 
 const (
 	NetworkId          = 1
@@ -79,6 +80,11 @@ const (
 	SendTxV2Msg            = 0x13
 	GetTxStatusMsg         = 0x14
 	TxStatusMsg            = 0x15
+	// Protocol messages belonging to LPV3
+	GetLogsMsg     = 0x16
+	LogsMsg        = 0x17
+	GetTxLookupMsg = 0x18
+	TxLookupMsg    = 0x19
 )
 
 type errCode int
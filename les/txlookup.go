@@ -0,0 +1,150 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// txLookupData is the position of a single transaction in the canonical chain.
+type txLookupData struct {
+	Header   *types.Header `rlp:"nil"` // Header of the block including the transaction (nil = unknown)
+	Index    uint64        // Index of the transaction within the block
+	LogIndex uint64        // Index of the first log of the transaction within the block
+}
+
+// txLookupResponse is the network packet answering a batch of transaction lookups.
+// The transactions and their receipts are proven by the merkle proofs against the
+// transaction and receipt roots of the included headers. The receipt preceding a
+// transaction is proven too, since the gas used by the transaction is derived from
+// the cumulative gas usage of both. The log indices are not provable without all
+// the receipts of the block, they are only passed along.
+type txLookupResponse struct {
+	Lookups []txLookupData
+	Proof   light.NodeList
+}
+
+// proveTxLookup looks up the position of a transaction in the canonical chain,
+// adding the merkle proofs of the transaction and of its receipt to the given
+// node set. An empty lookup is returned for unknown transactions.
+func proveTxLookup(db ethdb.Database, hash common.Hash, nodes *light.NodeSet) txLookupData {
+	blockHash, number, index := core.GetTxLookupEntry(db, hash)
+	if blockHash == (common.Hash{}) || core.GetCanonicalHash(db, number) != blockHash {
+		return txLookupData{}
+	}
+	header := core.GetHeader(db, blockHash, number)
+	body := core.GetBody(db, blockHash, number)
+	receipts := core.GetBlockReceipts(db, blockHash, number)
+	if header == nil || body == nil || len(receipts) != len(body.Transactions) || index >= uint64(len(receipts)) {
+		return txLookupData{}
+	}
+	rtrie, txtrie := deriveTrie(receipts), deriveTrie(types.Transactions(body.Transactions))
+
+	key, _ := rlp.EncodeToBytes(uint(index))
+	txtrie.Prove(key, 0, nodes)
+	rtrie.Prove(key, 0, nodes)
+	if index > 0 {
+		key, _ = rlp.EncodeToBytes(uint(index - 1))
+		rtrie.Prove(key, 0, nodes)
+	}
+	var logIndex uint64
+	for _, receipt := range receipts[:index] {
+		logIndex += uint64(len(receipt.Logs))
+	}
+	return txLookupData{Header: header, Index: index, LogIndex: logIndex}
+}
+
+// verifyTxLookup checks the proven transaction and receipt of a lookup against
+// the included header, returning them with all the receipt metadata filled in.
+func verifyTxLookup(hash common.Hash, data txLookupData, proof trie.DatabaseReader) (*types.Transaction, *types.Receipt, error) {
+	header := data.Header
+	key, _ := rlp.EncodeToBytes(uint(data.Index))
+
+	// Verify the transaction and ensure it's the requested one
+	blob, err, _ := trie.VerifyProof(header.TxHash, key, proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	if blob == nil || crypto.Keccak256Hash(blob) != hash {
+		return nil, nil, errTxHashMismatch
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(blob, tx); err != nil {
+		return nil, nil, err
+	}
+	// Verify the receipt of the transaction and of the preceding one
+	receipt, err := verifyReceipt(header, data.Index, proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt.GasUsed = receipt.CumulativeGasUsed
+	if data.Index > 0 {
+		prev, err := verifyReceipt(header, data.Index-1, proof)
+		if err != nil {
+			return nil, nil, err
+		}
+		if prev.CumulativeGasUsed > receipt.CumulativeGasUsed {
+			return nil, nil, errReceiptHashMismatch
+		}
+		receipt.GasUsed -= prev.CumulativeGasUsed
+	}
+	// Fill in the metadata not included in the consensus encoding
+	receipt.TxHash = hash
+	if tx.To() == nil {
+		var signer types.Signer = types.FrontierSigner{}
+		if tx.Protected() {
+			signer = types.NewEIP155Signer(tx.ChainId())
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, nil, err
+		}
+		receipt.ContractAddress = crypto.CreateAddress(from, tx.Nonce())
+	}
+	blockHash := header.Hash()
+	for i, log := range receipt.Logs {
+		log.BlockNumber, log.BlockHash = header.Number.Uint64(), blockHash
+		log.TxHash, log.TxIndex = hash, uint(data.Index)
+		log.Index = uint(data.LogIndex) + uint(i)
+	}
+	return tx, receipt, nil
+}
+
+// verifyReceipt retrieves a single proven receipt of a block.
+func verifyReceipt(header *types.Header, index uint64, proof trie.DatabaseReader) (*types.Receipt, error) {
+	key, _ := rlp.EncodeToBytes(uint(index))
+
+	blob, err, _ := trie.VerifyProof(header.ReceiptHash, key, proof)
+	if err != nil {
+		return nil, err
+	}
+	if blob == nil {
+		return nil, errReceiptHashMismatch
+	}
+	receipt := new(types.Receipt)
+	if err := rlp.DecodeBytes(blob, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
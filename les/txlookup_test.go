@@ -0,0 +1,98 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/light"
+)
+
+// Tests that transaction lookups proven by a server are verified against the
+// included header, with the receipt metadata restored, and that tampered
+// responses are rejected.
+func TestProveTxLookup(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+
+	// Assemble a canonical block with a transfer and a contract creation
+	signer := types.HomesteadSigner{}
+	tx1, _ := types.SignTx(types.NewTransaction(0, acc1Addr, big.NewInt(1), 21000, nil, nil), signer, testBankKey)
+	tx2, _ := types.SignTx(types.NewContractCreation(1, big.NewInt(0), 100000, nil, testContractCode), signer, testBankKey)
+
+	receipt1 := types.NewReceipt(nil, false, 21000)
+	receipt1.Logs = []*types.Log{{Address: acc1Addr}}
+	receipt2 := types.NewReceipt(nil, false, 71000)
+	receipt2.Logs = []*types.Log{{Address: acc2Addr}, {Address: acc2Addr}}
+
+	txs, receipts := types.Transactions{tx1, tx2}, types.Receipts{receipt1, receipt2}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(10)}, txs, nil, receipts)
+
+	core.WriteBlock(db, block)
+	core.WriteCanonicalHash(db, block.Hash(), 10)
+	core.WriteBlockReceipts(db, block.Hash(), 10, receipts)
+	core.WriteTxLookupEntries(db, block)
+
+	// Prove and verify the contract creation
+	nodes := light.NewNodeSet()
+	data := proveTxLookup(db, tx2.Hash(), nodes)
+	if data.Header == nil || data.Header.Hash() != block.Hash() || data.Index != 1 || data.LogIndex != 1 {
+		t.Fatalf("lookup mismatch: have %+v", data)
+	}
+	proof := nodes.NodeList().NodeSet()
+	tx, receipt, err := verifyTxLookup(tx2.Hash(), data, proof)
+	if err != nil {
+		t.Fatalf("failed to verify lookup: %v", err)
+	}
+	if tx.Hash() != tx2.Hash() {
+		t.Errorf("transaction mismatch: have %x, want %x", tx.Hash(), tx2.Hash())
+	}
+	if receipt.GasUsed != 50000 || receipt.TxHash != tx2.Hash() || receipt.ContractAddress != crypto.CreateAddress(testBankAddress, 1) {
+		t.Errorf("receipt metadata mismatch: have %+v", receipt)
+	}
+	if l := receipt.Logs[1]; l.BlockHash != block.Hash() || l.BlockNumber != 10 || l.TxHash != tx2.Hash() || l.TxIndex != 1 || l.Index != 2 {
+		t.Errorf("log metadata mismatch: have %+v", l)
+	}
+	// Ensure tampered responses are rejected
+	if _, _, err := verifyTxLookup(tx1.Hash(), data, proof); err != errTxHashMismatch {
+		t.Errorf("transaction hash error mismatch: have %v, want %v", err, errTxHashMismatch)
+	}
+	tampered := data
+	tampered.Index = 0
+	if _, _, err := verifyTxLookup(tx2.Hash(), tampered, proof); err == nil {
+		t.Errorf("unproven transaction accepted")
+	}
+	// Ensure lookups outside the local canonical chain are rejected
+	msg := &Msg{MsgType: MsgTxLookups, Obj: &txLookupResponse{Lookups: []txLookupData{data}, Proof: nodes.NodeList()}}
+	if err := (&TxLookupRequest{Hash: tx2.Hash()}).Validate(db, msg); err != nil {
+		t.Errorf("canonical lookup rejected: %v", err)
+	}
+	core.WriteCanonicalHash(db, common.Hash{0x02}, 10)
+	if err := (&TxLookupRequest{Hash: tx2.Hash()}).Validate(db, msg); err != errTxNotCanonical {
+		t.Errorf("non-canonical lookup error mismatch: have %v, want %v", err, errTxNotCanonical)
+	}
+	// Unknown transactions are answered without proofs
+	nodes = light.NewNodeSet()
+	if data := proveTxLookup(db, common.Hash{0x01}, nodes); data.Header != nil || nodes.KeyCount() != 0 {
+		t.Errorf("unknown transaction proven: %+v", data)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+var errNoTrustedServers = errors.New("no trusted servers configured for ultra light client")
+
+// ulc contains the settings of the ultra light client mode. An ultra light client
+// doesn't verify the headers it receives, it accepts a header once enough of the
+// configured trusted servers have announced it with a signature.
+type ulc struct {
+	trusted map[discover.NodeID]*discover.Node // Servers whose signed announcements are trusted
+	quorum  int                                // Number of trusted servers needed to accept a header
+}
+
+// newULC creates the ultra light client settings from the user configuration,
+// parsing the enode URLs of the trusted servers.
+func newULC(config *eth.ULCConfig) (*ulc, error) {
+	if len(config.TrustedServers) == 0 {
+		return nil, errNoTrustedServers
+	}
+	fraction := config.MinTrustedFraction
+	if fraction == 0 {
+		fraction = eth.DefaultULCMinTrustedFraction
+	}
+	if fraction < 0 || fraction > 100 {
+		return nil, fmt.Errorf("invalid trusted server fraction %d%%", fraction)
+	}
+	trusted := make(map[discover.NodeID]*discover.Node)
+	for _, url := range config.TrustedServers {
+		node, err := discover.ParseNode(url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted server %q: %v", url, err)
+		}
+		trusted[node.ID] = node
+	}
+	// Round the quorum up, a single trusted announcement is needed at least
+	quorum := (len(trusted)*fraction + 99) / 100
+	if quorum == 0 {
+		quorum = 1
+	}
+	return &ulc{trusted: trusted, quorum: quorum}, nil
+}
+
+// isTrusted reports whether the announcements of the given server are trusted.
+func (u *ulc) isTrusted(id discover.NodeID) bool {
+	_, ok := u.trusted[id]
+	return ok
+}
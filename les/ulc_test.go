@@ -0,0 +1,98 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// Tests that the quorum of trusted servers is derived correctly from the
+// configured fraction, and that invalid configurations are rejected.
+func TestULCQuorum(t *testing.T) {
+	var urls []string
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		urls = append(urls, discover.NewNode(discover.PubkeyID(&key.PublicKey), net.IP{127, 0, 0, 1}, 30303, 30303).String())
+	}
+	tests := []struct {
+		servers  int
+		fraction int
+		quorum   int
+	}{
+		{4, 0, 3}, // default fraction
+		{4, 100, 4},
+		{4, 50, 2},
+		{4, 51, 3},
+		{4, 1, 1},
+		{3, 75, 3},
+		{1, 75, 1},
+	}
+	for i, tt := range tests {
+		u, err := newULC(&eth.ULCConfig{TrustedServers: urls[:tt.servers], MinTrustedFraction: tt.fraction})
+		if err != nil {
+			t.Fatalf("test %d: failed to create ultra light client: %v", i, err)
+		}
+		if u.quorum != tt.quorum {
+			t.Errorf("test %d: quorum mismatch: have %d, want %d", i, u.quorum, tt.quorum)
+		}
+	}
+	if _, err := newULC(&eth.ULCConfig{}); err != errNoTrustedServers {
+		t.Errorf("missing servers error mismatch: have %v, want %v", err, errNoTrustedServers)
+	}
+	if _, err := newULC(&eth.ULCConfig{TrustedServers: urls, MinTrustedFraction: 101}); err == nil {
+		t.Errorf("invalid fraction accepted")
+	}
+	if _, err := newULC(&eth.ULCConfig{TrustedServers: []string{"enode://invalid"}}); err == nil {
+		t.Errorf("invalid server URL accepted")
+	}
+}
+
+// Tests that ultra light clients only request heads announced by a quorum of
+// their trusted servers.
+func TestULCTrustedHead(t *testing.T) {
+	u := &ulc{trusted: make(map[discover.NodeID]*discover.Node), quorum: 2}
+	f := &lightFetcher{pm: &ProtocolManager{ulc: u}, peers: make(map[*peer]*fetcherPeerInfo)}
+
+	head := common.Hash{0x01}
+	announce := func(id byte, trusted bool) {
+		p := newPeer(lpv2, NetworkId, p2p.NewPeer(discover.NodeID{id}, "test", nil), nil)
+		p.trusted = trusted
+		f.peers[p] = &fetcherPeerInfo{nodeByHash: map[common.Hash]*fetcherTreeNode{head: {hash: head}}}
+	}
+	announce(1, true)
+	announce(2, false)
+	announce(3, false)
+	if f.trustedHead(head) {
+		t.Fatalf("head accepted below the trusted quorum")
+	}
+	announce(4, true)
+	if !f.trustedHead(head) {
+		t.Fatalf("head rejected at the trusted quorum")
+	}
+	// Without ultra light client mode every head is accepted
+	f.pm.ulc = nil
+	if !f.trustedHead(common.Hash{0x02}) {
+		t.Fatalf("head rejected in light client mode")
+	}
+}
@@ -128,6 +128,38 @@ func (req *ReceiptsRequest) StoreResult(db ethdb.Database) {
 	core.WriteBlockReceipts(db, req.Hash, req.Number, req.Receipts)
 }
 
+// LogsRequest is the ODR request type for retrieving the logs of a batch of
+// blocks matching a filter. The logs may cover only a prefix of the blocks.
+type LogsRequest struct {
+	OdrRequest
+	Headers   []*types.Header
+	Addresses []common.Address
+	Topics    [][]common.Hash
+	Logs      [][]*types.Log
+}
+
+// StoreResult is a no-op, filtered logs are not stored in the local database
+func (req *LogsRequest) StoreResult(db ethdb.Database) {}
+
+// TxLookupRequest is the ODR request type for retrieving the position of a
+// transaction in the chain along with its receipt
+type TxLookupRequest struct {
+	OdrRequest
+	Hash        common.Hash
+	Tx          *types.Transaction
+	Receipt     *types.Receipt
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint64
+}
+
+// StoreResult caches the proven transaction and receipt in the local database
+func (req *TxLookupRequest) StoreResult(db ethdb.Database) {
+	if req.Tx != nil {
+		writeTxLookup(db, req)
+	}
+}
+
 // ChtRequest is the ODR request type for state/storage trie entries
 type ChtRequest struct {
 	OdrRequest
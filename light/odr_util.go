@@ -24,6 +24,8 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -140,6 +142,116 @@ func GetBlockReceipts(ctx context.Context, odr OdrBackend, hash common.Hash, num
 	return r.Receipts, nil
 }
 
+// GetFilteredLogs retrieves the logs of the given blocks matching the address and
+// topic criteria, letting the network filter them. Blocks left out of a reply are
+// requested again until the logs of all of them are retrieved.
+func GetFilteredLogs(ctx context.Context, odr OdrBackend, headers []*types.Header, addresses []common.Address, topics [][]common.Hash) ([][]*types.Log, error) {
+	logs := make([][]*types.Log, 0, len(headers))
+	for len(logs) < len(headers) {
+		r := &LogsRequest{Headers: headers[len(logs):], Addresses: addresses, Topics: topics}
+		if err := odr.Retrieve(ctx, r); err != nil {
+			return nil, err
+		}
+		logs = append(logs, r.Logs...)
+	}
+	return logs, nil
+}
+
+// txLookupPrefix + hash -> proven transaction lookup
+var txLookupPrefix = []byte("txLookup-")
+
+// storedTxLookup is the database representation of a proven transaction lookup.
+type storedTxLookup struct {
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint64
+	Tx          *types.Transaction
+	Receipt     *types.ReceiptForStorage
+}
+
+// writeTxLookup caches a proven transaction lookup in the local database.
+func writeTxLookup(db ethdb.Putter, req *TxLookupRequest) {
+	data, err := rlp.EncodeToBytes(&storedTxLookup{
+		BlockHash:   req.BlockHash,
+		BlockNumber: req.BlockNumber,
+		Index:       req.Index,
+		Tx:          req.Tx,
+		Receipt:     (*types.ReceiptForStorage)(req.Receipt),
+	})
+	if err != nil {
+		log.Crit("Failed to encode transaction lookup", "err", err)
+	}
+	if err := db.Put(append(txLookupPrefix, req.Hash.Bytes()...), data); err != nil {
+		log.Crit("Failed to store transaction lookup", "err", err)
+	}
+}
+
+// readTxLookup retrieves a cached transaction lookup from the local database.
+func readTxLookup(db ethdb.Database, hash common.Hash) *TxLookupRequest {
+	data, _ := db.Get(append(txLookupPrefix, hash.Bytes()...))
+	if len(data) == 0 {
+		return nil
+	}
+	var entry storedTxLookup
+	if err := rlp.DecodeBytes(data, &entry); err != nil {
+		log.Error("Invalid transaction lookup RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return &TxLookupRequest{
+		Hash:        hash,
+		Tx:          entry.Tx,
+		Receipt:     (*types.Receipt)(entry.Receipt),
+		BlockHash:   entry.BlockHash,
+		BlockNumber: entry.BlockNumber,
+		Index:       entry.Index,
+	}
+}
+
+// lookupTransaction retrieves the position of a transaction in the canonical
+// chain along with its receipt, either from the local cache or from the network.
+// Lookups pointing to blocks which are not canonical (any more) are discarded.
+func lookupTransaction(ctx context.Context, odr OdrBackend, txHash common.Hash) (*TxLookupRequest, error) {
+	r := readTxLookup(odr.Database(), txHash)
+	if r == nil {
+		r = &TxLookupRequest{Hash: txHash}
+		if err := odr.Retrieve(ctx, r); err != nil {
+			return nil, err
+		}
+		if r.Tx == nil {
+			return nil, nil
+		}
+	}
+	hash, err := GetCanonicalHash(ctx, odr, r.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if hash != r.BlockHash {
+		odr.Database().Delete(append(txLookupPrefix, txHash.Bytes()...))
+		return nil, nil
+	}
+	return r, nil
+}
+
+// GetTransaction retrieves a canonical transaction by its hash, along with its
+// position in the chain. A nil transaction is returned if it's unknown.
+func GetTransaction(ctx context.Context, odr OdrBackend, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
+	r, err := lookupTransaction(ctx, odr, txHash)
+	if r == nil {
+		return nil, common.Hash{}, 0, 0, err
+	}
+	return r.Tx, r.BlockHash, r.BlockNumber, r.Index, nil
+}
+
+// GetTransactionReceipt retrieves the receipt of a canonical transaction by its
+// hash. A nil receipt is returned if the transaction is unknown.
+func GetTransactionReceipt(ctx context.Context, odr OdrBackend, txHash common.Hash) (*types.Receipt, error) {
+	r, err := lookupTransaction(ctx, odr, txHash)
+	if r == nil {
+		return nil, err
+	}
+	return r.Receipt, nil
+}
+
 // GetBloomBits retrieves a batch of compressed bloomBits vectors belonging to the given bit index and section indexes
 func GetBloomBits(ctx context.Context, odr OdrBackend, bitIdx uint, sectionIdxList []uint64) ([][]byte, error) {
 	db := odr.Database()
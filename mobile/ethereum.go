@@ -83,6 +83,9 @@ func (p *SyncProgress) GetHighestBlock() int64  { return int64(p.progress.Highes
 func (p *SyncProgress) GetPulledStates() int64  { return int64(p.progress.PulledStates) }
 func (p *SyncProgress) GetKnownStates() int64   { return int64(p.progress.KnownStates) }
 
+func (p *SyncProgress) GetPulledStateBytes() int64 { return int64(p.progress.PulledStateBytes) }
+func (p *SyncProgress) GetResumedStates() int64    { return int64(p.progress.ResumedStates) }
+
 // Topics is a set of topic lists to filter events with.
 type Topics struct{ topics [][]common.Hash }
 
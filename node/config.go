@@ -26,6 +26,8 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/hdwallet"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	"github.com/ethereum/go-ethereum/common"
@@ -38,6 +40,7 @@ import (
 const (
 	datadirPrivateKey      = "nodekey"            // Path within the datadir to the node's private key
 	datadirDefaultKeyStore = "keystore"           // Path within the datadir to the keystore
+	keystoreHDWallets      = "hd"                 // Path within the keystore to the HD wallets
 	datadirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
@@ -82,9 +85,19 @@ type Config struct {
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool `toml:",omitempty"`
 
+	// KeyStoreKDF is the key derivation function encrypting the keys created or
+	// updated in the key store, either "scrypt" (default) or "argon2id". Keys are
+	// decrypted with whichever function they were encrypted with.
+	KeyStoreKDF string `toml:",omitempty"`
+
 	// NoUSB disables hardware wallet monitoring and connectivity.
 	NoUSB bool `toml:",omitempty"`
 
+	// ExternalSigner is the endpoint (IPC path or HTTP URL) of an external signer
+	// process. If set, all account operations are forwarded to it and the local
+	// keystore and hardware wallets are not used.
+	ExternalSigner string `toml:",omitempty"`
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -391,8 +404,35 @@ func (c *Config) AccountConfig() (int, int, string, error) {
 	return scryptN, scryptP, keydir, err
 }
 
+// AccountKDF determines the key derivation function to encrypt keys with.
+func (c *Config) AccountKDF() (keystore.KDF, error) {
+	switch c.KeyStoreKDF {
+	case "", keystore.KDFScrypt:
+		if c.UseLightweightKDF {
+			return keystore.ScryptKDF(keystore.LightScryptN, keystore.LightScryptP), nil
+		}
+		return keystore.ScryptKDF(keystore.StandardScryptN, keystore.StandardScryptP), nil
+
+	case keystore.KDFArgon2id:
+		if c.UseLightweightKDF {
+			return keystore.Argon2idKDF(keystore.LightArgon2idTime, keystore.LightArgon2idMemory), nil
+		}
+		return keystore.Argon2idKDF(keystore.StandardArgon2idTime, keystore.StandardArgon2idMemory), nil
+	}
+	return keystore.KDF{}, fmt.Errorf("unsupported key derivation function: %q", c.KeyStoreKDF)
+}
+
 func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
-	scryptN, scryptP, keydir, err := conf.AccountConfig()
+	// If an external signer is configured, it is the only account backend
+	if conf.ExternalSigner != "" {
+		log.Info("Using external signer", "url", conf.ExternalSigner)
+		extapi, err := external.NewExternalBackend(conf.ExternalSigner)
+		if err != nil {
+			return nil, "", fmt.Errorf("error connecting to external signer: %v", err)
+		}
+		return accounts.NewManager(extapi), "", nil
+	}
+	_, _, keydir, err := conf.AccountConfig()
 	var ephemeral string
 	if keydir == "" {
 		// There is no datadir.
@@ -400,6 +440,10 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 		ephemeral = keydir
 	}
 
+	if err != nil {
+		return nil, "", err
+	}
+	kdf, err := conf.AccountKDF()
 	if err != nil {
 		return nil, "", err
 	}
@@ -408,7 +452,8 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 	}
 	// Assemble the account manager and supported backends
 	backends := []accounts.Backend{
-		keystore.NewKeyStore(keydir, scryptN, scryptP),
+		keystore.NewKeyStoreWithKDF(keydir, kdf),
+		hdwallet.NewBackendWithKDF(filepath.Join(keydir, keystoreHDWallets), kdf),
 	}
 	if !conf.NoUSB {
 		// Start a USB hub for Ledger hardware wallets
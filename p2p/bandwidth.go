@@ -0,0 +1,214 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Contains the per-protocol traffic accounting and bandwidth limits.
+
+package p2p
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// BandwidthLimit is the maximum throughput of a protocol, in bytes per second.
+// The limit applies to the sum of all connected peers. A zero value means that
+// the direction is not limited.
+type BandwidthLimit struct {
+	Ingress int64
+	Egress  int64
+}
+
+// ProtocolTraffic contains the number of payload bytes exchanged with a peer
+// over a single protocol. The per-code counters are keyed by protocol relative
+// message codes.
+type ProtocolTraffic struct {
+	Ingress       uint64            `json:"ingress"`
+	Egress        uint64            `json:"egress"`
+	IngressByCode map[uint64]uint64 `json:"ingressByCode,omitempty"`
+	EgressByCode  map[uint64]uint64 `json:"egressByCode,omitempty"`
+}
+
+// protoTraffic counts the traffic of one protocol on a single connection.
+type protoTraffic struct {
+	ingress []uint64 // bytes received, indexed by message code
+	egress  []uint64 // bytes sent, indexed by message code
+}
+
+func newProtoTraffic(length uint64) *protoTraffic {
+	return &protoTraffic{ingress: make([]uint64, length), egress: make([]uint64, length)}
+}
+
+func (t *protoTraffic) addIngress(code uint64, size uint32) {
+	if t != nil && code < uint64(len(t.ingress)) {
+		atomic.AddUint64(&t.ingress[code], uint64(size))
+	}
+}
+
+func (t *protoTraffic) addEgress(code uint64, size uint32) {
+	if t != nil && code < uint64(len(t.egress)) {
+		atomic.AddUint64(&t.egress[code], uint64(size))
+	}
+}
+
+// stats returns a snapshot of the counters.
+func (t *protoTraffic) stats() ProtocolTraffic {
+	stats := ProtocolTraffic{
+		IngressByCode: make(map[uint64]uint64),
+		EgressByCode:  make(map[uint64]uint64),
+	}
+	if t == nil {
+		return stats
+	}
+	for code := range t.ingress {
+		if n := atomic.LoadUint64(&t.ingress[code]); n > 0 {
+			stats.Ingress += n
+			stats.IngressByCode[uint64(code)] = n
+		}
+		if n := atomic.LoadUint64(&t.egress[code]); n > 0 {
+			stats.Egress += n
+			stats.EgressByCode[uint64(code)] = n
+		}
+	}
+	return stats
+}
+
+// protoMeters holds the traffic meters of a protocol, shared by all peers.
+type protoMeters struct {
+	ingress, egress             gometrics.Meter
+	ingressByCode, egressByCode []gometrics.Meter
+}
+
+// newProtoMeters registers the traffic meters of the given protocols, keyed
+// by protocol name and version. It is called once when the server starts, the
+// meters being shared by all peers afterwards.
+func newProtoMeters(protocols []Protocol) map[string]*protoMeters {
+	meters := make(map[string]*protoMeters, len(protocols))
+	for _, proto := range protocols {
+		name := protoMeterName(proto)
+		if _, ok := meters[name]; ok {
+			continue
+		}
+		m := &protoMeters{
+			ingress:       metrics.NewMeter("p2p/" + name + "/InboundTraffic"),
+			egress:        metrics.NewMeter("p2p/" + name + "/OutboundTraffic"),
+			ingressByCode: make([]gometrics.Meter, proto.Length),
+			egressByCode:  make([]gometrics.Meter, proto.Length),
+		}
+		for code := uint64(0); code < proto.Length; code++ {
+			m.ingressByCode[code] = metrics.NewMeter(fmt.Sprintf("p2p/%s/InboundTraffic/%d", name, code))
+			m.egressByCode[code] = metrics.NewMeter(fmt.Sprintf("p2p/%s/OutboundTraffic/%d", name, code))
+		}
+		meters[name] = m
+	}
+	return meters
+}
+
+// protoMeterName returns the name the meters of a protocol are registered by.
+func protoMeterName(proto Protocol) string {
+	return fmt.Sprintf("%s/%d", proto.Name, proto.Version)
+}
+
+func (m *protoMeters) markIngress(code uint64, size uint32) {
+	if m != nil && code < uint64(len(m.ingressByCode)) {
+		m.ingress.Mark(int64(size))
+		m.ingressByCode[code].Mark(int64(size))
+	}
+}
+
+func (m *protoMeters) markEgress(code uint64, size uint32) {
+	if m != nil && code < uint64(len(m.egressByCode)) {
+		m.egress.Mark(int64(size))
+		m.egressByCode[code].Mark(int64(size))
+	}
+}
+
+// protoLimiter enforces the bandwidth limit of a protocol.
+type protoLimiter struct {
+	ingress, egress *rateLimiter
+}
+
+func newProtoLimiters(limits map[string]BandwidthLimit) map[string]*protoLimiter {
+	limiters := make(map[string]*protoLimiter, len(limits))
+	for name, limit := range limits {
+		limiters[name] = &protoLimiter{
+			ingress: newRateLimiter(limit.Ingress),
+			egress:  newRateLimiter(limit.Egress),
+		}
+	}
+	return limiters
+}
+
+// rateLimiter is a token bucket holding up to one second worth of traffic.
+type rateLimiter struct {
+	mu    sync.Mutex
+	rate  int64 // bytes per second
+	avail int64 // available bytes, negative if in debt
+	last  time.Time
+}
+
+// newRateLimiter creates a limiter for the given rate. It returns nil for
+// unlimited rates.
+func newRateLimiter(rate int64) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &rateLimiter{rate: rate, avail: rate}
+}
+
+// reserve takes size bytes from the bucket and returns how long the caller
+// must wait until the transfer is within the limit.
+func (l *rateLimiter) reserve(now time.Time, size uint32) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.avail += int64(now.Sub(l.last)) * l.rate / int64(time.Second)
+		if l.avail > l.rate {
+			l.avail = l.rate
+		}
+	}
+	l.last = now
+	l.avail -= int64(size)
+	if l.avail >= 0 {
+		return 0
+	}
+	return time.Duration(-l.avail * int64(time.Second) / l.rate)
+}
+
+// wait blocks until size bytes may be transferred. It returns false if the
+// given channel is closed while waiting.
+func (l *rateLimiter) wait(size uint32, closed <-chan struct{}) bool {
+	if l == nil {
+		return true
+	}
+	delay := l.reserve(time.Now(), size)
+	if delay == 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-closed:
+		return false
+	}
+}
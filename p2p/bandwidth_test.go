@@ -0,0 +1,96 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	var (
+		l   = newRateLimiter(1000)
+		now = time.Now()
+	)
+	// The first second worth of traffic passes immediately.
+	if d := l.reserve(now, 1000); d != 0 {
+		t.Fatalf("burst delayed by %v", d)
+	}
+	// Further traffic has to wait until the bucket is refilled.
+	if d := l.reserve(now, 500); d != 500*time.Millisecond {
+		t.Fatalf("wrong delay: have %v, want %v", d, 500*time.Millisecond)
+	}
+	if d := l.reserve(now.Add(500*time.Millisecond), 100); d != 100*time.Millisecond {
+		t.Fatalf("wrong delay: have %v, want %v", d, 100*time.Millisecond)
+	}
+	// Idle time doesn't accumulate beyond the burst size.
+	if d := l.reserve(now.Add(time.Hour), 1000); d != 0 {
+		t.Fatalf("burst after idle time delayed by %v", d)
+	}
+	if d := l.reserve(now.Add(time.Hour), 1); d == 0 {
+		t.Fatal("traffic beyond burst not delayed")
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	if l := newRateLimiter(0); l != nil {
+		t.Fatal("limiter created for unlimited rate")
+	}
+	var l *rateLimiter
+	if !l.wait(1<<20, nil) {
+		t.Fatal("nil limiter blocked")
+	}
+}
+
+func TestPeerTraffic(t *testing.T) {
+	done := make(chan struct{})
+	proto := Protocol{
+		Name:   "a",
+		Length: 5,
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			if err := ExpectMsg(rw, 2, []uint{1}); err != nil {
+				t.Error(err)
+			}
+			if err := SendItems(rw, 3, "foo", "bar"); err != nil {
+				t.Error(err)
+			}
+			close(done)
+			_, err := rw.ReadMsg()
+			return err
+		},
+	}
+	closer, rw, peer, _ := testPeer([]Protocol{proto})
+	defer closer()
+
+	in, _ := rlp.EncodeToBytes([]uint{1})
+	out, _ := rlp.EncodeToBytes([]string{"foo", "bar"})
+	Send(rw, baseProtocolLength+2, []uint{1})
+	if err := ExpectMsg(rw, baseProtocolLength+3, []string{"foo", "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+
+	traffic := peer.running["a"].traffic.stats()
+	if traffic.Ingress != uint64(len(in)) || traffic.IngressByCode[2] != uint64(len(in)) {
+		t.Errorf("wrong ingress traffic: %+v", traffic)
+	}
+	if traffic.Egress != uint64(len(out)) || traffic.EgressByCode[3] != uint64(len(out)) {
+		t.Errorf("wrong egress traffic: %+v", traffic)
+	}
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
@@ -61,6 +62,14 @@ var (
 	nodeDBDiscoverPing      = nodeDBDiscoverRoot + ":lastping"
 	nodeDBDiscoverPong      = nodeDBDiscoverRoot + ":lastpong"
 	nodeDBDiscoverFindFails = nodeDBDiscoverRoot + ":findfail"
+	nodeDBDiscoverRecord    = nodeDBDiscoverRoot + ":enr"
+
+	nodeDBLocalRoot = ":local"
+	nodeDBLocalSeq  = nodeDBLocalRoot + ":seq"
+
+	nodeDBReputationRoot   = ":reputation"
+	nodeDBReputationScore  = nodeDBReputationRoot + ":score"
+	nodeDBReputationBanned = nodeDBReputationRoot + ":banned"
 )
 
 // newNodeDB creates a new node database for storing and retrieving infos about
@@ -199,6 +208,30 @@ func (db *nodeDB) updateNode(node *Node) error {
 	return db.lvl.Put(makeKey(node.ID, nodeDBDiscoverRoot), blob, nil)
 }
 
+// record retrieves the signed node record of a remote node.
+func (db *nodeDB) record(id NodeID) *enr.Record {
+	blob, err := db.lvl.Get(makeKey(id, nodeDBDiscoverRecord), nil)
+	if err != nil {
+		return nil
+	}
+	record := new(enr.Record)
+	if err := rlp.DecodeBytes(blob, record); err != nil {
+		log.Error("Failed to decode node record", "err", err)
+		return nil
+	}
+	return record
+}
+
+// updateRecord inserts - potentially overwriting - the node record of a remote
+// node into the peer database.
+func (db *nodeDB) updateRecord(id NodeID, record *enr.Record) error {
+	blob, err := rlp.EncodeToBytes(record)
+	if err != nil {
+		return err
+	}
+	return db.lvl.Put(makeKey(id, nodeDBDiscoverRecord), blob, nil)
+}
+
 // deleteNode deletes all information/keys associated with a node.
 func (db *nodeDB) deleteNode(id NodeID) error {
 	deleter := db.lvl.NewIterator(util.BytesPrefix(makeKey(id, "")), nil)
@@ -260,6 +293,10 @@ func (db *nodeDB) expireNodes() error {
 			if seen := db.lastPong(id); seen.After(threshold) {
 				continue
 			}
+			// Keep banned nodes around until the ban runs out
+			if db.bannedUntil(id).After(time.Now()) {
+				continue
+			}
 		}
 		// Otherwise delete all associated information
 		db.deleteNode(id)
@@ -298,6 +335,36 @@ func (db *nodeDB) updateFindFails(id NodeID, fails int) error {
 	return db.storeInt64(makeKey(id, nodeDBDiscoverFindFails), int64(fails))
 }
 
+// score retrieves the reputation score recorded for a remote node.
+func (db *nodeDB) score(id NodeID) int {
+	return int(db.fetchInt64(makeKey(id, nodeDBReputationScore)))
+}
+
+// updateScore updates the reputation score of a remote node.
+func (db *nodeDB) updateScore(id NodeID, score int) error {
+	return db.storeInt64(makeKey(id, nodeDBReputationScore), int64(score))
+}
+
+// bannedUntil retrieves the time until which a remote node is banned.
+func (db *nodeDB) bannedUntil(id NodeID) time.Time {
+	return time.Unix(db.fetchInt64(makeKey(id, nodeDBReputationBanned)), 0)
+}
+
+// updateBannedUntil updates the time until which a remote node is banned.
+func (db *nodeDB) updateBannedUntil(id NodeID, instance time.Time) error {
+	return db.storeInt64(makeKey(id, nodeDBReputationBanned), instance.Unix())
+}
+
+// localSeq retrieves the sequence number of the last local node record.
+func (db *nodeDB) localSeq() uint64 {
+	return uint64(db.fetchInt64(makeKey(db.self, nodeDBLocalSeq)))
+}
+
+// storeLocalSeq stores the sequence number of the local node record.
+func (db *nodeDB) storeLocalSeq(seq uint64) error {
+	return db.storeInt64(makeKey(db.self, nodeDBLocalSeq), int64(seq))
+}
+
 // querySeeds retrieves random nodes to be used as potential seed nodes
 // for bootstrapping.
 func (db *nodeDB) querySeeds(n int, maxAge time.Duration) []*Node {
@@ -144,6 +144,26 @@ func TestNodeDBFetchStore(t *testing.T) {
 	if stored := db.findFails(node.ID); stored != num {
 		t.Errorf("find-node fails: value mismatch: have %v, want %v", stored, num)
 	}
+	// Check fetch/store operations on a node reputation score object
+	if stored := db.score(node.ID); stored != 0 {
+		t.Errorf("score: non-existing object: %v", stored)
+	}
+	if err := db.updateScore(node.ID, -num); err != nil {
+		t.Errorf("score: failed to update: %v", err)
+	}
+	if stored := db.score(node.ID); stored != -num {
+		t.Errorf("score: value mismatch: have %v, want %v", stored, -num)
+	}
+	// Check fetch/store operations on a node ban object
+	if stored := db.bannedUntil(node.ID); stored.Unix() != 0 {
+		t.Errorf("ban: non-existing object: %v", stored)
+	}
+	if err := db.updateBannedUntil(node.ID, inst); err != nil {
+		t.Errorf("ban: failed to update: %v", err)
+	}
+	if stored := db.bannedUntil(node.ID); stored.Unix() != inst.Unix() {
+		t.Errorf("ban: value mismatch: have %v, want %v", stored, inst)
+	}
 	// Check fetch/store operations on an actual node object
 	if stored := db.node(node.ID); stored != nil {
 		t.Errorf("node: non-existing object: %v", stored)
@@ -0,0 +1,147 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Contains the management of the local node record (EIP-778) and the lookup of
+// records collected from remote nodes.
+
+package discover
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"net"
+
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+var errNoRecordKey = errors.New("no key to sign the local node record")
+
+// initRecord assembles and signs the initial record of the local node.
+func (tab *Table) initRecord(priv *ecdsa.PrivateKey, addr *net.UDPAddr) error {
+	tab.recordMu.Lock()
+	defer tab.recordMu.Unlock()
+
+	tab.priv = priv
+	tab.recordEntries = make(map[string]enr.Entry)
+	if ip := addr.IP.To4(); ip != nil && !ip.IsUnspecified() {
+		tab.recordEntries["ip4"] = enr.IP4(ip)
+	} else if ip := addr.IP.To16(); ip != nil && !ip.IsUnspecified() {
+		tab.recordEntries["ip6"] = enr.IP6(ip)
+	}
+	tab.recordEntries["udp"] = enr.UDP(addr.Port)
+	tab.recordEntries["tcp"] = enr.TCP(addr.Port)
+
+	return tab.signRecord()
+}
+
+// Record returns the signed record of the local node, or nil if the table is
+// not backed by a UDP transport. The returned record must not be modified.
+func (tab *Table) Record() *enr.Record {
+	tab.recordMu.Lock()
+	defer tab.recordMu.Unlock()
+
+	return tab.record
+}
+
+// UpdateRecord sets the given entries in the local node record, increments its
+// sequence number and signs it again. The new record is advertised to remote
+// nodes on the next ping.
+func (tab *Table) UpdateRecord(entries ...enr.Entry) error {
+	tab.recordMu.Lock()
+	defer tab.recordMu.Unlock()
+
+	if tab.priv == nil {
+		return errNoRecordKey
+	}
+	for _, e := range entries {
+		tab.recordEntries[e.ENRKey()] = e
+	}
+	return tab.signRecord()
+}
+
+// signRecord creates a new local node record from the current entry set, using
+// the sequence number following the last one handed out. The caller must hold
+// tab.recordMu.
+func (tab *Table) signRecord() error {
+	r := new(enr.Record)
+	for _, e := range tab.recordEntries {
+		r.Set(e)
+	}
+	// Signing increments the sequence number, so start from the persisted
+	// one to ensure records are never reissued with an older number.
+	r.SetSeq(tab.db.localSeq())
+	if err := r.Sign(tab.priv); err != nil {
+		return err
+	}
+	if err := tab.db.storeLocalSeq(r.Seq()); err != nil {
+		return err
+	}
+	tab.record = r
+	return nil
+}
+
+// recordSeq returns the sequence number of the local node record.
+func (tab *Table) recordSeq() uint64 {
+	if r := tab.Record(); r != nil {
+		return r.Seq()
+	}
+	return 0
+}
+
+// NodeRecord returns the last signed record received from the given node, or
+// nil if the node never supplied one.
+func (tab *Table) NodeRecord(id NodeID) *enr.Record {
+	return tab.db.record(id)
+}
+
+// NodeFromRecord creates a node from the endpoint and identity contained in a
+// signed node record. The record must contain an IP address and the TCP port.
+// If the record doesn't specify a UDP port, the TCP port is assumed.
+func NodeFromRecord(r *enr.Record) (*Node, error) {
+	var (
+		pubkey enr.Secp256k1
+		ip4    enr.IP4
+		ip6    enr.IP6
+		tcp    enr.TCP
+		udp    enr.UDP
+		ip     net.IP
+	)
+	if err := r.Load(&pubkey); err != nil {
+		return nil, err
+	}
+	switch {
+	case r.Load(&ip4) == nil:
+		ip = net.IP(ip4)
+	case r.Load(&ip6) == nil:
+		ip = net.IP(ip6)
+	default:
+		return nil, errors.New("record has no IP address")
+	}
+	if err := r.Load(&tcp); err != nil {
+		return nil, err
+	}
+	if err := r.Load(&udp); err != nil {
+		if !enr.IsNotFound(err) {
+			return nil, err
+		}
+		udp = enr.UDP(tcp)
+	}
+	n := NewNode(PubkeyID((*ecdsa.PublicKey)(&pubkey)), ip, uint16(udp), uint16(tcp))
+	if err := n.validateComplete(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
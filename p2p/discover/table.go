@@ -23,6 +23,7 @@
 package discover
 
 import (
+	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/binary"
 	"errors"
@@ -35,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enr"
 )
 
 const (
@@ -69,6 +71,11 @@ type Table struct {
 
 	net  transport
 	self *Node // metadata of the local node
+
+	recordMu      sync.Mutex           // protects the local node record
+	record        *enr.Record          // signed record of the local node
+	recordEntries map[string]enr.Entry // entries of the local node record
+	priv          *ecdsa.PrivateKey    // key used to sign the local node record
 }
 
 type bondproc struct {
@@ -123,6 +130,26 @@ func (tab *Table) Self() *Node {
 	return tab.self
 }
 
+// Score returns the reputation score recorded for the given node.
+func (tab *Table) Score(id NodeID) int {
+	return tab.db.score(id)
+}
+
+// UpdateScore records the reputation score of the given node.
+func (tab *Table) UpdateScore(id NodeID, score int) error {
+	return tab.db.updateScore(id, score)
+}
+
+// BannedUntil returns the time until which the given node is banned.
+func (tab *Table) BannedUntil(id NodeID) time.Time {
+	return tab.db.bannedUntil(id)
+}
+
+// UpdateBannedUntil records the time until which the given node is banned.
+func (tab *Table) UpdateBannedUntil(id NodeID, until time.Time) error {
+	return tab.db.updateBannedUntil(id, until)
+}
+
 // ReadRandomNodes fills the given slice with random nodes from the
 // table. It will not write the same node more than once. The nodes in
 // the slice are copies and can be modified by the caller.
@@ -225,6 +252,12 @@ func (tab *Table) Resolve(targetID NodeID) *Node {
 	return nil
 }
 
+// Ping checks whether the given node is alive by sending it a ping packet
+// and waiting for the reply.
+func (tab *Table) Ping(n *Node) error {
+	return tab.ping(n.ID, n.addr())
+}
+
 // Lookup performs a network search for nodes close
 // to the given target. It approaches the target by querying
 // nodes that are closer to it on each iteration.
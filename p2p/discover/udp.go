@@ -27,6 +27,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/ethereum/go-ethereum/p2p/netutil"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -44,6 +45,7 @@ var (
 	errTimeout          = errors.New("RPC timeout")
 	errClockWarp        = errors.New("reply deadline too far in the future")
 	errClosed           = errors.New("socket closed")
+	errRecordIdentity   = errors.New("node record identity mismatch")
 )
 
 // Timeouts
@@ -63,6 +65,8 @@ const (
 	pongPacket
 	findnodePacket
 	neighborsPacket
+	enrRequestPacket
+	enrResponsePacket
 )
 
 // RPC request structures
@@ -104,6 +108,21 @@ type (
 		Rest []rlp.RawValue `rlp:"tail"`
 	}
 
+	// enrRequest queries for the remote node's record (EIP-868).
+	enrRequest struct {
+		Expiration uint64
+		// Ignore additional fields (for forward compatibility).
+		Rest []rlp.RawValue `rlp:"tail"`
+	}
+
+	// enrResponse is the reply to enrRequest.
+	enrResponse struct {
+		ReplyTok []byte // Hash of the enrRequest packet.
+		Record   enr.Record
+		// Ignore additional fields (for forward compatibility).
+		Rest []rlp.RawValue `rlp:"tail"`
+	}
+
 	rpcNode struct {
 		IP  net.IP // len 4 for IPv4 or 16 for IPv6
 		UDP uint16 // for discovery protocol
@@ -145,6 +164,26 @@ func nodeToRPC(n *Node) rpcNode {
 	return rpcNode{ID: n.ID, IP: n.IP, UDP: n.UDP, TCP: n.TCP}
 }
 
+// seqToTail encodes the local record sequence number as the trailing ping
+// and pong element defined by EIP-868.
+func seqToTail(seq uint64) []rlp.RawValue {
+	if seq == 0 {
+		return nil
+	}
+	blob, _ := rlp.EncodeToBytes(seq)
+	return []rlp.RawValue{blob}
+}
+
+// seqFromTail decodes the record sequence number advertised in a ping or pong
+// packet. Packets sent by nodes without record support yield zero.
+func seqFromTail(tail []rlp.RawValue) uint64 {
+	var seq uint64
+	if len(tail) == 0 || rlp.DecodeBytes(tail[0], &seq) != nil {
+		return 0
+	}
+	return seq
+}
+
 type packet interface {
 	handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byte) error
 	name() string
@@ -254,6 +293,10 @@ func newUDP(priv *ecdsa.PrivateKey, c conn, natm nat.Interface, nodeDBPath strin
 		return nil, nil, err
 	}
 	udp.Table = tab
+	if err := tab.initRecord(priv, realaddr); err != nil {
+		tab.Close()
+		return nil, nil, err
+	}
 
 	go udp.loop()
 	go udp.readLoop()
@@ -269,14 +312,76 @@ func (t *udp) close() {
 // ping sends a ping message to the given node and waits for a reply.
 func (t *udp) ping(toid NodeID, toaddr *net.UDPAddr) error {
 	// TODO: maybe check for ReplyTo field in callback to measure RTT
-	errc := t.pending(toid, pongPacket, func(interface{}) bool { return true })
+	var seq uint64
+	errc := t.pending(toid, pongPacket, func(r interface{}) bool {
+		seq = seqFromTail(r.(*pong).Rest)
+		return true
+	})
 	t.send(toaddr, pingPacket, &ping{
 		Version:    Version,
 		From:       t.ourEndpoint,
 		To:         makeEndpoint(toaddr, 0), // TODO: maybe use known TCP port from DB
 		Expiration: uint64(time.Now().Add(expiration).Unix()),
+		Rest:       seqToTail(t.recordSeq()),
 	})
-	return <-errc
+	if err := <-errc; err != nil {
+		return err
+	}
+	t.refreshRecord(toid, toaddr, seq)
+	return nil
+}
+
+// requestENR sends an enrRequest to the given node and waits for its record.
+func (t *udp) requestENR(toid NodeID, toaddr *net.UDPAddr) (*enr.Record, error) {
+	req := &enrRequest{Expiration: uint64(time.Now().Add(expiration).Unix())}
+	packet, err := encodePacket(t.priv, enrRequestPacket, req)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		hash   = packet[:macSize]
+		record *enr.Record
+	)
+	errc := t.pending(toid, enrResponsePacket, func(r interface{}) bool {
+		reply := r.(*enrResponse)
+		if !bytes.Equal(reply.ReplyTok, hash) {
+			return false
+		}
+		record = &reply.Record
+		return true
+	})
+	t.write(toaddr, req.name(), packet)
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	// Make sure the record was actually issued by the node we asked.
+	var pubkey enr.Secp256k1
+	if err := record.Load(&pubkey); err != nil {
+		return nil, err
+	}
+	if PubkeyID((*ecdsa.PublicKey)(&pubkey)) != toid {
+		return nil, errRecordIdentity
+	}
+	return record, nil
+}
+
+// refreshRecord fetches the record of a remote node in the background if the
+// sequence number it advertised is newer than the one of the stored record.
+func (t *udp) refreshRecord(id NodeID, addr *net.UDPAddr, seq uint64) {
+	if seq == 0 {
+		return
+	}
+	if known := t.db.record(id); known != nil && known.Seq() >= seq {
+		return
+	}
+	go func() {
+		record, err := t.requestENR(id, addr)
+		if err != nil {
+			log.Trace("Failed to fetch node record", "id", id, "addr", addr, "err", err)
+			return
+		}
+		t.db.updateRecord(id, record)
+	}()
 }
 
 func (t *udp) waitping(from NodeID) error {
@@ -464,8 +569,12 @@ func (t *udp) send(toaddr *net.UDPAddr, ptype byte, req packet) error {
 	if err != nil {
 		return err
 	}
-	_, err = t.conn.WriteToUDP(packet, toaddr)
-	log.Trace(">> "+req.name(), "addr", toaddr, "err", err)
+	return t.write(toaddr, req.name(), packet)
+}
+
+func (t *udp) write(toaddr *net.UDPAddr, what string, packet []byte) error {
+	_, err := t.conn.WriteToUDP(packet, toaddr)
+	log.Trace(">> "+what, "addr", toaddr, "err", err)
 	return err
 }
 
@@ -547,6 +656,10 @@ func decodePacket(buf []byte) (packet, NodeID, []byte, error) {
 		req = new(findnode)
 	case neighborsPacket:
 		req = new(neighbors)
+	case enrRequestPacket:
+		req = new(enrRequest)
+	case enrResponsePacket:
+		req = new(enrResponse)
 	default:
 		return nil, fromID, hash, fmt.Errorf("unknown type: %d", ptype)
 	}
@@ -563,6 +676,7 @@ func (req *ping) handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byte) er
 		To:         makeEndpoint(from, req.From.TCP),
 		ReplyTok:   mac,
 		Expiration: uint64(time.Now().Add(expiration).Unix()),
+		Rest:       seqToTail(t.recordSeq()),
 	})
 	if !t.handleReply(fromID, pingPacket, req) {
 		// Note: we're ignoring the provided IP address right now
@@ -637,3 +751,34 @@ func (req *neighbors) name() string { return "NEIGHBORS/v4" }
 func expired(ts uint64) bool {
 	return time.Unix(int64(ts), 0).Before(time.Now())
 }
+
+func (req *enrRequest) handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byte) error {
+	if expired(req.Expiration) {
+		return errExpired
+	}
+	if t.db.node(fromID) == nil {
+		// No bond exists, we don't process the packet. See the comment
+		// in findnode for details.
+		return errUnknownNode
+	}
+	record := t.Record()
+	if record == nil {
+		return nil
+	}
+	t.send(from, enrResponsePacket, &enrResponse{
+		ReplyTok: mac,
+		Record:   *record,
+	})
+	return nil
+}
+
+func (req *enrRequest) name() string { return "ENRREQUEST/v4" }
+
+func (req *enrResponse) handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byte) error {
+	if !t.handleReply(fromID, enrResponsePacket, req) {
+		return errUnsolicitedReply
+	}
+	return nil
+}
+
+func (req *enrResponse) name() string { return "ENRRESPONSE/v4" }
@@ -36,6 +36,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -497,6 +498,98 @@ func TestForwardCompatibility(t *testing.T) {
 	}
 }
 
+func TestUDP_enrRequest(t *testing.T) {
+	test := newUDPTest(t)
+	defer test.table.Close()
+
+	// requests from unknown nodes are ignored.
+	test.packetIn(errUnknownNode, enrRequestPacket, &enrRequest{Expiration: futureExp})
+
+	// ensure there's a bond with the test node.
+	test.table.db.updateNode(NewNode(
+		PubkeyID(&test.remotekey.PublicKey),
+		test.remoteaddr.IP,
+		uint16(test.remoteaddr.Port),
+		99,
+	))
+	test.packetIn(nil, enrRequestPacket, &enrRequest{Expiration: futureExp})
+	test.waitPacketOut(func(p *enrResponse) {
+		reqhash := test.sent[len(test.sent)-1][:macSize]
+		if !bytes.Equal(p.ReplyTok, reqhash) {
+			t.Errorf("got enrResponse.ReplyTok %x, want %x", p.ReplyTok, reqhash)
+		}
+		if p.Record.Seq() != test.table.Record().Seq() {
+			t.Errorf("wrong record seq: got %d, want %d", p.Record.Seq(), test.table.Record().Seq())
+		}
+		var udp enr.UDP
+		if err := p.Record.Load(&udp); err != nil {
+			t.Errorf("can't load UDP port: %v", err)
+		} else if udp != enr.UDP(testLocal.UDP) {
+			t.Errorf("wrong UDP port: got %d, want %d", udp, testLocal.UDP)
+		}
+	})
+}
+
+func TestUDP_requestENR(t *testing.T) {
+	test := newUDPTest(t)
+	defer test.table.Close()
+
+	var remote enr.Record
+	remote.Set(enr.UDP(test.remoteaddr.Port))
+	if err := remote.Sign(test.remotekey); err != nil {
+		t.Fatal(err)
+	}
+	rid := PubkeyID(&test.remotekey.PublicKey)
+
+	// the pong advertises a record, which is then requested.
+	go test.udp.ping(rid, test.remoteaddr)
+	test.waitPacketOut(func(p *ping) {
+		if seq := seqFromTail(p.Rest); seq != test.table.Record().Seq() {
+			t.Errorf("wrong advertised seq: got %d, want %d", seq, test.table.Record().Seq())
+		}
+	})
+	test.packetIn(nil, pongPacket, &pong{Expiration: futureExp, Rest: seqToTail(remote.Seq())})
+	dgram := test.pipe.waitPacketOut()
+	if p, _, _, err := decodePacket(dgram); err != nil {
+		t.Fatalf("sent packet decode error: %v", err)
+	} else if _, ok := p.(*enrRequest); !ok {
+		t.Fatalf("sent packet type mismatch, got: %T, want: *enrRequest", p)
+	}
+	test.packetIn(nil, enrResponsePacket, &enrResponse{ReplyTok: dgram[:macSize], Record: remote})
+
+	for i := 0; i < 50; i++ {
+		if stored := test.table.NodeRecord(rid); stored != nil {
+			if stored.Seq() != remote.Seq() {
+				t.Fatalf("wrong stored seq: got %d, want %d", stored.Seq(), remote.Seq())
+			}
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("record was not stored")
+}
+
+func TestTable_updateRecord(t *testing.T) {
+	test := newUDPTest(t)
+	defer test.table.Close()
+
+	seq := test.table.Record().Seq()
+	if err := test.table.UpdateRecord(enr.WithEntry("eth", uint(1))); err != nil {
+		t.Fatal(err)
+	}
+	record := test.table.Record()
+	if record.Seq() != seq+1 {
+		t.Errorf("wrong seq after update: got %d, want %d", record.Seq(), seq+1)
+	}
+	var eth uint
+	if err := record.Load(enr.WithEntry("eth", &eth)); err != nil || eth != 1 {
+		t.Errorf("entry not set: %d, %v", eth, err)
+	}
+	if stored := test.table.db.localSeq(); stored != record.Seq() {
+		t.Errorf("seq not persisted: got %d, want %d", stored, record.Seq())
+	}
+}
+
 // dgramPipe is a fake UDP socket. It queues all sent datagrams.
 type dgramPipe struct {
 	mu      *sync.Mutex
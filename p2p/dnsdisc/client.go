@@ -0,0 +1,228 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package dnsdisc implements node discovery via DNS (EIP-1459).
+//
+// Node lists are published as a merkle tree of signed node records in DNS TXT
+// records. A tree is referenced by a URL of the form
+//
+//     enrtree://<base32 compressed public key>@<domain>
+//
+// and is only accepted if its root is signed by the key contained in the URL.
+package dnsdisc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	defaultTimeout    = 5 * time.Second
+	defaultCacheLimit = 1000
+
+	// Maximum depth of followed tree links.
+	maxLinkDepth = 4
+)
+
+// Resolver is a DNS resolver that can query TXT records. It is satisfied by
+// *net.Resolver.
+type Resolver interface {
+	LookupTXT(ctx context.Context, domain string) ([]string, error)
+}
+
+// Config holds the settings of a DNS discovery client.
+type Config struct {
+	Timeout    time.Duration // timeout used for DNS lookups (default 5s)
+	CacheLimit int           // maximum number of cached tree entries (default 1000)
+	Resolver   Resolver      // the DNS resolver to use (defaults to system DNS)
+	Logger     log.Logger    // destination of client log messages
+}
+
+// Client discovers nodes by resolving DNS node trees.
+type Client struct {
+	cfg   Config
+	cache *lru.Cache
+}
+
+// NewClient creates a client.
+func NewClient(cfg Config) *Client {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.CacheLimit == 0 {
+		cfg.CacheLimit = defaultCacheLimit
+	}
+	if cfg.Resolver == nil {
+		cfg.Resolver = new(net.Resolver)
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Root()
+	}
+	cache, _ := lru.New(cfg.CacheLimit)
+	return &Client{cfg: cfg, cache: cache}
+}
+
+// SyncTree downloads and verifies the complete tree at the given URL.
+func (c *Client) SyncTree(url string) (*Tree, error) {
+	le, err := parseLink(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrtree URL: %v", err)
+	}
+	return c.syncTree(le)
+}
+
+// Nodes resolves the trees at the given URLs, including all trees linked from
+// them, and returns the contained nodes. Trees that fail to resolve are skipped.
+func (c *Client) Nodes(urls ...string) []*discover.Node {
+	var (
+		nodes   []*discover.Node
+		visited = make(map[string]bool)
+	)
+	for _, url := range urls {
+		le, err := parseLink(url)
+		if err != nil {
+			c.cfg.Logger.Warn("Invalid DNS discovery URL", "url", url, "err", err)
+			continue
+		}
+		nodes = c.collectNodes(le, visited, nodes, 0)
+	}
+	return nodes
+}
+
+func (c *Client) collectNodes(le *linkEntry, visited map[string]bool, nodes []*discover.Node, depth int) []*discover.Node {
+	if visited[le.String()] || depth > maxLinkDepth {
+		return nodes
+	}
+	visited[le.String()] = true
+
+	t, err := c.syncTree(le)
+	if err != nil {
+		c.cfg.Logger.Debug("DNS discovery tree sync failed", "tree", le.domain, "err", err)
+		return nodes
+	}
+	for _, r := range t.Nodes() {
+		n, err := discover.NodeFromRecord(r)
+		if err != nil {
+			c.cfg.Logger.Trace("Skipping invalid DNS discovery record", "tree", le.domain, "err", err)
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	for _, link := range t.Links() {
+		if sub, err := parseLink(link); err == nil {
+			nodes = c.collectNodes(sub, visited, nodes, depth+1)
+		}
+	}
+	return nodes
+}
+
+// syncTree downloads the tree referenced by the given link.
+func (c *Client) syncTree(le *linkEntry) (*Tree, error) {
+	root, err := c.resolveRoot(le)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tree{root: root, entries: make(map[string]entry)}
+	if err := c.syncBranch(t, le.domain, root.eroot); err != nil {
+		return nil, err
+	}
+	if err := c.syncBranch(t, le.domain, root.lroot); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// syncBranch downloads the entry with the given hash and all its descendants.
+func (c *Client) syncBranch(t *Tree, domain string, hash string) error {
+	e, err := c.resolveEntry(domain, hash)
+	if err != nil {
+		return err
+	}
+	t.entries[hash] = e
+	if branch, ok := e.(*branchEntry); ok {
+		for _, child := range branch.children {
+			if _, ok := t.entries[child]; ok {
+				continue
+			}
+			if err := c.syncBranch(t, domain, child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveRoot retrieves the root entry of a tree and verifies its signature.
+func (c *Client) resolveRoot(le *linkEntry) (*rootEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
+	defer cancel()
+
+	txts, err := c.cfg.Resolver.LookupTXT(ctx, le.domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, txt := range txts {
+		if strings.HasPrefix(txt, rootPrefix) {
+			root, err := parseRoot(txt)
+			if err != nil {
+				return nil, err
+			}
+			if !root.verifySignature(le.pubkey) {
+				return nil, errInvalidSig
+			}
+			return root, nil
+		}
+	}
+	return nil, fmt.Errorf("no root found at %s", le.domain)
+}
+
+// resolveEntry retrieves an entry from the cache or fetches it from the network
+// if it isn't cached. Entries are content addressed, which is verified here.
+func (c *Client) resolveEntry(domain, hash string) (entry, error) {
+	if e, ok := c.cache.Get(hash); ok {
+		return e.(entry), nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
+	defer cancel()
+
+	name := hash + "." + domain
+	txts, err := c.cfg.Resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, txt := range txts {
+		e, err := parseEntry(txt)
+		if err == errUnknownEntry {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry at %s: %v", name, err)
+		}
+		if subdomain(e) != hash {
+			return nil, fmt.Errorf("hash mismatch at %s", name)
+		}
+		c.cache.Add(hash, e)
+		return e, nil
+	}
+	return nil, fmt.Errorf("no entry found at %s", name)
+}
@@ -0,0 +1,186 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dnsdisc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+// mapResolver is a stub resolver serving TXT records from a map.
+type mapResolver map[string]string
+
+func (mr mapResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if record, ok := mr[name]; ok {
+		return []string{record}, nil
+	}
+	return nil, fmt.Errorf("%s: no such host", name)
+}
+
+func (mr mapResolver) add(m map[string]string) {
+	for k, v := range m {
+		mr[k] = v
+	}
+}
+
+func testKeys(n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	return keys
+}
+
+func testRecords(t *testing.T, keys []*ecdsa.PrivateKey) []*enr.Record {
+	records := make([]*enr.Record, len(keys))
+	for i, key := range keys {
+		r := new(enr.Record)
+		r.Set(enr.IP4(net.IP{127, 0, 0, 1}))
+		r.Set(enr.TCP(30303 + i))
+		if err := r.Sign(key); err != nil {
+			t.Fatal(err)
+		}
+		records[i] = r
+	}
+	return records
+}
+
+func makeTestTree(t *testing.T, domain string, records []*enr.Record, links []string) (*Tree, string, map[string]string) {
+	tree, err := MakeTree(1, records, links)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := tree.Sign(testKeys(1)[0], domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree, url, tree.ToTXT(domain)
+}
+
+func recordStrings(records []*enr.Record) []string {
+	s := make([]string, len(records))
+	for i, r := range records {
+		s[i] = (&enrEntry{r}).String()
+	}
+	sort.Strings(s)
+	return s
+}
+
+func TestClientSyncTree(t *testing.T) {
+	records := testRecords(t, testKeys(30))
+	tree, url, txt := makeTestTree(t, "n", records, nil)
+
+	c := NewClient(Config{Resolver: mapResolver(txt)})
+	synced, err := c.SyncTree(url)
+	if err != nil {
+		t.Fatal("sync error:", err)
+	}
+	if !reflect.DeepEqual(recordStrings(synced.Nodes()), recordStrings(records)) {
+		t.Error("wrong records in synced tree")
+	}
+	if synced.Seq() != tree.Seq() {
+		t.Errorf("wrong seq: got %d, want %d", synced.Seq(), tree.Seq())
+	}
+	if synced.Signature() != tree.Signature() {
+		t.Errorf("wrong signature")
+	}
+}
+
+func TestClientSyncTreeBadSignature(t *testing.T) {
+	_, _, txt := makeTestTree(t, "n", testRecords(t, testKeys(3)), nil)
+	_, wrongURL, _ := makeTestTree(t, "n", nil, nil)
+
+	c := NewClient(Config{Resolver: mapResolver(txt)})
+	if _, err := c.SyncTree(wrongURL); err != errInvalidSig {
+		t.Fatalf("wrong error: got %v, want %v", err, errInvalidSig)
+	}
+}
+
+func TestClientSyncTreeBadEntry(t *testing.T) {
+	_, url, txt := makeTestTree(t, "n", testRecords(t, testKeys(3)), nil)
+
+	// Replace a leaf with a different (valid) record.
+	other := (&enrEntry{testRecords(t, testKeys(1))[0]}).String()
+	for name, e := range txt {
+		if strings.HasPrefix(e, enrPrefix) {
+			txt[name] = other
+			break
+		}
+	}
+	c := NewClient(Config{Resolver: mapResolver(txt)})
+	if _, err := c.SyncTree(url); err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Fatalf("expected hash mismatch error, got %v", err)
+	}
+}
+
+func TestClientNodesLinked(t *testing.T) {
+	var (
+		keys      = testKeys(4)
+		records   = testRecords(t, keys)
+		resolver  = make(mapResolver)
+		_, l, txt = makeTestTree(t, "linked", records[2:], nil)
+	)
+	resolver.add(txt)
+	_, url, txt := makeTestTree(t, "root", records[:2], []string{l})
+	resolver.add(txt)
+
+	c := NewClient(Config{Resolver: resolver})
+	nodes := c.Nodes(url)
+	if len(nodes) != len(keys) {
+		t.Fatalf("wrong number of nodes: got %d, want %d", len(nodes), len(keys))
+	}
+	want := make(map[discover.NodeID]bool)
+	for _, key := range keys {
+		want[discover.PubkeyID(&key.PublicKey)] = true
+	}
+	for _, n := range nodes {
+		if !want[n.ID] {
+			t.Errorf("unexpected node %v", n)
+		}
+	}
+}
+
+func TestParseLink(t *testing.T) {
+	key := testKeys(1)[0]
+	url := (&linkEntry{domain: "nodes.example.org", pubkey: &key.PublicKey}).String()
+
+	le, err := parseLink(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if le.domain != "nodes.example.org" {
+		t.Errorf("wrong domain %q", le.domain)
+	}
+	if !reflect.DeepEqual(crypto.CompressPubkey(le.pubkey), crypto.CompressPubkey(&key.PublicKey)) {
+		t.Errorf("wrong public key")
+	}
+	for _, bad := range []string{"enrtree://nodes.example.org", "https://x@nodes.example.org", "enrtree://AAAA@nodes.example.org"} {
+		if _, err := parseLink(bad); err == nil {
+			t.Errorf("no error for invalid URL %q", bad)
+		}
+	}
+}
@@ -109,6 +109,9 @@ type Peer struct {
 
 	// events receives message send / receive events if set
 	events *event.Feed
+
+	// rep tracks the reputation of the remote node if set
+	rep *reputation
 }
 
 // NewPeer returns a peer for testing purposes.
@@ -155,6 +158,29 @@ func (p *Peer) Disconnect(reason DiscReason) {
 	}
 }
 
+// Score returns the reputation score of the remote node.
+func (p *Peer) Score() int {
+	if p.rep == nil {
+		return 0
+	}
+	return p.rep.score(p.ID())
+}
+
+// AdjustScore changes the reputation score of the remote node by delta. Protocol
+// handlers should report misbehaviour with a negative delta. If the score falls
+// below the server's ban threshold, the node is disconnected and temporarily
+// banned. Trusted nodes are never banned.
+func (p *Peer) AdjustScore(delta int) {
+	if p.rep == nil {
+		return
+	}
+	if p.rep.adjust(p.ID(), delta) && !p.rw.is(trustedConn) {
+		p.log.Debug("Banning p2p peer", "duration", p.rep.duration)
+		p.rep.ban(p.ID(), time.Now())
+		go p.Disconnect(DiscUselessPeer)
+	}
+}
+
 // String implements fmt.Stringer.
 func (p *Peer) String() string {
 	return fmt.Sprintf("Peer %x %v", p.rw.id[:8], p.RemoteAddr())
@@ -323,7 +349,13 @@ outer:
 					offset -= old.Length
 				}
 				// Assign the new match
-				result[cap.Name] = &protoRW{Protocol: proto, offset: offset, in: make(chan Msg), w: rw}
+				result[cap.Name] = &protoRW{
+					Protocol: proto,
+					offset:   offset,
+					in:       make(chan Msg),
+					w:        rw,
+					traffic:  newProtoTraffic(proto.Length),
+				}
 				offset += proto.Length
 
 				continue outer
@@ -378,16 +410,28 @@ type protoRW struct {
 	werr   chan<- error    // for write results
 	offset uint64
 	w      MsgWriter
+
+	traffic *protoTraffic // traffic counters of this connection
+	meters  *protoMeters  // traffic meters shared by all peers
+	limiter *protoLimiter // bandwidth limit shared by all peers, if set
 }
 
 func (rw *protoRW) WriteMsg(msg Msg) (err error) {
 	if msg.Code >= rw.Length {
 		return newPeerError(errInvalidMsgCode, "not handled")
 	}
+	if rw.limiter != nil && !rw.limiter.egress.wait(msg.Size, rw.closed) {
+		return fmt.Errorf("shutting down")
+	}
+	code := msg.Code
 	msg.Code += rw.offset
 	select {
 	case <-rw.wstart:
 		err = rw.w.WriteMsg(msg)
+		if err == nil {
+			rw.traffic.addEgress(code, msg.Size)
+			rw.meters.markEgress(code, msg.Size)
+		}
 		// Report write status back to Peer.run. It will initiate
 		// shutdown if the error is non-nil and unblock the next write
 		// otherwise. The calling protocol code should exit for errors
@@ -403,6 +447,14 @@ func (rw *protoRW) ReadMsg() (Msg, error) {
 	select {
 	case msg := <-rw.in:
 		msg.Code -= rw.offset
+		rw.traffic.addIngress(msg.Code, msg.Size)
+		rw.meters.markIngress(msg.Code, msg.Size)
+		// Delaying the delivery of the message stalls the read loop of
+		// the peer, which also throttles the remote sender.
+		if rw.limiter != nil && !rw.limiter.ingress.wait(msg.Size, rw.closed) {
+			msg.Discard()
+			return Msg{}, io.EOF
+		}
 		return msg, nil
 	case <-rw.closed:
 		return Msg{}, io.EOF
@@ -420,7 +472,8 @@ type PeerInfo struct {
 		LocalAddress  string `json:"localAddress"`  // Local endpoint of the TCP data connection
 		RemoteAddress string `json:"remoteAddress"` // Remote endpoint of the TCP data connection
 	} `json:"network"`
-	Protocols map[string]interface{} `json:"protocols"` // Sub-protocol specific metadata fields
+	Protocols map[string]interface{}     `json:"protocols"` // Sub-protocol specific metadata fields
+	Traffic   map[string]ProtocolTraffic `json:"traffic"`   // Payload bytes exchanged per sub-protocol
 }
 
 // Info gathers and returns a collection of metadata known about a peer.
@@ -436,6 +489,7 @@ func (p *Peer) Info() *PeerInfo {
 		Name:      p.Name(),
 		Caps:      caps,
 		Protocols: make(map[string]interface{}),
+		Traffic:   make(map[string]ProtocolTraffic),
 	}
 	info.Network.LocalAddress = p.LocalAddr().String()
 	info.Network.RemoteAddress = p.RemoteAddr().String()
@@ -451,6 +505,7 @@ func (p *Peer) Info() *PeerInfo {
 			}
 		}
 		info.Protocols[proto.Name] = protoInfo
+		info.Traffic[proto.Name] = proto.traffic.stats()
 	}
 	return info
 }
@@ -0,0 +1,185 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// Reputation adjustments that protocol handlers can report via Peer.AdjustScore.
+const (
+	ScoreProtocolViolation = -50 // Peer sent invalid data or broke the protocol rules
+	ScoreTimeout           = -10 // Peer failed to answer a request in time
+	ScoreUsefulResponse    = 1   // Peer delivered valid, requested data
+)
+
+const (
+	// Reputation scores are clamped into this range so that a long history of
+	// good behaviour can't be used to buy immunity from being banned.
+	minScore = -1000
+	maxScore = 100
+
+	// Default reputation score below which a node gets banned.
+	defaultBanThreshold = -100
+
+	// Default amount of time a node stays banned.
+	defaultBanDuration = 30 * time.Minute
+)
+
+// reputationStore is the persistent storage backing the reputation tracker.
+// It is implemented by discover.Table, which keeps the values in the node
+// database.
+type reputationStore interface {
+	Score(id discover.NodeID) int
+	UpdateScore(id discover.NodeID, score int) error
+	BannedUntil(id discover.NodeID) time.Time
+	UpdateBannedUntil(id discover.NodeID, until time.Time) error
+}
+
+// reputation tracks the behaviour of remote nodes and decides whether they
+// are temporarily banned.
+type reputation struct {
+	store     reputationStore
+	threshold int
+	duration  time.Duration
+
+	lock sync.Mutex // serializes read-modify-write cycles on the store
+}
+
+// newReputation creates a reputation tracker. If store is nil, scores are
+// only kept in memory.
+func newReputation(store reputationStore, threshold int, duration time.Duration) *reputation {
+	if store == nil {
+		store = newMemoryReputationStore()
+	}
+	if threshold >= 0 {
+		threshold = defaultBanThreshold
+	}
+	if duration <= 0 {
+		duration = defaultBanDuration
+	}
+	return &reputation{store: store, threshold: threshold, duration: duration}
+}
+
+// score returns the current reputation score of the given node.
+func (r *reputation) score(id discover.NodeID) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.store.Score(id)
+}
+
+// adjust changes the score of the given node by delta and reports whether the
+// new score is below the ban threshold.
+func (r *reputation) adjust(id discover.NodeID, delta int) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	score := r.store.Score(id) + delta
+	switch {
+	case score < minScore:
+		score = minScore
+	case score > maxScore:
+		score = maxScore
+	}
+	r.store.UpdateScore(id, score)
+	return score < r.threshold
+}
+
+// ban marks the given node as banned for the configured duration. The score
+// of the node is reset so it gets a fresh start after the ban expires.
+func (r *reputation) ban(id discover.NodeID, now time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.store.UpdateBannedUntil(id, now.Add(r.duration))
+	r.store.UpdateScore(id, 0)
+}
+
+// banned reports whether the given node is currently banned.
+func (r *reputation) banned(id discover.NodeID, now time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.store.BannedUntil(id).After(now)
+}
+
+// sortByScore orders the given nodes by descending reputation. Nodes with
+// equal scores retain their relative order.
+func (r *reputation) sortByScore(nodes []*discover.Node) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := &nodesByScore{nodes: nodes, scores: make([]int, len(nodes))}
+	for i, n := range nodes {
+		s.scores[i] = r.store.Score(n.ID)
+	}
+	sort.Stable(s)
+}
+
+// nodesByScore implements sort.Interface to order nodes by their reputation.
+type nodesByScore struct {
+	nodes  []*discover.Node
+	scores []int
+}
+
+func (s *nodesByScore) Len() int           { return len(s.nodes) }
+func (s *nodesByScore) Less(i, j int) bool { return s.scores[i] > s.scores[j] }
+func (s *nodesByScore) Swap(i, j int) {
+	s.nodes[i], s.nodes[j] = s.nodes[j], s.nodes[i]
+	s.scores[i], s.scores[j] = s.scores[j], s.scores[i]
+}
+
+// memoryReputationStore is a reputationStore used when the node database is
+// not available (i.e. discovery is disabled).
+type memoryReputationStore struct {
+	scores map[discover.NodeID]int
+	bans   map[discover.NodeID]time.Time
+}
+
+func newMemoryReputationStore() *memoryReputationStore {
+	return &memoryReputationStore{
+		scores: make(map[discover.NodeID]int),
+		bans:   make(map[discover.NodeID]time.Time),
+	}
+}
+
+func (s *memoryReputationStore) Score(id discover.NodeID) int {
+	return s.scores[id]
+}
+
+func (s *memoryReputationStore) UpdateScore(id discover.NodeID, score int) error {
+	if score == 0 {
+		delete(s.scores, id)
+	} else {
+		s.scores[id] = score
+	}
+	return nil
+}
+
+func (s *memoryReputationStore) BannedUntil(id discover.NodeID) time.Time {
+	return s.bans[id]
+}
+
+func (s *memoryReputationStore) UpdateBannedUntil(id discover.NodeID, until time.Time) error {
+	s.bans[id] = until
+	return nil
+}
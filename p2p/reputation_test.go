@@ -0,0 +1,108 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+func TestReputationBan(t *testing.T) {
+	var (
+		rep = newReputation(nil, -100, time.Hour)
+		id  = uintID(1)
+		now = time.Now()
+	)
+	if rep.adjust(id, ScoreProtocolViolation) {
+		t.Fatal("node below threshold after first violation")
+	}
+	if score := rep.score(id); score != ScoreProtocolViolation {
+		t.Fatalf("score mismatch: have %d, want %d", score, ScoreProtocolViolation)
+	}
+	if rep.adjust(id, ScoreProtocolViolation) {
+		t.Fatal("node below threshold while at threshold")
+	}
+	if !rep.adjust(id, ScoreTimeout) {
+		t.Fatal("node not below threshold")
+	}
+	rep.ban(id, now)
+	if !rep.banned(id, now) {
+		t.Error("node not banned")
+	}
+	if rep.banned(id, now.Add(time.Hour+time.Second)) {
+		t.Error("node still banned after ban duration")
+	}
+	if score := rep.score(id); score != 0 {
+		t.Errorf("score not reset after ban: have %d", score)
+	}
+}
+
+func TestReputationClamp(t *testing.T) {
+	rep := newReputation(nil, 0, 0)
+	id := uintID(1)
+
+	for i := 0; i < 2*maxScore; i++ {
+		rep.adjust(id, ScoreUsefulResponse)
+	}
+	if score := rep.score(id); score != maxScore {
+		t.Fatalf("score mismatch: have %d, want %d", score, maxScore)
+	}
+	// Even a perfect reputation must not protect from being banned.
+	banned := false
+	for i := 0; i < 10 && !banned; i++ {
+		banned = rep.adjust(id, ScoreProtocolViolation)
+	}
+	if !banned {
+		t.Fatal("node with maximum score could not be banned")
+	}
+}
+
+func TestReputationSort(t *testing.T) {
+	rep := newReputation(nil, 0, 0)
+	nodes := []*discover.Node{
+		{ID: uintID(1)}, {ID: uintID(2)}, {ID: uintID(3)}, {ID: uintID(4)},
+	}
+	rep.adjust(uintID(2), ScoreTimeout)
+	rep.adjust(uintID(3), ScoreUsefulResponse)
+
+	rep.sortByScore(nodes)
+	want := []discover.NodeID{uintID(3), uintID(1), uintID(4), uintID(2)}
+	for i, n := range nodes {
+		if n.ID != want[i] {
+			t.Errorf("position %d: have %x, want %x", i, n.ID[:8], want[i][:8])
+		}
+	}
+}
+
+func TestDialStateSkipsBanned(t *testing.T) {
+	var (
+		rep    = newReputation(nil, 0, 0)
+		now    = time.Now()
+		banned = &discover.Node{ID: uintID(1)}
+	)
+	rep.ban(banned.ID, now)
+
+	s := newDialState([]*discover.Node{banned}, nil, fakeTable{}, rep, 0, nil)
+	if err := s.checkDial(banned, nil); err != errBanned {
+		t.Fatalf("wrong dial check result: have %v, want %v", err, errBanned)
+	}
+	if tasks := s.newTasks(0, nil, now); len(tasks) != 0 {
+		t.Fatalf("banned static node dialed: %v", tasks)
+	}
+}
@@ -139,6 +139,32 @@ func (t *rlpx) doProtoHandshake(our *protoHandshake) (their *protoHandshake, err
 	return their, nil
 }
 
+// Handshake dials the given node and performs the encryption and protocol
+// handshakes, announcing no capabilities. It returns the client name and
+// capabilities announced by the remote node. The connection is closed
+// afterwards.
+func Handshake(prv *ecdsa.PrivateKey, n *discover.Node, name string) (clientName string, caps []Cap, err error) {
+	fd, err := net.DialTimeout("tcp", fmt.Sprintf("%v:%d", n.IP, n.TCP), defaultDialTimeout)
+	if err != nil {
+		return "", nil, err
+	}
+	t := newRLPX(fd)
+	defer t.close(DiscQuitting)
+
+	if _, err := t.doEncHandshake(prv, n); err != nil {
+		return "", nil, err
+	}
+	our := &protoHandshake{Version: baseProtocolVersion, Name: name, ID: discover.PubkeyID(&prv.PublicKey)}
+	their, err := t.doProtoHandshake(our)
+	if err != nil {
+		return "", nil, err
+	}
+	if their.ID != n.ID {
+		return "", nil, DiscUnexpectedIdentity
+	}
+	return their.Name, their.Caps, nil
+}
+
 func readProtocolHandshake(rw MsgReader, our *protoHandshake) (*protoHandshake, error) {
 	msg, err := rw.ReadMsg()
 	if err != nil {
@@ -219,6 +219,39 @@ func TestProtocolHandshake(t *testing.T) {
 	wg.Wait()
 }
 
+func TestHandshake(t *testing.T) {
+	srv := &Server{Config: Config{
+		Name:       "test-server",
+		MaxPeers:   10,
+		ListenAddr: "127.0.0.1:0",
+		PrivateKey: newkey(),
+		NoDial:     true,
+		Protocols: []Protocol{{
+			Name:    "test",
+			Version: 1,
+			Length:  1,
+			Run:     func(*Peer, MsgReadWriter) error { return nil },
+		}},
+	}}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start server: %v", err)
+	}
+	defer srv.Stop()
+
+	addr := srv.listener.Addr().(*net.TCPAddr)
+	node := discover.NewNode(srv.Self().ID, addr.IP, 0, uint16(addr.Port))
+	name, caps, err := Handshake(newkey(), node, "test-client")
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	if name != "test-server" {
+		t.Errorf("client name mismatch: have %q, want %q", name, "test-server")
+	}
+	if want := []Cap{{"test", 1}}; !reflect.DeepEqual(caps, want) {
+		t.Errorf("caps mismatch: have %v, want %v", caps, want)
+	}
+}
+
 func TestProtocolHandshakeErrors(t *testing.T) {
 	our := &protoHandshake{Version: 3, Caps: []Cap{{"foo", 2}, {"bar", 3}}, Name: "quux"}
 	tests := []struct {
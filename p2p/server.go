@@ -26,19 +26,24 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/discover"
 	"github.com/ethereum/go-ethereum/p2p/discv5"
+	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
+	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/ethereum/go-ethereum/p2p/netutil"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 const (
 	defaultDialTimeout      = 15 * time.Second
 	refreshPeersInterval    = 30 * time.Second
 	staticPeerCheckInterval = 15 * time.Second
+	dnsRefreshInterval      = 30 * time.Minute
 
 	// Maximum number of concurrently handshaking inbound connections.
 	maxAcceptConns = 50
@@ -94,6 +99,15 @@ type Config struct {
 	// protocol.
 	BootstrapNodesV5 []*discv5.Node `toml:",omitempty"`
 
+	// BandwidthLimits configures the maximum throughput of sub-protocols,
+	// keyed by protocol name. Protocols without an entry are not limited.
+	BandwidthLimits map[string]BandwidthLimit `toml:",omitempty"`
+
+	// DNSDiscovery contains the URLs of DNS node lists (enrtree://...) that are
+	// resolved periodically to find dial candidates. This is useful in networks
+	// where UDP based discovery is not available.
+	DNSDiscovery []string `toml:",omitempty"`
+
 	// Static nodes are used as pre-configured connections which are always
 	// maintained and re-connected on disconnects.
 	StaticNodes []*discover.Node
@@ -102,6 +116,15 @@ type Config struct {
 	// allowed to connect, even above the peer limit.
 	TrustedNodes []*discover.Node
 
+	// BanThreshold is the reputation score below which remote nodes are
+	// disconnected and temporarily banned. It must be negative, zero
+	// defaults to a preset value.
+	BanThreshold int `toml:",omitempty"`
+
+	// BanDuration is the amount of time a node stays banned after its
+	// reputation fell below BanThreshold. Zero defaults to a preset value.
+	BanDuration time.Duration `toml:",omitempty"`
+
 	// Connectivity can be restricted to certain IP networks.
 	// If this option is set to a non-nil value, only hosts which match one of the
 	// IP networks contained in the list are considered.
@@ -158,6 +181,10 @@ type Server struct {
 	running bool
 
 	ntab         discoverTable
+	rep          *reputation
+	limiters     map[string]*protoLimiter
+	meters       map[string]*protoMeters
+	record       *enr.Record // local node record if discovery is disabled
 	listener     net.Listener
 	ourHandshake *protoHandshake
 	lastLookup   time.Time
@@ -170,6 +197,7 @@ type Server struct {
 	quit          chan struct{}
 	addstatic     chan *discover.Node
 	removestatic  chan *discover.Node
+	dnsnodes      chan []*discover.Node
 	posthandshake chan *conn
 	addpeer       chan *conn
 	delpeer       chan peerDrop
@@ -337,6 +365,62 @@ func (srv *Server) makeSelf(listener net.Listener, ntab discoverTable) *discover
 	return ntab.Self()
 }
 
+// recordTable is implemented by discovery tables which maintain the signed
+// record of the local node.
+type recordTable interface {
+	Record() *enr.Record
+	UpdateRecord(entries ...enr.Entry) error
+}
+
+// Record returns the signed node record (EIP-778) of the local node, or nil if
+// the server is not running.
+func (srv *Server) Record() *enr.Record {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	if !srv.running {
+		return nil
+	}
+	if tab, ok := srv.ntab.(recordTable); ok {
+		return tab.Record()
+	}
+	return srv.record
+}
+
+// makeRecord assembles and signs the local node record. If the record is
+// maintained by the discovery table, only the RLPx listening port is updated.
+func (srv *Server) makeRecord() error {
+	if tab, ok := srv.ntab.(recordTable); ok {
+		if srv.listener == nil {
+			return nil
+		}
+		var (
+			port = enr.TCP(srv.listener.Addr().(*net.TCPAddr).Port)
+			have enr.TCP
+		)
+		if tab.Record().Load(&have); have == port {
+			return nil
+		}
+		return tab.UpdateRecord(port)
+	}
+	self := srv.makeSelf(srv.listener, srv.ntab)
+
+	r := new(enr.Record)
+	if ip := self.IP.To4(); ip != nil && !ip.IsUnspecified() {
+		r.Set(enr.IP4(ip))
+	} else if ip := self.IP.To16(); ip != nil && !ip.IsUnspecified() {
+		r.Set(enr.IP6(ip))
+	}
+	if self.TCP != 0 {
+		r.Set(enr.TCP(self.TCP))
+	}
+	if err := r.Sign(srv.PrivateKey); err != nil {
+		return err
+	}
+	srv.record = r
+	return nil
+}
+
 // Stop terminates the server and all active peer connections.
 // It blocks until all active connections have been closed.
 func (srv *Server) Stop() {
@@ -385,6 +469,7 @@ func (srv *Server) Start() (err error) {
 	srv.posthandshake = make(chan *conn)
 	srv.addstatic = make(chan *discover.Node)
 	srv.removestatic = make(chan *discover.Node)
+	srv.dnsnodes = make(chan []*discover.Node)
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
 
@@ -411,11 +496,20 @@ func (srv *Server) Start() (err error) {
 		srv.DiscV5 = ntab
 	}
 
+	// Persist reputation scores in the node database if discovery is running
+	var store reputationStore
+	if s, ok := srv.ntab.(reputationStore); ok {
+		store = s
+	}
+	srv.rep = newReputation(store, srv.BanThreshold, srv.BanDuration)
+	srv.limiters = newProtoLimiters(srv.BandwidthLimits)
+	srv.meters = newProtoMeters(srv.Protocols)
+
 	dynPeers := (srv.MaxPeers + 1) / 2
-	if srv.NoDiscovery {
+	if srv.NoDiscovery && len(srv.DNSDiscovery) == 0 {
 		dynPeers = 0
 	}
-	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, dynPeers, srv.NetRestrict)
+	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, srv.rep, dynPeers, srv.NetRestrict)
 
 	// handshake
 	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name, ID: discover.PubkeyID(&srv.PrivateKey.PublicKey)}
@@ -431,6 +525,15 @@ func (srv *Server) Start() (err error) {
 	if srv.NoDial && srv.ListenAddr == "" {
 		srv.log.Warn("P2P server will be useless, neither dialing nor listening")
 	}
+	if err := srv.makeRecord(); err != nil {
+		return err
+	}
+
+	if len(srv.DNSDiscovery) > 0 && !srv.NoDial {
+		client := dnsdisc.NewClient(dnsdisc.Config{Logger: srv.log})
+		srv.loopWG.Add(1)
+		go srv.dnsDiscoveryLoop(client)
+	}
 
 	srv.loopWG.Add(1)
 	go srv.run(dialer)
@@ -438,6 +541,32 @@ func (srv *Server) Start() (err error) {
 	return nil
 }
 
+// dnsDiscoveryLoop runs in its own goroutine. It periodically resolves the
+// configured DNS node lists and hands the nodes to the dialer.
+func (srv *Server) dnsDiscoveryLoop(client *dnsdisc.Client) {
+	defer srv.loopWG.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-srv.quit:
+			return
+		}
+		nodes := client.Nodes(srv.DNSDiscovery...)
+		srv.log.Debug("Resolved DNS discovery nodes", "count", len(nodes))
+		if len(nodes) > 0 {
+			select {
+			case srv.dnsnodes <- nodes:
+			case <-srv.quit:
+				return
+			}
+		}
+		timer.Reset(dnsRefreshInterval)
+	}
+}
+
 func (srv *Server) startListening() error {
 	// Launch the TCP listener.
 	listener, err := net.Listen("tcp", srv.ListenAddr)
@@ -465,6 +594,7 @@ type dialer interface {
 	taskDone(task, time.Time)
 	addStatic(*discover.Node)
 	removeStatic(*discover.Node)
+	addCandidates([]*discover.Node)
 }
 
 func (srv *Server) run(dialstate dialer) {
@@ -536,6 +666,11 @@ running:
 			if p, ok := peers[n.ID]; ok {
 				p.Disconnect(DiscRequested)
 			}
+		case nodes := <-srv.dnsnodes:
+			// This channel is used by the DNS discovery loop to
+			// supply new dial candidates.
+			srv.log.Trace("Adding DNS discovery candidates", "count", len(nodes))
+			dialstate.addCandidates(nodes)
 		case op := <-srv.peerOp:
 			// This channel is used by Peers and PeerCount.
 			op(peers)
@@ -572,6 +707,11 @@ running:
 				if srv.EnableMsgEvents {
 					p.events = &srv.peerFeed
 				}
+				p.rep = srv.rep
+				for name, proto := range p.running {
+					proto.limiter = srv.limiters[name]
+					proto.meters = srv.meters[protoMeterName(proto.Protocol)]
+				}
 				name := truncateName(c.name)
 				srv.log.Debug("Adding p2p peer", "name", name, "addr", c.fd.RemoteAddr(), "peers", len(peers)+1)
 				peers[c.id] = p
@@ -628,6 +768,8 @@ func (srv *Server) protoHandshakeChecks(peers map[discover.NodeID]*Peer, c *conn
 
 func (srv *Server) encHandshakeChecks(peers map[discover.NodeID]*Peer, c *conn) error {
 	switch {
+	case !c.is(trustedConn) && srv.rep.banned(c.id, time.Now()):
+		return DiscUselessPeer
 	case !c.is(trustedConn|staticDialedConn) && len(peers) >= srv.MaxPeers:
 		return DiscTooManyPeers
 	case peers[c.id] != nil:
@@ -824,6 +966,7 @@ type NodeInfo struct {
 	ID    string `json:"id"`    // Unique node identifier (also the encryption key)
 	Name  string `json:"name"`  // Name of the node, including client type, version, OS, custom data
 	Enode string `json:"enode"` // Enode URL for adding this peer from remote peers
+	ENR   string `json:"enr"`   // Signed node record (EIP-778), hex encoded RLP
 	IP    string `json:"ip"`    // IP address of the node
 	Ports struct {
 		Discovery int `json:"discovery"` // UDP listening port for discovery protocol
@@ -849,6 +992,12 @@ func (srv *Server) NodeInfo() *NodeInfo {
 	info.Ports.Discovery = int(node.UDP)
 	info.Ports.Listener = int(node.TCP)
 
+	if record := srv.Record(); record != nil {
+		if blob, err := rlp.EncodeToBytes(record); err == nil {
+			info.ENR = hexutil.Encode(blob)
+		}
+	}
+
 	// Gather all the running protocol infos (only once per protocol type)
 	for _, proto := range srv.Protocols {
 		if _, ok := info.Protocols[proto.Name]; !ok {
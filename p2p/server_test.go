@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto/sha3"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/p2p/enr"
 )
 
 func init() {
@@ -122,6 +123,36 @@ func TestServerListen(t *testing.T) {
 	}
 }
 
+func TestServerRecord(t *testing.T) {
+	for _, nodisc := range []bool{false, true} {
+		srv := &Server{
+			Config: Config{
+				PrivateKey:  newkey(),
+				MaxPeers:    10,
+				ListenAddr:  "127.0.0.1:0",
+				NoDiscovery: nodisc,
+			},
+		}
+		if err := srv.Start(); err != nil {
+			t.Fatalf("could not start server: %v", err)
+		}
+		record := srv.Record()
+		if record == nil {
+			t.Fatalf("nodiscovery=%t: no local record", nodisc)
+		}
+		var tcp enr.TCP
+		if err := record.Load(&tcp); err != nil {
+			t.Errorf("nodiscovery=%t: can't load TCP port: %v", nodisc, err)
+		} else if want := srv.listener.Addr().(*net.TCPAddr).Port; int(tcp) != want {
+			t.Errorf("nodiscovery=%t: wrong TCP port: got %d, want %d", nodisc, tcp, want)
+		}
+		if info := srv.NodeInfo(); info.ENR == "" {
+			t.Errorf("nodiscovery=%t: record missing from node info", nodisc)
+		}
+		srv.Stop()
+	}
+}
+
 func TestServerDial(t *testing.T) {
 	// run a one-shot TCP server to handle the connection.
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -305,6 +336,8 @@ func (tg taskgen) newTasks(running int, peers map[discover.NodeID]*Peer, now tim
 func (tg taskgen) taskDone(t task, now time.Time) {
 	tg.doneFunc(t)
 }
+func (tg taskgen) addCandidates([]*discover.Node) {
+}
 func (tg taskgen) addStatic(*discover.Node) {
 }
 func (tg taskgen) removeStatic(*discover.Node) {
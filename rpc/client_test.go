@@ -283,6 +283,37 @@ func TestClientSubscribeCustomNamespace(t *testing.T) {
 	}
 }
 
+// This test checks that subscriptions ended by the server with an error report
+// it through Err after delivering the preceding notifications.
+func TestClientSubscribeFail(t *testing.T) {
+	server := newTestServer("eth", new(NotificationTestService))
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	nc := make(chan int, 1)
+	sub, err := client.EthSubscribe(context.Background(), nc, "failSubscription", 7, "boom")
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	select {
+	case err := <-sub.Err():
+		if err == nil || err.Error() != "boom" {
+			t.Fatalf("subscription error mismatch: have %v, want %q", err, "boom")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatalf("subscription not ended within 1s after failure")
+	}
+	select {
+	case val := <-nc:
+		if val != 7 {
+			t.Fatalf("value mismatch: have %d, want %d", val, 7)
+		}
+	default:
+		t.Fatalf("notification before failure not delivered")
+	}
+}
+
 // In this test, the connection drops while EthSubscribe is
 // waiting for a response.
 func TestClientSubscribeClose(t *testing.T) {
@@ -19,6 +19,7 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
@@ -105,6 +106,21 @@ func (s *NotificationTestService) HangSubscription(ctx context.Context, val int)
 	return subscription, nil
 }
 
+func (s *NotificationTestService) FailSubscription(ctx context.Context, val int, msg string) (*Subscription, error) {
+	notifier, supported := NotifierFromContext(ctx)
+	if !supported {
+		return nil, ErrNotificationsUnsupported
+	}
+	subscription := notifier.CreateSubscription()
+
+	go func() {
+		<-subscription.Activated()
+		notifier.Notify(subscription.ID, val)
+		notifier.Fail(subscription.ID, errors.New(msg))
+	}()
+	return subscription, nil
+}
+
 func TestNotifications(t *testing.T) {
 	server := NewServer()
 	service := &NotificationTestService{}
@@ -214,7 +230,7 @@ func waitForMessages(t *testing.T, in *json.Decoder, successes chan<- jsonSucces
 				notifications <- jsonNotification{
 					Version: msg["jsonrpc"].(string),
 					Method:  msg["method"].(string),
-					Params:  jsonSubscription{params["subscription"].(string), params["result"]},
+					Params:  jsonSubscription{params["subscription"].(string), params["result"], nil},
 				}
 				continue
 			}
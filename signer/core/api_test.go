@@ -0,0 +1,181 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+)
+
+// headlessUI is a SignerUI answering the requests with preconfigured decisions.
+type headlessUI struct {
+	approve  bool
+	password string
+	signed   int
+}
+
+func (ui *headlessUI) ApproveTx(request *SignTxRequest) (SignTxResponse, error) {
+	return SignTxResponse{Transaction: request.Transaction, Approved: ui.approve, Password: ui.password}, nil
+}
+
+func (ui *headlessUI) ApproveSignData(request *SignDataRequest) (SignDataResponse, error) {
+	return SignDataResponse{Approved: ui.approve, Password: ui.password}, nil
+}
+
+func (ui *headlessUI) ApproveListing(request *ListRequest) (ListResponse, error) {
+	if !ui.approve {
+		return ListResponse{}, nil
+	}
+	return ListResponse{request.Accounts}, nil
+}
+
+func (ui *headlessUI) ApproveNewAccount(request *NewAccountRequest) (NewAccountResponse, error) {
+	return NewAccountResponse{Approved: ui.approve, Password: ui.password}, nil
+}
+
+func (ui *headlessUI) ShowError(message string)                     {}
+func (ui *headlessUI) ShowInfo(message string)                      {}
+func (ui *headlessUI) OnApprovedTx(tx ethapi.SignTransactionResult) { ui.signed++ }
+func (ui *headlessUI) OnSignerStartup(info StartupInfo)             {}
+
+// newTestSigner creates a signer API backed by a temporary keystore.
+func newTestSigner(t *testing.T) (*SignerAPI, *headlessUI, string) {
+	dir, err := ioutil.TempDir("", "signer-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	ui := &headlessUI{approve: true, password: "foobar"}
+	return NewSignerAPI(1, dir, true, ui, true), ui, dir
+}
+
+// newTestAccount creates a new account through the API and waits until the
+// account manager picked up the wallet containing it.
+func newTestAccount(t *testing.T, api *SignerAPI) accounts.Account {
+	acc, err := api.New(context.Background())
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := api.am.Find(acc); err == nil {
+			return acc
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("account %x not picked up by the manager", acc.Address)
+	return acc
+}
+
+func TestSignerAccounts(t *testing.T) {
+	api, ui, dir := newTestSigner(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	if _, err := api.List(ctx); err != nil {
+		t.Fatalf("failed to list empty keystore: %v", err)
+	}
+	acc := newTestAccount(t, api)
+	list, err := api.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list accounts: %v", err)
+	}
+	if len(list) != 1 || list[0].Address != acc.Address {
+		t.Fatalf("account list mismatch: have %v, want [%x]", list, acc.Address)
+	}
+	// Denied requests must not reveal or create anything
+	ui.approve = false
+	if _, err := api.List(ctx); err != ErrRequestDenied {
+		t.Errorf("denied listing error mismatch: have %v, want %v", err, ErrRequestDenied)
+	}
+	if _, err := api.New(ctx); err != ErrRequestDenied {
+		t.Errorf("denied creation error mismatch: have %v, want %v", err, ErrRequestDenied)
+	}
+}
+
+func TestSignerSignTransaction(t *testing.T) {
+	api, ui, dir := newTestSigner(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	acc := newTestAccount(t, api)
+	to := common.HexToAddress("0x1337")
+	args := SendTxArgs{
+		From:     acc.Address,
+		To:       &to,
+		Gas:      hexutil.Uint64(21000),
+		GasPrice: hexutil.Big(*big.NewInt(1)),
+		Value:    hexutil.Big(*big.NewInt(1)),
+	}
+	res, err := api.SignTransaction(ctx, args)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if ui.signed != 1 {
+		t.Errorf("signed transaction not reported to the UI")
+	}
+	if *res.Tx.To() != to {
+		t.Errorf("recipient mismatch: have %x, want %x", *res.Tx.To(), to)
+	}
+	// Wrong passwords and denials must both fail the signing
+	ui.password = "wrong"
+	if _, err := api.SignTransaction(ctx, args); err == nil {
+		t.Errorf("signed with invalid password")
+	}
+	ui.approve, ui.password = false, "foobar"
+	if _, err := api.SignTransaction(ctx, args); err != ErrRequestDenied {
+		t.Errorf("denied signing error mismatch: have %v, want %v", err, ErrRequestDenied)
+	}
+}
+
+func TestSignerSignData(t *testing.T) {
+	api, ui, dir := newTestSigner(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	acc := newTestAccount(t, api)
+	data := hexutil.Bytes("hello world")
+	sig, err := api.Sign(ctx, acc.Address, data)
+	if err != nil {
+		t.Fatalf("failed to sign data: %v", err)
+	}
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		t.Fatalf("invalid signature: %x", sig)
+	}
+	// Recover the signer to ensure the expected hash was signed
+	sig[64] -= 27
+	hash, _ := SignHash(data)
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		t.Fatalf("failed to recover signer: %v", err)
+	}
+	if addr := crypto.PubkeyToAddress(*pub); addr != acc.Address {
+		t.Errorf("signer mismatch: have %x, want %x", addr, acc.Address)
+	}
+	ui.approve = false
+	if _, err := api.Sign(ctx, acc.Address, data); err != ErrRequestDenied {
+		t.Errorf("denied signing error mismatch: have %v, want %v", err, ErrRequestDenied)
+	}
+}
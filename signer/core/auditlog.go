@@ -0,0 +1,97 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// AuditLogger wraps an ExternalAPI, recording every request made through it and
+// the outcome of the request into a dedicated log file.
+type AuditLogger struct {
+	log log.Logger
+	api ExternalAPI
+}
+
+// NewAuditLogger creates an ExternalAPI wrapper appending its audit trail to the
+// file at the given path.
+func NewAuditLogger(path string, api ExternalAPI) (*AuditLogger, error) {
+	l := log.New("api", "signer")
+	handler, err := log.FileHandler(path, log.LogfmtFormat())
+	if err != nil {
+		return nil, err
+	}
+	l.SetHandler(handler)
+	l.Info("Configured", "audit log", path)
+	return &AuditLogger{l, api}, nil
+}
+
+// List implements ExternalAPI, logging the request and the revealed accounts.
+func (l *AuditLogger) List(ctx context.Context) (Accounts, error) {
+	l.log.Info("List", "type", "request")
+	res, e := l.api.List(ctx)
+
+	l.log.Info("List", "type", "response", "data", res.String(), "error", e)
+	return res, e
+}
+
+// New implements ExternalAPI, logging the request and the created account.
+func (l *AuditLogger) New(ctx context.Context) (accounts.Account, error) {
+	l.log.Info("New", "type", "request")
+	res, e := l.api.New(ctx)
+
+	l.log.Info("New", "type", "response", "address", res.Address.Hex(), "error", e)
+	return res, e
+}
+
+// SignTransaction implements ExternalAPI, logging the request and the signed
+// transaction.
+func (l *AuditLogger) SignTransaction(ctx context.Context, args SendTxArgs) (*ethapi.SignTransactionResult, error) {
+	l.log.Info("SignTransaction", "type", "request", "tx", args.String())
+	res, e := l.api.SignTransaction(ctx, args)
+
+	if res != nil {
+		l.log.Info("SignTransaction", "type", "response", "data", common.Bytes2Hex(res.Raw), "error", e)
+	} else {
+		l.log.Info("SignTransaction", "type", "response", "data", res, "error", e)
+	}
+	return res, e
+}
+
+// Sign implements ExternalAPI, logging the request and the produced signature.
+func (l *AuditLogger) Sign(ctx context.Context, addr common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	l.log.Info("Sign", "type", "request", "addr", addr.Hex(), "data", common.Bytes2Hex(data))
+	b, e := l.api.Sign(ctx, addr, data)
+
+	l.log.Info("Sign", "type", "response", "data", common.Bytes2Hex(b), "error", e)
+	return b, e
+}
+
+// Version implements ExternalAPI, logging the request.
+func (l *AuditLogger) Version(ctx context.Context) (string, error) {
+	l.log.Info("Version", "type", "request")
+	data, err := l.api.Version(ctx)
+
+	l.log.Info("Version", "type", "response", "data", data, "error", err)
+	return data, err
+}
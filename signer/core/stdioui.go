@@ -0,0 +1,111 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// StdIOUI is a SignerUI forwarding the approval requests as JSON-RPC calls over
+// stdin/stdout to a parent process, which implements the actual user interface.
+type StdIOUI struct {
+	client *rpc.Client
+}
+
+// NewStdIOUI creates a user interface speaking JSON-RPC over stdin/stdout. The
+// standard output must not be used for anything else afterwards.
+func NewStdIOUI() *StdIOUI {
+	client, err := rpc.DialStdIO(context.Background())
+	if err != nil {
+		log.Crit("Could not create stdio client", "err", err)
+	}
+	return &StdIOUI{client: client}
+}
+
+// dispatch sends a request over the stdio
+func (ui *StdIOUI) dispatch(serviceMethod string, args interface{}, reply interface{}) error {
+	err := ui.client.Call(reply, serviceMethod, args)
+	if err != nil {
+		log.Info("Error", "exc", err.Error())
+	}
+	return err
+}
+
+// ApproveTx prompt the user for confirmation to request to sign Transaction
+func (ui *StdIOUI) ApproveTx(request *SignTxRequest) (SignTxResponse, error) {
+	var result SignTxResponse
+	err := ui.dispatch("ui_approveTx", request, &result)
+	return result, err
+}
+
+// ApproveSignData prompt the user for confirmation to request to sign data
+func (ui *StdIOUI) ApproveSignData(request *SignDataRequest) (SignDataResponse, error) {
+	var result SignDataResponse
+	err := ui.dispatch("ui_approveSignData", request, &result)
+	return result, err
+}
+
+// ApproveListing prompt the user for confirmation to list accounts
+func (ui *StdIOUI) ApproveListing(request *ListRequest) (ListResponse, error) {
+	var result ListResponse
+	err := ui.dispatch("ui_approveListing", request, &result)
+	return result, err
+}
+
+// ApproveNewAccount prompt the user for confirmation to create new Account
+func (ui *StdIOUI) ApproveNewAccount(request *NewAccountRequest) (NewAccountResponse, error) {
+	var result NewAccountResponse
+	err := ui.dispatch("ui_approveNewAccount", request, &result)
+	return result, err
+}
+
+// ShowError displays error message to user
+func (ui *StdIOUI) ShowError(message string) {
+	err := ui.dispatch("ui_showError", &message, nil)
+	if err != nil {
+		log.Info("Error calling 'ui_showError'", "exc", err.Error(), "msg", message)
+	}
+}
+
+// ShowInfo displays info message to user
+func (ui *StdIOUI) ShowInfo(message string) {
+	err := ui.dispatch("ui_showInfo", &message, nil)
+	if err != nil {
+		log.Info("Error calling 'ui_showInfo'", "exc", err.Error(), "msg", message)
+	}
+}
+
+// OnApprovedTx notifies the UI about a transaction having been successfully signed.
+func (ui *StdIOUI) OnApprovedTx(tx ethapi.SignTransactionResult) {
+	err := ui.dispatch("ui_onApprovedTx", tx, nil)
+	if err != nil {
+		log.Info("Error calling 'ui_onApprovedTx'", "exc", err.Error(), "tx", tx)
+	}
+}
+
+// OnSignerStartup is invoked when the signer boots, and tells the UI info about
+// external API location and version information.
+func (ui *StdIOUI) OnSignerStartup(info StartupInfo) {
+	err := ui.dispatch("ui_onSignerStartup", info, nil)
+	if err != nil {
+		log.Info("Error calling 'ui_onSignerStartup'", "exc", err.Error(), "info", info)
+	}
+}
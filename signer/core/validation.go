@@ -0,0 +1,76 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// validateTransaction does a number of checks on the supplied transaction, and
+// returns either a list of warnings, or an error, indicating that the
+// transaction should be immediately rejected.
+func validateTransaction(txargs *SendTxArgs) (*ValidationMessages, error) {
+	msgs := &ValidationMessages{}
+
+	// Prevent accidental erroneous usage of both 'input' and 'data'
+	if txargs.Data != nil && txargs.Input != nil && !bytes.Equal(*txargs.Data, *txargs.Input) {
+		return nil, errors.New(`ambiguous request: both "data" and "input" are set and are not identical`)
+	}
+	// Place data on 'data', and nil 'input'
+	var data []byte
+	if txargs.Input != nil {
+		txargs.Data = txargs.Input
+		txargs.Input = nil
+	}
+	if txargs.Data != nil {
+		data = *txargs.Data
+	}
+	if txargs.To == nil {
+		// Contract creation should contain sufficient data to deploy a contract. A
+		// typical error is omitting sender due to some quirk in the javascript call
+		// e.g. https://github.com/ethereum/go-ethereum/issues/16106.
+		if len(data) == 0 {
+			if txargs.Value.ToInt().Cmp(common.Big0) > 0 {
+				// Sending ether into black hole
+				return nil, errors.New("tx will create contract with value but empty code")
+			}
+			// No value submitted at least
+			msgs.crit("Tx will create contract with empty code!")
+		} else if len(data) < 40 { // Arbitrary limit
+			msgs.warn(fmt.Sprintf("Tx will create contract, but payload is suspiciously small (%d bytes)", len(data)))
+		}
+		return msgs, nil
+	}
+	// Not a contract creation, validate as a plain transaction
+	if *txargs.To == (common.Address{}) {
+		msgs.crit("Tx destination is the zero address!")
+	}
+	if len(data) > 0 {
+		if len(data) < 4 {
+			msgs.warn("Tx contains data, but the data is not valid ABI")
+		} else if (len(data)-4)%32 != 0 {
+			msgs.warn(fmt.Sprintf("Tx contains data, but the ABI encoding is not a multiple of 32 bytes (was %d)", len(data)-4))
+		} else {
+			msgs.info(fmt.Sprintf("Tx invokes method with selector 0x%x", data[:4]))
+		}
+	}
+	return msgs, nil
+}
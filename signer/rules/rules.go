@@ -0,0 +1,262 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package rules implements a SignerUI deciding on requests by evaluating a
+// javascript rule set, deferring the undecided requests to a fallback UI.
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/internal/jsre/deps"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/signer/core"
+	"github.com/ethereum/go-ethereum/signer/storage"
+	"github.com/robertkrimen/otto"
+)
+
+var (
+	// bigNumberJS is the library made available to the rules for arithmetic on
+	// the (hex encoded) quantities of the requests.
+	bigNumberJS = deps.MustAsset("bignumber.js")
+
+	// errRuleRejected is returned when a rule explicitly rejected a request.
+	errRuleRejected = errors.New("request rejected by rule")
+)
+
+// decision is the verdict of a rule on a single request.
+type decision int
+
+const (
+	undecided decision = iota // The rule has no opinion, defer to the next UI
+	approve                   // The rule approved the request
+	reject                    // The rule rejected the request
+)
+
+// consoleOutput is an override for the console.log and console.error methods to
+// stream the output into the configured output stream instead of stdout.
+func consoleOutput(call otto.FunctionCall) otto.Value {
+	output := []string{"JS:> "}
+	for _, argument := range call.ArgumentList {
+		output = append(output, fmt.Sprintf("%v", argument))
+	}
+	fmt.Fprintln(os.Stderr, strings.Join(output, " "))
+	return otto.Value{}
+}
+
+// RulesetUI is a SignerUI which evaluates every request against a javascript
+// rule set. Requests the rules don't decide on are passed to the next UI.
+//
+// A rule set is a javascript program defining functions named like the approval
+// methods (e.g. ApproveTx), which get the request as an object and return the
+// string "Approve" or "Reject", or anything else to leave the decision to the
+// next UI. The rules may persist state between invocations via the storage
+// object, e.g. to implement rate limits in OnApprovedTx.
+type RulesetUI struct {
+	next        core.SignerUI   // The next handler, for manual processing
+	storage     storage.Storage // Storage of the rules for state across invocations
+	credentials storage.Storage // Storage of the account passwords of auto approved requests
+	jsRules     string          // The rules to use
+}
+
+// NewRuleEvaluator creates a rule set based UI, falling back to next for the
+// undecided requests, persisting the rule state into jsbackend and retrieving
+// the account passwords from credentialsBackend.
+func NewRuleEvaluator(next core.SignerUI, jsbackend, credentialsBackend storage.Storage) (*RulesetUI, error) {
+	return &RulesetUI{
+		next:        next,
+		storage:     jsbackend,
+		credentials: credentialsBackend,
+	}, nil
+}
+
+// Init loads the javascript rule set, ensuring it can be evaluated.
+func (r *RulesetUI) Init(javascriptRules string) error {
+	if _, err := r.newVM(javascriptRules); err != nil {
+		return err
+	}
+	r.jsRules = javascriptRules
+	return nil
+}
+
+// newVM creates a fresh javascript engine with the native callbacks and the
+// given rules loaded. A new engine is used for every request, so the rules can
+// only carry state across requests explicitly via the storage.
+func (r *RulesetUI) newVM(rules string) (*otto.Otto, error) {
+	vm := otto.New()
+
+	// Set the native callbacks
+	consoleObj, _ := vm.Get("console")
+	consoleObj.Object().Set("log", consoleOutput)
+	consoleObj.Object().Set("error", consoleOutput)
+
+	storageObj, _ := vm.Object("({})")
+	storageObj.Set("put", func(call otto.FunctionCall) otto.Value {
+		key, val := call.Argument(0).String(), call.Argument(1).String()
+		r.storage.Put(key, val)
+		return otto.Value{}
+	})
+	storageObj.Set("get", func(call otto.FunctionCall) otto.Value {
+		val, _ := vm.ToValue(r.storage.Get(call.Argument(0).String()))
+		return val
+	})
+	vm.Set("storage", storageObj)
+
+	// Load the bootstrap libraries and the rules themselves
+	script, err := vm.Compile("bignumber.js", bigNumberJS)
+	if err != nil {
+		log.Warn("Failed loading libraries", "err", err)
+		return nil, err
+	}
+	if _, err := vm.Run(script); err != nil {
+		return nil, err
+	}
+	if _, err := vm.Run(rules); err != nil {
+		log.Info("Execution failed", "err", err)
+		return nil, err
+	}
+	return vm, nil
+}
+
+// execute invokes the named rule with the given argument, if the rule set
+// defines it. To insulate the rules from the Go types, the argument is passed
+// serialized into JSON and deserialized on the javascript side.
+func (r *RulesetUI) execute(jsfunc string, jsarg interface{}) (otto.Value, error) {
+	vm, err := r.newVM(r.jsRules)
+	if err != nil {
+		return otto.UndefinedValue(), err
+	}
+	blob, err := json.Marshal(jsarg)
+	if err != nil {
+		return otto.UndefinedValue(), err
+	}
+	quoted, err := json.Marshal(string(blob))
+	if err != nil {
+		return otto.UndefinedValue(), err
+	}
+	call := fmt.Sprintf("typeof %s === 'function' ? %s(JSON.parse(%s)) : undefined", jsfunc, jsfunc, quoted)
+	return vm.Run(call)
+}
+
+// checkApproval evaluates the named rule for the given request.
+func (r *RulesetUI) checkApproval(jsfunc string, jsarg interface{}) (decision, error) {
+	v, err := r.execute(jsfunc, jsarg)
+	if err != nil {
+		log.Info("Rule execution failed", "rule", jsfunc, "err", err)
+		return reject, err
+	}
+	switch result, _ := v.ToString(); result {
+	case "Approve":
+		log.Info("Operation approved by rule", "rule", jsfunc)
+		return approve, nil
+	case "Reject":
+		log.Info("Operation rejected by rule", "rule", jsfunc)
+		return reject, nil
+	}
+	return undecided, nil
+}
+
+// password retrieves the stored password of an account for auto approvals.
+func (r *RulesetUI) password(addr common.Address) string {
+	if r.credentials == nil {
+		return ""
+	}
+	return r.credentials.Get(strings.ToLower(addr.Hex()))
+}
+
+// ApproveTx implements core.SignerUI, evaluating the ApproveTx rule.
+func (r *RulesetUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, error) {
+	verdict, err := r.checkApproval("ApproveTx", request)
+	switch {
+	case err != nil:
+		return core.SignTxResponse{Approved: false}, err
+	case verdict == approve:
+		return core.SignTxResponse{
+			Transaction: request.Transaction,
+			Approved:    true,
+			Password:    r.password(request.Transaction.From),
+		}, nil
+	case verdict == reject:
+		return core.SignTxResponse{Approved: false}, errRuleRejected
+	}
+	return r.next.ApproveTx(request)
+}
+
+// ApproveSignData implements core.SignerUI, evaluating the ApproveSignData rule.
+func (r *RulesetUI) ApproveSignData(request *core.SignDataRequest) (core.SignDataResponse, error) {
+	verdict, err := r.checkApproval("ApproveSignData", request)
+	switch {
+	case err != nil:
+		return core.SignDataResponse{Approved: false}, err
+	case verdict == approve:
+		return core.SignDataResponse{Approved: true, Password: r.password(request.Address)}, nil
+	case verdict == reject:
+		return core.SignDataResponse{Approved: false}, errRuleRejected
+	}
+	return r.next.ApproveSignData(request)
+}
+
+// ApproveListing implements core.SignerUI, evaluating the ApproveListing rule.
+func (r *RulesetUI) ApproveListing(request *core.ListRequest) (core.ListResponse, error) {
+	verdict, err := r.checkApproval("ApproveListing", request)
+	switch {
+	case err != nil:
+		return core.ListResponse{}, err
+	case verdict == approve:
+		return core.ListResponse{Accounts: request.Accounts}, nil
+	case verdict == reject:
+		return core.ListResponse{}, errRuleRejected
+	}
+	return r.next.ApproveListing(request)
+}
+
+// ApproveNewAccount implements core.SignerUI. Creating accounts needs a password
+// chosen by the user, so it is always deferred to the next UI.
+func (r *RulesetUI) ApproveNewAccount(request *core.NewAccountRequest) (core.NewAccountResponse, error) {
+	return r.next.ApproveNewAccount(request)
+}
+
+// ShowError implements core.SignerUI, forwarding the message to the next UI.
+func (r *RulesetUI) ShowError(message string) {
+	log.Error(message)
+	r.next.ShowError(message)
+}
+
+// ShowInfo implements core.SignerUI, forwarding the message to the next UI.
+func (r *RulesetUI) ShowInfo(message string) {
+	log.Info(message)
+	r.next.ShowInfo(message)
+}
+
+// OnSignerStartup implements core.SignerUI, forwarding the info to the next UI.
+func (r *RulesetUI) OnSignerStartup(info core.StartupInfo) {
+	r.next.OnSignerStartup(info)
+}
+
+// OnApprovedTx implements core.SignerUI, invoking the OnApprovedTx rule to let
+// the rule set account for the signed transaction.
+func (r *RulesetUI) OnApprovedTx(tx ethapi.SignTransactionResult) {
+	if _, err := r.execute("OnApprovedTx", tx); err != nil {
+		log.Info("Rule execution failed", "rule", "OnApprovedTx", "err", err)
+	}
+	r.next.OnApprovedTx(tx)
+}
@@ -0,0 +1,197 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rules
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/signer/core"
+	"github.com/ethereum/go-ethereum/signer/storage"
+)
+
+// alwaysDenyUI is a SignerUI rejecting everything, used as the fallback of the
+// rule set to detect the requests the rules deferred.
+type alwaysDenyUI struct {
+	asked int
+}
+
+func (d *alwaysDenyUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, error) {
+	d.asked++
+	return core.SignTxResponse{Transaction: request.Transaction, Approved: false}, nil
+}
+
+func (d *alwaysDenyUI) ApproveSignData(request *core.SignDataRequest) (core.SignDataResponse, error) {
+	d.asked++
+	return core.SignDataResponse{Approved: false}, nil
+}
+
+func (d *alwaysDenyUI) ApproveListing(request *core.ListRequest) (core.ListResponse, error) {
+	d.asked++
+	return core.ListResponse{}, nil
+}
+
+func (d *alwaysDenyUI) ApproveNewAccount(request *core.NewAccountRequest) (core.NewAccountResponse, error) {
+	d.asked++
+	return core.NewAccountResponse{Approved: false}, nil
+}
+
+func (d *alwaysDenyUI) ShowError(message string)                     {}
+func (d *alwaysDenyUI) ShowInfo(message string)                      {}
+func (d *alwaysDenyUI) OnApprovedTx(tx ethapi.SignTransactionResult) {}
+func (d *alwaysDenyUI) OnSignerStartup(info core.StartupInfo)        {}
+
+// newRuleSet creates a rule evaluator loaded with the given rules, failing the
+// test if they can't be loaded.
+func newRuleSet(t *testing.T, rules string, credentials storage.Storage) (*RulesetUI, *alwaysDenyUI) {
+	next := new(alwaysDenyUI)
+	r, err := NewRuleEvaluator(next, storage.NewEphemeralStorage(), credentials)
+	if err != nil {
+		t.Fatalf("failed to create rule evaluator: %v", err)
+	}
+	if err := r.Init(rules); err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+	return r, next
+}
+
+func dummyTxRequest(value int64) *core.SignTxRequest {
+	to := common.HexToAddress("0xae967917c465db8578ca9024c205720b1a3651a9")
+	return &core.SignTxRequest{
+		Transaction: core.SendTxArgs{
+			From:     common.HexToAddress("0x000000000000000000000000000000000000dead"),
+			To:       &to,
+			Gas:      hexutil.Uint64(21000),
+			GasPrice: hexutil.Big(*big.NewInt(2000000)),
+			Value:    hexutil.Big(*big.NewInt(value)),
+			Nonce:    hexutil.Uint64(0),
+		},
+	}
+}
+
+// Tests that explicit decisions are honoured and undecided requests are passed
+// on to the next UI.
+func TestRuleDecisions(t *testing.T) {
+	js := `
+		function ApproveListing() { return "Approve" }
+		function ApproveSignData() { return "Reject" }
+		function ApproveTx(r) { if (r.transaction.to.toLowerCase() === "0xae967917c465db8578ca9024c205720b1a3651a9") { return "Approve" } }`
+
+	credentials := storage.NewEphemeralStorage()
+	credentials.Put("0x000000000000000000000000000000000000dead", "secret")
+
+	r, next := newRuleSet(t, js, credentials)
+
+	accs := []core.Account{{Typ: "Account", Address: common.HexToAddress("0x1")}}
+	list, err := r.ApproveListing(&core.ListRequest{Accounts: accs})
+	if err != nil {
+		t.Fatalf("listing failed: %v", err)
+	}
+	if len(list.Accounts) != 1 {
+		t.Errorf("listing not approved: have %d accounts, want 1", len(list.Accounts))
+	}
+	if resp, err := r.ApproveSignData(&core.SignDataRequest{}); err == nil || resp.Approved {
+		t.Errorf("data signing not rejected: approved %v, err %v", resp.Approved, err)
+	}
+	resp, err := r.ApproveTx(dummyTxRequest(1))
+	if err != nil {
+		t.Fatalf("tx approval failed: %v", err)
+	}
+	if !resp.Approved || resp.Password != "secret" {
+		t.Errorf("tx approval mismatch: have (%v, %q), want (true, \"secret\")", resp.Approved, resp.Password)
+	}
+	if next.asked != 0 {
+		t.Errorf("decided requests deferred: have %d deferrals, want 0", next.asked)
+	}
+	// Requests without a rule or without a verdict go to the fallback UI
+	if _, err := r.ApproveNewAccount(&core.NewAccountRequest{}); err != nil {
+		t.Fatalf("new account failed: %v", err)
+	}
+	req := dummyTxRequest(1)
+	other := common.HexToAddress("0x1")
+	req.Transaction.To = &other
+	if resp, err := r.ApproveTx(req); err != nil || resp.Approved {
+		t.Errorf("undecided tx mismatch: approved %v, err %v", resp.Approved, err)
+	}
+	if next.asked != 2 {
+		t.Errorf("undecided requests not deferred: have %d deferrals, want 2", next.asked)
+	}
+}
+
+// Tests that failing rules reject the request instead of deferring it.
+func TestRuleFailures(t *testing.T) {
+	next := new(alwaysDenyUI)
+	r, _ := NewRuleEvaluator(next, storage.NewEphemeralStorage(), storage.NewEphemeralStorage())
+	if err := r.Init("function ApproveTx( { return"); err == nil {
+		t.Fatalf("invalid rules accepted")
+	}
+	r, next = newRuleSet(t, `function ApproveTx(r) { return r.missing.field }`, nil)
+	if resp, err := r.ApproveTx(dummyTxRequest(1)); err == nil || resp.Approved {
+		t.Errorf("failing rule not rejected: approved %v, err %v", resp.Approved, err)
+	}
+	if next.asked != 0 {
+		t.Errorf("failing rule deferred: have %d deferrals, want 0", next.asked)
+	}
+}
+
+// Tests that rules can keep state across invocations via the storage, here by
+// limiting the amount of wei transferred in total.
+func TestRuleStorage(t *testing.T) {
+	js := `
+		function big(str) {
+			if (str.slice(0, 2) == "0x") { return new BigNumber(str.slice(2), 16) }
+			return new BigNumber(str)
+		}
+		function ApproveTx(r) {
+			var spent = big(storage.get("spent") || "0");
+			if (spent.add(big(r.transaction.value)).lessThanOrEqualTo(big("100"))) {
+				return "Approve"
+			}
+			return "Reject"
+		}
+		function OnApprovedTx(resp) {
+			var spent = big(storage.get("spent") || "0");
+			storage.put("spent", spent.add(big(resp.tx.value)).toNumber().toString());
+		}`
+
+	r, _ := newRuleSet(t, js, storage.NewEphemeralStorage())
+
+	for i, tt := range []struct {
+		value    int64
+		approved bool
+	}{
+		{60, true}, {30, true}, {20, false}, {10, true}, {1, false},
+	} {
+		req := dummyTxRequest(tt.value)
+		resp, _ := r.ApproveTx(req)
+		if resp.Approved != tt.approved {
+			t.Fatalf("tx %d: approval mismatch: have %v, want %v", i, resp.Approved, tt.approved)
+		}
+		if resp.Approved {
+			tx := types.NewTransaction(0, *req.Transaction.To, big.NewInt(tt.value), 21000, big.NewInt(1), nil)
+			r.OnApprovedTx(ethapi.SignTransactionResult{Tx: tx})
+		}
+	}
+	if spent := r.storage.Get("spent"); !strings.EqualFold(spent, "100") {
+		t.Errorf("spent amount mismatch: have %s, want 100", spent)
+	}
+}
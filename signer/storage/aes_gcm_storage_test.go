@@ -0,0 +1,104 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEncryption(t *testing.T) {
+	key := []byte("AES256Key-32Characters1234567890")
+	plaintext := []byte("exampleplaintext")
+
+	c, iv, err := encrypt(key, plaintext, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := decrypt(key, iv, c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pt, plaintext) {
+		t.Errorf("plaintext mismatch: have %x, want %x", pt, plaintext)
+	}
+}
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eth-encrypted-storage-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	stored := NewAESEncryptedStorage(filepath.Join(dir, "creds.json"), common.Hex2Bytes("0102030405060708090a0b0c0d0e0f10"))
+	stored.Put("bazonk", "foobar")
+	stored.Put("another", "value")
+
+	// Reopen the file with the same key and ensure the values are retrievable
+	reread := NewAESEncryptedStorage(filepath.Join(dir, "creds.json"), common.Hex2Bytes("0102030405060708090a0b0c0d0e0f10"))
+	if v := reread.Get("bazonk"); v != "foobar" {
+		t.Errorf("value mismatch: have %q, want %q", v, "foobar")
+	}
+	// Ensure the values are not readable with a different key
+	wrong := NewAESEncryptedStorage(filepath.Join(dir, "creds.json"), common.Hex2Bytes("f1f2f3f4f5f6f7f8f9fafbfcfdfeff00"))
+	if v := wrong.Get("bazonk"); v != "" {
+		t.Errorf("value decrypted with wrong key: %q", v)
+	}
+	// Deleting a key removes it from the file
+	reread.Put("another", "")
+	if v := stored.Get("another"); v != "" {
+		t.Errorf("deleted value retrieved: %q", v)
+	}
+}
+
+func TestSwappedKeys(t *testing.T) {
+	// It should not be possible to swap the keys/values, so that
+	// K1:V1, K2:V2 can be swapped into K1:V2, K2:V1
+	dir, err := ioutil.TempDir("", "eth-encrypted-storage-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s1 := NewAESEncryptedStorage(filepath.Join(dir, "creds.json"), common.Hex2Bytes("0102030405060708090a0b0c0d0e0f10"))
+	s1.Put("k1", "v1")
+	s1.Put("k2", "v2")
+
+	// Now make a modified copy
+	creds := make(map[string]storedCredential)
+	raw, err := ioutil.ReadFile(s1.filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(raw, &creds); err != nil {
+		t.Fatal(err)
+	}
+	creds["k1"], creds["k2"] = creds["k2"], creds["k1"]
+	if err := s1.writeEncryptedStorage(creds); err != nil {
+		t.Fatal(err)
+	}
+	if v := s1.Get("k1"); v != "" {
+		t.Errorf("swapped value decrypted: %q", v)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package storage implements the key-value stores used by the external signer
+// to persist credentials and rule state.
+package storage
+
+import "sync"
+
+// Storage is a simple string key-value store.
+type Storage interface {
+	// Put stores a value by key. An empty value deletes the key.
+	Put(key, value string)
+
+	// Get returns the previously stored value, or the empty string if it does not
+	// exist or key is of 0-length.
+	Get(key string) string
+}
+
+// EphemeralStorage is an in-memory storage that does not persist values to disk.
+// Mainly used for testing purposes.
+type EphemeralStorage struct {
+	data map[string]string
+	lock sync.Mutex
+}
+
+// NewEphemeralStorage creates an empty in-memory storage.
+func NewEphemeralStorage() *EphemeralStorage {
+	return &EphemeralStorage{data: make(map[string]string)}
+}
+
+// Put stores a value by key. An empty value deletes the key.
+func (s *EphemeralStorage) Put(key, value string) {
+	if len(key) == 0 {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(value) == 0 {
+		delete(s.data, key)
+		return
+	}
+	s.data[key] = value
+}
+
+// Get returns the previously stored value, or the empty string if it does not
+// exist or key is of 0-length.
+func (s *EphemeralStorage) Get(key string) string {
+	if len(key) == 0 {
+		return ""
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.data[key]
+}
@@ -184,12 +184,19 @@ func (self *bzz) Drop() {
 }
 
 // one cycle of the main forever loop that handles and dispatches incoming messages
-func (self *bzz) handle() error {
+func (self *bzz) handle() (err error) {
 	msg, err := self.rw.ReadMsg()
 	log.Debug(fmt.Sprintf("<- %v", msg))
 	if err != nil {
 		return err
 	}
+	// any failure past this point is caused by an invalid message,
+	// lower the reputation of the sender
+	defer func() {
+		if err != nil {
+			self.peer.AdjustScore(p2p.ScoreProtocolViolation)
+		}
+	}()
 	if msg.Size > ProtocolMaxMsgSize {
 		return fmt.Errorf("message too long: %v > %v", msg.Size, ProtocolMaxMsgSize)
 	}